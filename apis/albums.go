@@ -2,14 +2,14 @@ package apis
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"io"
+	"iter"
 	"net/http"
 	"strconv"
 
 	"github.com/alicse3/gospotify/consts"
 	"github.com/alicse3/gospotify/models"
+	"github.com/alicse3/gospotify/spotifyid"
 	"github.com/alicse3/gospotify/utils"
 )
 
@@ -17,31 +17,64 @@ import (
 type AlbumService interface {
 	// Get Spotify catalog information for a single album.
 	GetAlbum(input models.GetAlbumRequest) (*models.Album, error)
+	// GetAlbumCtx is like GetAlbum but carries the given context through to the API call.
+	GetAlbumCtx(ctx context.Context, input models.GetAlbumRequest) (*models.Album, error)
 
 	// Get Spotify catalog information for multiple albums identified by their Spotify IDs.
 	GetAlbums(input models.GetAlbumsRequest) (*models.Albums, error)
+	// GetAlbumsCtx is like GetAlbums but carries the given context through to the API call.
+	GetAlbumsCtx(ctx context.Context, input models.GetAlbumsRequest) (*models.Albums, error)
+
+	// GetAlbumsBatch gets any number of albums, splitting the IDs into chunks of the maximum size of GetAlbums which are fetched concurrently,
+	// at most concurrency chunks at a time, 0 means a default of 4. The items are in the order of the IDs, nil for the unknown ones.
+	// The failed chunks are reported in the Failures of the result, along with an error joining theirs.
+	GetAlbumsBatch(ctx context.Context, input models.GetAlbumsRequest, concurrency int) (*BatchResult[models.Album], error)
 
 	// Get Spotify catalog information about an album’s tracks. Optional parameters can be used to limit the number of tracks returned.
 	GetAlbumTracks(input models.GetAlbumTracksRequest) (*models.AlbumTracks, error)
+	// GetAlbumTracksCtx is like GetAlbumTracks but carries the given context through to the API call.
+	GetAlbumTracksCtx(ctx context.Context, input models.GetAlbumTracksRequest) (*models.AlbumTracks, error)
+
+	// AllAlbumTracks yields the album's tracks one by one, walking all the pages of GetAlbumTracks starting at input.Offset.
+	// At most maxItems items are yielded, 0 means there is no cap.
+	AllAlbumTracks(ctx context.Context, input models.GetAlbumTracksRequest, maxItems int) iter.Seq2[models.SimplifiedTrack, error]
 
 	// Get a list of the albums saved in the current Spotify user's 'Your Music' library.
 	// Authorization scopes: user-library-read
 	GetSavedAlbums(input models.GetSavedAlbumsRequest) (*models.SavedAlbums, error)
+	// GetSavedAlbumsCtx is like GetSavedAlbums but carries the given context through to the API call.
+	GetSavedAlbumsCtx(ctx context.Context, input models.GetSavedAlbumsRequest) (*models.SavedAlbums, error)
+
+	// AllSavedAlbums yields the saved albums one by one, walking all the pages of GetSavedAlbums starting at input.Offset.
+	// At most maxItems items are yielded, 0 means there is no cap.
+	AllSavedAlbums(ctx context.Context, input models.GetSavedAlbumsRequest, maxItems int) iter.Seq2[models.SavedAlbum, error]
 
 	// Save one or more albums to the current user's 'Your Music' library.
 	// Authorization scopes: user-library-modify
 	SaveAlbums(input models.SaveAlbumsRequest) error
+	// SaveAlbumsCtx is like SaveAlbums but carries the given context through to the API call.
+	SaveAlbumsCtx(ctx context.Context, input models.SaveAlbumsRequest) error
 
 	// Remove one or more albums from the current user's 'Your Music' library.
 	// Authorization scopes: user-library-modify
 	RemoveAlbums(input models.RemoveAlbumsRequest) error
+	// RemoveAlbumsCtx is like RemoveAlbums but carries the given context through to the API call.
+	RemoveAlbumsCtx(ctx context.Context, input models.RemoveAlbumsRequest) error
 
 	// Check if one or more albums is already saved in the current Spotify user's 'Your Music' library.
 	// Authorization scopes: user-library-read
 	CheckSavedAlbums(input models.CheckSavedAlbumsRequest) (*models.CheckSavedAlbums, error)
+	// CheckSavedAlbumsCtx is like CheckSavedAlbums but carries the given context through to the API call.
+	CheckSavedAlbumsCtx(ctx context.Context, input models.CheckSavedAlbumsRequest) (*models.CheckSavedAlbums, error)
 
 	// Get a list of new album releases featured in Spotify (shown, for example, on a Spotify player’s “Browse” tab).
 	GetNewReleases(input models.GetNewReleasesRequest) (*models.NewlyReleasedAlbums, error)
+	// GetNewReleasesCtx is like GetNewReleases but carries the given context through to the API call.
+	GetNewReleasesCtx(ctx context.Context, input models.GetNewReleasesRequest) (*models.NewlyReleasedAlbums, error)
+
+	// AllNewReleases yields the new album releases one by one, walking all the pages of GetNewReleases starting at input.Offset.
+	// At most maxItems items are yielded, 0 means there is no cap.
+	AllNewReleases(ctx context.Context, input models.GetNewReleasesRequest, maxItems int) iter.Seq2[models.Album, error]
 }
 
 // DefaultAlbumService is a struct that implements AlbumService interface.
@@ -56,9 +89,14 @@ func NewDefaultAlbumService(client *utils.HttpClient) *DefaultAlbumService {
 
 // GetAlbum implements the AlbumService's interface GetAlbum method.
 func (service *DefaultAlbumService) GetAlbum(input models.GetAlbumRequest) (*models.Album, error) {
+	return service.GetAlbumCtx(context.Background(), input)
+}
+
+// GetAlbumCtx implements the AlbumService's interface GetAlbumCtx method.
+func (service *DefaultAlbumService) GetAlbumCtx(ctx context.Context, input models.GetAlbumRequest) (*models.Album, error) {
 	// Validate the input
 	if input.Id == "" {
-		return nil, &utils.AppError{Status: http.StatusBadRequest, Message: consts.MsgIdRequired}
+		return nil, utils.NewError(http.StatusBadRequest, consts.MsgIdRequired, nil)
 	}
 
 	// Substitute id in the endpoint
@@ -67,77 +105,61 @@ func (service *DefaultAlbumService) GetAlbum(input models.GetAlbumRequest) (*mod
 	// Add inputs to the query parameters
 	params := map[string]string{"market": input.Market}
 
-	// Make an API call
-	res, err := service.client.Get(context.Background(), endpoint, params)
-	if err != nil {
-		return nil, &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToGetAlbum, Err: err}
-	}
-
-	// Handle Spotify API error
-	if res.StatusCode != http.StatusOK {
-		return nil, utils.ParseSpotifyError(res, utils.RegErrorType)
-	}
-
-	// Read the response body
-	data, err := io.ReadAll(res.Body)
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
-
-	// Unmarshal the response data into Album struct
-	var album models.Album
-	if err := json.Unmarshal(data, &album); err != nil {
-		return nil, err
-	}
-
-	// Return the Album
-	return &album, nil
+	// Make an API call and decode the response data into Album struct
+	return doJSON[models.Album](ctx, service.client, http.MethodGet, endpoint, params, nil, consts.MsgFailedToGetAlbum)
 }
 
 // GetAlbums implements the AlbumService's interface GetAlbums method.
 func (service *DefaultAlbumService) GetAlbums(input models.GetAlbumsRequest) (*models.Albums, error) {
+	return service.GetAlbumsCtx(context.Background(), input)
+}
+
+// GetAlbumsCtx implements the AlbumService's interface GetAlbumsCtx method.
+func (service *DefaultAlbumService) GetAlbumsCtx(ctx context.Context, input models.GetAlbumsRequest) (*models.Albums, error) {
 	// Validate the input
-	if input.Ids == "" {
-		return nil, &utils.AppError{Status: http.StatusBadRequest, Message: consts.MsgIdsRequired}
+	if err := validateIds(input.Ids); err != nil {
+		return nil, err
 	}
 
-	// Add inputs to the query parameters
-	params := map[string]string{"ids": input.Ids, "market": input.Market}
+	// Make an API call per chunk of IDs and merge the responses
+	return getChunked(input.Ids, consts.MaxAlbumIds,
+		func(ids []spotifyid.ID) (*models.Albums, error) {
+			// Add inputs to the query parameters
+			params := map[string]string{"ids": join(ids), "market": input.Market}
 
-	// Make an API call
-	res, err := service.client.Get(context.Background(), consts.EndpointAlbums, params)
-	if err != nil {
-		return nil, &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToGetAlbums, Err: err}
-	}
-
-	// Handle Spotify API error
-	if res.StatusCode != http.StatusOK {
-		return nil, utils.ParseSpotifyError(res, utils.RegErrorType)
-	}
+			// Make an API call and decode the response data into Albums struct
+			return doJSON[models.Albums](ctx, service.client, http.MethodGet, consts.EndpointAlbums, params, nil, consts.MsgFailedToGetAlbums)
+		},
+		func(result, chunk *models.Albums) {
+			result.Albums = append(result.Albums, chunk.Albums...)
+		},
+	)
+}
 
-	// Read the response body
-	data, err := io.ReadAll(res.Body)
-	if err != nil {
+// GetAlbumsBatch implements the AlbumService's interface GetAlbumsBatch method.
+func (service *DefaultAlbumService) GetAlbumsBatch(ctx context.Context, input models.GetAlbumsRequest, concurrency int) (*BatchResult[models.Album], error) {
+	// Validate the input
+	if err := validateIds(input.Ids); err != nil {
 		return nil, err
 	}
-	defer res.Body.Close()
 
-	// Unmarshal the response data into Albums struct
-	var albums models.Albums
-	if err := json.Unmarshal(data, &albums); err != nil {
-		return nil, err
-	}
+	// Fetch the chunks of IDs concurrently
+	fetch := batchFetcher[models.Album](service.client, consts.EndpointAlbums, "albums", map[string]string{"market": input.Market}, consts.MsgFailedToGetAlbums)
+	result := FetchBatch(ctx, input.Ids, consts.MaxAlbumIds, concurrency, fetch)
 
-	// Return the Albums
-	return &albums, nil
+	return result, result.Err()
 }
 
 // GetAlbumTracks implements the AlbumService's interface GetAlbumTracks method.
 func (service *DefaultAlbumService) GetAlbumTracks(input models.GetAlbumTracksRequest) (*models.AlbumTracks, error) {
+	return service.GetAlbumTracksCtx(context.Background(), input)
+}
+
+// GetAlbumTracksCtx implements the AlbumService's interface GetAlbumTracksCtx method.
+func (service *DefaultAlbumService) GetAlbumTracksCtx(ctx context.Context, input models.GetAlbumTracksRequest) (*models.AlbumTracks, error) {
 	// Validate the input
 	if input.Id == "" {
-		return nil, &utils.AppError{Status: http.StatusBadRequest, Message: consts.MsgIdRequired}
+		return nil, utils.NewError(http.StatusBadRequest, consts.MsgIdRequired, nil)
 	}
 
 	// Substitute id in the endpoint
@@ -146,184 +168,176 @@ func (service *DefaultAlbumService) GetAlbumTracks(input models.GetAlbumTracksRe
 	// Add inputs to the query parameters
 	params := map[string]string{"market": input.Market, "limit": strconv.Itoa(input.Limit), "offset": strconv.Itoa(input.Offset)}
 
-	// Make an API call
-	res, err := service.client.Get(context.Background(), endpoint, params)
-	if err != nil {
-		return nil, &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToGetTracks, Err: err}
-	}
-
-	// Handle Spotify API error
-	if res.StatusCode != http.StatusOK {
-		return nil, utils.ParseSpotifyError(res, utils.RegErrorType)
-	}
-
-	// Read the response body
-	data, err := io.ReadAll(res.Body)
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
-
-	// Unmarshal the response data into Tracks struct
-	var tracks models.AlbumTracks
-	if err := json.Unmarshal(data, &tracks); err != nil {
-		return nil, err
-	}
+	// Make an API call and decode the response data into Tracks struct
+	return doJSON[models.AlbumTracks](ctx, service.client, http.MethodGet, endpoint, params, nil, consts.MsgFailedToGetTracks)
+}
 
-	// Return the Tracks
-	return &tracks, nil
+// AllAlbumTracks implements the AlbumService's interface AllAlbumTracks method.
+func (service *DefaultAlbumService) AllAlbumTracks(ctx context.Context, input models.GetAlbumTracksRequest, maxItems int) iter.Seq2[models.SimplifiedTrack, error] {
+	// Default to the maximum page size
+	input.Limit = pageLimit(input.Limit)
+
+	// Walk the pages, fetching each one from its offset
+	return Paginate(ctx, input.Offset, maxItems,
+		func(ctx context.Context, offset int) (*models.AlbumTracks, error) {
+			input.Offset = offset
+			return service.GetAlbumTracksCtx(ctx, input)
+		},
+		func(page *models.AlbumTracks) ([]models.SimplifiedTrack, string) {
+			return page.Items, page.Next
+		},
+	)
 }
 
 // GetSavedAlbums implements the AlbumService's interface GetSavedAlbums method.
 func (service *DefaultAlbumService) GetSavedAlbums(input models.GetSavedAlbumsRequest) (*models.SavedAlbums, error) {
-	// Add inputs to the query parameters
-	params := map[string]string{"limit": strconv.Itoa(input.Limit), "offset": strconv.Itoa(input.Offset), "market": input.Market}
-
-	// Make an API call
-	res, err := service.client.Get(context.Background(), consts.EndpointMyAlbums, params)
-	if err != nil {
-		return nil, &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToGetTracks, Err: err}
-	}
-
-	// Handle Spotify API error
-	if res.StatusCode != http.StatusOK {
-		return nil, utils.ParseSpotifyError(res, utils.RegErrorType)
-	}
+	return service.GetSavedAlbumsCtx(context.Background(), input)
+}
 
-	// Read the response body
-	data, err := io.ReadAll(res.Body)
-	if err != nil {
+// GetSavedAlbumsCtx implements the AlbumService's interface GetSavedAlbumsCtx method.
+func (service *DefaultAlbumService) GetSavedAlbumsCtx(ctx context.Context, input models.GetSavedAlbumsRequest) (*models.SavedAlbums, error) {
+	// Check the granted scopes
+	if err := checkScopes(service.client, "AlbumService.GetSavedAlbums"); err != nil {
 		return nil, err
 	}
-	defer res.Body.Close()
 
-	// Unmarshal the response data into SavedAlbums struct
-	var savedAlbums models.SavedAlbums
-	if err := json.Unmarshal(data, &savedAlbums); err != nil {
-		return nil, err
-	}
+	// Add inputs to the query parameters
+	params := map[string]string{"limit": strconv.Itoa(input.Limit), "offset": strconv.Itoa(input.Offset), "market": input.Market}
 
-	// Return the SavedAlbums
-	return &savedAlbums, nil
+	// Make an API call and decode the response data into SavedAlbums struct
+	return doJSON[models.SavedAlbums](ctx, service.client, http.MethodGet, consts.EndpointMyAlbums, params, nil, consts.MsgFailedToGetTracks)
+}
+
+// AllSavedAlbums implements the AlbumService's interface AllSavedAlbums method.
+func (service *DefaultAlbumService) AllSavedAlbums(ctx context.Context, input models.GetSavedAlbumsRequest, maxItems int) iter.Seq2[models.SavedAlbum, error] {
+	// Default to the maximum page size
+	input.Limit = pageLimit(input.Limit)
+
+	// Walk the pages, fetching each one from its offset
+	return Paginate(ctx, input.Offset, maxItems,
+		func(ctx context.Context, offset int) (*models.SavedAlbums, error) {
+			input.Offset = offset
+			return service.GetSavedAlbumsCtx(ctx, input)
+		},
+		func(page *models.SavedAlbums) ([]models.SavedAlbum, string) {
+			return page.Items, page.Next
+		},
+	)
 }
 
 // SaveAlbums implements the AlbumService's interface SaveAlbums method.
 func (service *DefaultAlbumService) SaveAlbums(input models.SaveAlbumsRequest) error {
-	// Validate the input
-	if input.Ids == "" {
-		return &utils.AppError{Status: http.StatusBadRequest, Message: consts.MsgIdsRequired}
-	}
-
-	// Add inputs to the query parameters
-	params := map[string]string{"ids": input.Ids}
+	return service.SaveAlbumsCtx(context.Background(), input)
+}
 
-	// Make an API call
-	res, err := service.client.Put(context.Background(), consts.EndpointMyAlbums, nil, params, input.Body)
-	if err != nil {
-		return &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToSaveAlbums, Err: err}
+// SaveAlbumsCtx implements the AlbumService's interface SaveAlbumsCtx method.
+func (service *DefaultAlbumService) SaveAlbumsCtx(ctx context.Context, input models.SaveAlbumsRequest) error {
+	// Check the granted scopes
+	if err := checkScopes(service.client, "AlbumService.SaveAlbums"); err != nil {
+		return err
 	}
 
-	// Handle Spotify API error
-	if res.StatusCode != http.StatusOK {
-		return utils.ParseSpotifyError(res, utils.RegErrorType)
+	// Validate the input
+	if err := validateIds(input.Ids); err != nil {
+		return err
 	}
 
-	// Return the empty response
-	return nil
+	// Make an API call per chunk of IDs
+	return doChunked(input.Ids, consts.MaxAlbumIds, func(ids []spotifyid.ID) error {
+		// Add inputs to the query parameters
+		params := map[string]string{"ids": join(ids)}
+
+		// Make an API call and discard the response body
+		return doEmpty(ctx, service.client, http.MethodPut, consts.EndpointMyAlbums, params, nil, consts.MsgFailedToSaveAlbums)
+	})
 }
 
 // RemoveAlbums implements the AlbumService's interface RemoveAlbums method.
 func (service *DefaultAlbumService) RemoveAlbums(input models.RemoveAlbumsRequest) error {
-	// Validate the input
-	if input.Ids == "" {
-		return &utils.AppError{Status: http.StatusBadRequest, Message: consts.MsgIdsRequired}
-	}
-
-	// Add inputs to the query parameters
-	params := map[string]string{"ids": input.Ids}
+	return service.RemoveAlbumsCtx(context.Background(), input)
+}
 
-	// Make an API call
-	res, err := service.client.Delete(context.Background(), consts.EndpointMyAlbums, nil, params, input.Body)
-	if err != nil {
-		return &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToRemoveAlbums, Err: err}
+// RemoveAlbumsCtx implements the AlbumService's interface RemoveAlbumsCtx method.
+func (service *DefaultAlbumService) RemoveAlbumsCtx(ctx context.Context, input models.RemoveAlbumsRequest) error {
+	// Check the granted scopes
+	if err := checkScopes(service.client, "AlbumService.RemoveAlbums"); err != nil {
+		return err
 	}
 
-	// Handle Spotify API error
-	if res.StatusCode != http.StatusOK {
-		return utils.ParseSpotifyError(res, utils.RegErrorType)
+	// Validate the input
+	if err := validateIds(input.Ids); err != nil {
+		return err
 	}
 
-	// Return the empty response
-	return nil
+	// Make an API call per chunk of IDs
+	return doChunked(input.Ids, consts.MaxAlbumIds, func(ids []spotifyid.ID) error {
+		// Add inputs to the query parameters
+		params := map[string]string{"ids": join(ids)}
+
+		// Make an API call and discard the response body
+		return doEmpty(ctx, service.client, http.MethodDelete, consts.EndpointMyAlbums, params, nil, consts.MsgFailedToRemoveAlbums)
+	})
 }
 
 // CheckSavedAlbums implements the AlbumService's interface CheckSavedAlbums method.
 func (service *DefaultAlbumService) CheckSavedAlbums(input models.CheckSavedAlbumsRequest) (*models.CheckSavedAlbums, error) {
-	// Validate the input
-	if input.Ids == "" {
-		return nil, &utils.AppError{Status: http.StatusBadRequest, Message: consts.MsgIdsRequired}
-	}
-
-	// Add inputs to the query parameters
-	params := map[string]string{"ids": input.Ids}
-
-	// Make an API call
-	res, err := service.client.Get(context.Background(), consts.EndpointCheckMyAlbums, params)
-	if err != nil {
-		return nil, &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToCheckSavedAlbums, Err: err}
-	}
-
-	// Handle Spotify API error
-	if res.StatusCode != http.StatusOK {
-		return nil, utils.ParseSpotifyError(res, utils.RegErrorType)
-	}
+	return service.CheckSavedAlbumsCtx(context.Background(), input)
+}
 
-	// Read the response body
-	data, err := io.ReadAll(res.Body)
-	if err != nil {
+// CheckSavedAlbumsCtx implements the AlbumService's interface CheckSavedAlbumsCtx method.
+func (service *DefaultAlbumService) CheckSavedAlbumsCtx(ctx context.Context, input models.CheckSavedAlbumsRequest) (*models.CheckSavedAlbums, error) {
+	// Check the granted scopes
+	if err := checkScopes(service.client, "AlbumService.CheckSavedAlbums"); err != nil {
 		return nil, err
 	}
-	defer res.Body.Close()
 
-	// Unmarshal the response data into CheckSavedAlbums struct
-	var checkSavedAlbums models.CheckSavedAlbums
-	if err := json.Unmarshal(data, &checkSavedAlbums); err != nil {
+	// Validate the input
+	if err := validateIds(input.Ids); err != nil {
 		return nil, err
 	}
 
-	// Return the CheckSavedAlbums
-	return &checkSavedAlbums, nil
+	// Make an API call per chunk of IDs and merge the responses
+	return getChunked(input.Ids, consts.MaxAlbumIds,
+		func(ids []spotifyid.ID) (*models.CheckSavedAlbums, error) {
+			// Add inputs to the query parameters
+			params := map[string]string{"ids": join(ids)}
+
+			// Make an API call and decode the response data into CheckSavedAlbums struct
+			return doJSON[models.CheckSavedAlbums](ctx, service.client, http.MethodGet, consts.EndpointCheckMyAlbums, params, nil, consts.MsgFailedToCheckSavedAlbums)
+		},
+		func(result, chunk *models.CheckSavedAlbums) {
+			*result = append(*result, *chunk...)
+		},
+	)
 }
 
 // GetNewReleases implements the AlbumService's interface GetNewReleases method.
 func (service *DefaultAlbumService) GetNewReleases(input models.GetNewReleasesRequest) (*models.NewlyReleasedAlbums, error) {
+	return service.GetNewReleasesCtx(context.Background(), input)
+}
+
+// GetNewReleasesCtx implements the AlbumService's interface GetNewReleasesCtx method.
+func (service *DefaultAlbumService) GetNewReleasesCtx(ctx context.Context, input models.GetNewReleasesRequest) (*models.NewlyReleasedAlbums, error) {
 	// Add inputs to the query parameters
 	params := map[string]string{"limit": strconv.Itoa(input.Limit), "offset": strconv.Itoa(input.Offset)}
 
-	// Make an API call
-	res, err := service.client.Get(context.Background(), consts.EndpointNewReleases, params)
-	if err != nil {
-		return nil, &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToGetNewReleases, Err: err}
-	}
-
-	// Handle Spotify API error
-	if res.StatusCode != http.StatusOK {
-		return nil, utils.ParseSpotifyError(res, utils.RegErrorType)
-	}
-
-	// Read the response body
-	data, err := io.ReadAll(res.Body)
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
-
-	// Unmarshal the response data into NewlyReleasedAlbums struct
-	var newlyReleasedAlbums models.NewlyReleasedAlbums
-	if err := json.Unmarshal(data, &newlyReleasedAlbums); err != nil {
-		return nil, err
-	}
+	// Make an API call and decode the response data into NewlyReleasedAlbums struct
+	return doJSON[models.NewlyReleasedAlbums](ctx, service.client, http.MethodGet, consts.EndpointNewReleases, params, nil, consts.MsgFailedToGetNewReleases)
+}
 
-	// Return the NewlyReleasedAlbums
-	return &newlyReleasedAlbums, nil
+// AllNewReleases implements the AlbumService's interface AllNewReleases method.
+func (service *DefaultAlbumService) AllNewReleases(ctx context.Context, input models.GetNewReleasesRequest, maxItems int) iter.Seq2[models.Album, error] {
+	// Default to the maximum page size
+	input.Limit = pageLimit(input.Limit)
+
+	// Walk the pages, fetching each one from its offset
+	return Paginate(ctx, input.Offset, maxItems,
+		func(ctx context.Context, offset int) (*models.NewlyReleasedAlbums, error) {
+			input.Offset = offset
+			return service.GetNewReleasesCtx(ctx, input)
+		},
+		func(page *models.NewlyReleasedAlbums) ([]models.Album, string) {
+			return page.Albums.Items, page.Albums.Next
+		},
+	)
 }
@@ -2,14 +2,14 @@ package apis
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"io"
+	"iter"
 	"net/http"
 	"strconv"
 
 	"github.com/alicse3/gospotify/consts"
 	"github.com/alicse3/gospotify/models"
+	"github.com/alicse3/gospotify/spotifyid"
 	"github.com/alicse3/gospotify/utils"
 )
 
@@ -17,18 +17,37 @@ import (
 type ArtistService interface {
 	// Get Spotify catalog information for a single artist identified by their unique Spotify ID.
 	GetArtist(input models.GetArtistRequest) (*models.Artist, error)
+	// GetArtistCtx is like GetArtist but carries the given context through to the API call.
+	GetArtistCtx(ctx context.Context, input models.GetArtistRequest) (*models.Artist, error)
 
 	// Get Spotify catalog information for several artists based on their Spotify IDs.
 	GetArtists(input models.GetArtistsRequest) (*models.Artists, error)
+	// GetArtistsCtx is like GetArtists but carries the given context through to the API call.
+	GetArtistsCtx(ctx context.Context, input models.GetArtistsRequest) (*models.Artists, error)
+
+	// GetArtistsBatch gets any number of artists, splitting the IDs into chunks of the maximum size of GetArtists which are fetched concurrently,
+	// at most concurrency chunks at a time, 0 means a default of 4. The items are in the order of the IDs, nil for the unknown ones.
+	// The failed chunks are reported in the Failures of the result, along with an error joining theirs.
+	GetArtistsBatch(ctx context.Context, input models.GetArtistsRequest, concurrency int) (*BatchResult[models.Artist], error)
 
 	// Get Spotify catalog information about an artist's albums.
 	GetArtistAlbums(input models.GetArtistAlbumsRequest) (*models.ArtistAlbums, error)
+	// GetArtistAlbumsCtx is like GetArtistAlbums but carries the given context through to the API call.
+	GetArtistAlbumsCtx(ctx context.Context, input models.GetArtistAlbumsRequest) (*models.ArtistAlbums, error)
+
+	// AllArtistAlbums yields the artist's albums one by one, walking all the pages of GetArtistAlbums starting at input.Offset.
+	// At most maxItems items are yielded, 0 means there is no cap.
+	AllArtistAlbums(ctx context.Context, input models.GetArtistAlbumsRequest, maxItems int) iter.Seq2[models.ArtistAlbum, error]
 
 	// Get Spotify catalog information about an artist's top tracks by country.
 	GetArtistTopTracks(input models.GetArtistTopTracksRequest) (*models.ArtistTopTracks, error)
+	// GetArtistTopTracksCtx is like GetArtistTopTracks but carries the given context through to the API call.
+	GetArtistTopTracksCtx(ctx context.Context, input models.GetArtistTopTracksRequest) (*models.ArtistTopTracks, error)
 
 	// Get Spotify catalog information about artists similar to a given artist. Similarity is based on analysis of the Spotify community's listening history.
 	GetRelatedArtists(input models.GetRelatedArtistsRequest) (*models.Artists, error)
+	// GetRelatedArtistsCtx is like GetRelatedArtists but carries the given context through to the API call.
+	GetRelatedArtistsCtx(ctx context.Context, input models.GetRelatedArtistsRequest) (*models.Artists, error)
 }
 
 // DefaultArtistService is a struct that implements ArtistService interface.
@@ -43,199 +62,147 @@ func NewDefaultArtistService(client *utils.HttpClient) *DefaultArtistService {
 
 // GetArtist implements the ArtistService's interface GetArtist method.
 func (service *DefaultArtistService) GetArtist(input models.GetArtistRequest) (*models.Artist, error) {
+	return service.GetArtistCtx(context.Background(), input)
+}
+
+// GetArtistCtx implements the ArtistService's interface GetArtistCtx method.
+func (service *DefaultArtistService) GetArtistCtx(ctx context.Context, input models.GetArtistRequest) (*models.Artist, error) {
 	// Validate the input
 	if input.Id == "" {
-		return nil, &utils.AppError{Status: http.StatusBadRequest, Message: consts.MsgIdRequired}
+		return nil, utils.NewError(http.StatusBadRequest, consts.MsgIdRequired, nil)
 	}
 
 	// Substitute id in the endpoint
 	endpoint := fmt.Sprintf(consts.EndpointArtist, input.Id)
 
-	// Make an API call
-	res, err := service.client.Get(context.Background(), endpoint, nil)
-	if err != nil {
-		return nil, &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToGetArtist, Err: err}
-	}
-
-	// Handle Spotify API error
-	if res.StatusCode != http.StatusOK {
-		return nil, utils.ParseSpotifyError(res, utils.RegErrorType)
-	}
-
-	// Read the response body
-	data, err := io.ReadAll(res.Body)
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
-
-	// Unmarshal the response data into Artist struct
-	var artist models.Artist
-	if err := json.Unmarshal(data, &artist); err != nil {
-		return nil, err
-	}
-
-	// Return the Artist
-	return &artist, nil
+	// Make an API call and decode the response data into Artist struct
+	return doJSON[models.Artist](ctx, service.client, http.MethodGet, endpoint, nil, nil, consts.MsgFailedToGetArtist)
 }
 
 // GetArtists implements the ArtistService's interface GetArtists method.
 func (service *DefaultArtistService) GetArtists(input models.GetArtistsRequest) (*models.Artists, error) {
+	return service.GetArtistsCtx(context.Background(), input)
+}
+
+// GetArtistsCtx implements the ArtistService's interface GetArtistsCtx method.
+func (service *DefaultArtistService) GetArtistsCtx(ctx context.Context, input models.GetArtistsRequest) (*models.Artists, error) {
 	// Validate the input
-	if input.Ids == "" {
-		return nil, &utils.AppError{Status: http.StatusBadRequest, Message: consts.MsgIdsRequired}
+	if err := validateIds(input.Ids); err != nil {
+		return nil, err
 	}
 
-	// Add inputs to the query parameters
-	params := map[string]string{"ids": input.Ids}
-
-	// Make an API call
-	res, err := service.client.Get(context.Background(), consts.EndpointArtists, params)
-	if err != nil {
-		return nil, &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToGetArtists, Err: err}
-	}
+	// Make an API call per chunk of IDs and merge the responses
+	return getChunked(input.Ids, consts.MaxArtistIds,
+		func(ids []spotifyid.ID) (*models.Artists, error) {
+			// Add inputs to the query parameters
+			params := map[string]string{"ids": join(ids)}
 
-	// Handle Spotify API error
-	if res.StatusCode != http.StatusOK {
-		return nil, utils.ParseSpotifyError(res, utils.RegErrorType)
-	}
+			// Make an API call and decode the response data into Artists struct
+			return doJSON[models.Artists](ctx, service.client, http.MethodGet, consts.EndpointArtists, params, nil, consts.MsgFailedToGetArtists)
+		},
+		func(result, chunk *models.Artists) {
+			result.Artists = append(result.Artists, chunk.Artists...)
+		},
+	)
+}
 
-	// Read the response body
-	data, err := io.ReadAll(res.Body)
-	if err != nil {
+// GetArtistsBatch implements the ArtistService's interface GetArtistsBatch method.
+func (service *DefaultArtistService) GetArtistsBatch(ctx context.Context, input models.GetArtistsRequest, concurrency int) (*BatchResult[models.Artist], error) {
+	// Validate the input
+	if err := validateIds(input.Ids); err != nil {
 		return nil, err
 	}
-	defer res.Body.Close()
 
-	// Unmarshal the response data into Artists struct
-	var artists models.Artists
-	if err := json.Unmarshal(data, &artists); err != nil {
-		return nil, err
-	}
+	// Fetch the chunks of IDs concurrently
+	fetch := batchFetcher[models.Artist](service.client, consts.EndpointArtists, "artists", map[string]string{}, consts.MsgFailedToGetArtists)
+	result := FetchBatch(ctx, input.Ids, consts.MaxArtistIds, concurrency, fetch)
 
-	// Return the Artists
-	return &artists, nil
+	return result, result.Err()
 }
 
 // GetArtistAlbums implements the ArtistService's interface GetArtistAlbums method.
 func (service *DefaultArtistService) GetArtistAlbums(input models.GetArtistAlbumsRequest) (*models.ArtistAlbums, error) {
+	return service.GetArtistAlbumsCtx(context.Background(), input)
+}
+
+// GetArtistAlbumsCtx implements the ArtistService's interface GetArtistAlbumsCtx method.
+func (service *DefaultArtistService) GetArtistAlbumsCtx(ctx context.Context, input models.GetArtistAlbumsRequest) (*models.ArtistAlbums, error) {
 	// Validate the input
 	if input.Id == "" {
-		return nil, &utils.AppError{Status: http.StatusBadRequest, Message: consts.MsgIdRequired}
+		return nil, utils.NewError(http.StatusBadRequest, consts.MsgIdRequired, nil)
 	}
 
 	// Substitute id in the endpoint
 	endpoint := fmt.Sprintf(consts.EndpointArtistAlbums, input.Id)
 
 	// Add inputs to the query parameters
-	params := map[string]string{"id": input.Id, "include_groups": input.IncludeGroups, "market": input.Market, "limit": strconv.Itoa(input.Limit), "offset": strconv.Itoa(input.Offset)}
-
-	// Make an API call
-	res, err := service.client.Get(context.Background(), endpoint, params)
-	if err != nil {
-		return nil, &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToGetArtistAlbums, Err: err}
-	}
-
-	// Handle Spotify API error
-	if res.StatusCode != http.StatusOK {
-		return nil, utils.ParseSpotifyError(res, utils.RegErrorType)
-	}
+	params := map[string]string{"id": string(input.Id), "include_groups": input.IncludeGroups, "market": input.Market, "limit": strconv.Itoa(input.Limit), "offset": strconv.Itoa(input.Offset)}
 
-	// Read the response body
-	data, err := io.ReadAll(res.Body)
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
-
-	// Unmarshal the response data into ArtistAlbums struct
-	var artistAlbums models.ArtistAlbums
-	if err := json.Unmarshal(data, &artistAlbums); err != nil {
-		return nil, err
-	}
+	// Make an API call and decode the response data into ArtistAlbums struct
+	return doJSON[models.ArtistAlbums](ctx, service.client, http.MethodGet, endpoint, params, nil, consts.MsgFailedToGetArtistAlbums)
+}
 
-	// Return the Artists
-	return &artistAlbums, nil
+// AllArtistAlbums implements the ArtistService's interface AllArtistAlbums method.
+func (service *DefaultArtistService) AllArtistAlbums(ctx context.Context, input models.GetArtistAlbumsRequest, maxItems int) iter.Seq2[models.ArtistAlbum, error] {
+	// Default to the maximum page size
+	input.Limit = pageLimit(input.Limit)
+
+	// Walk the pages, fetching each one from its offset
+	return Paginate(ctx, input.Offset, maxItems,
+		func(ctx context.Context, offset int) (*models.ArtistAlbums, error) {
+			input.Offset = offset
+			return service.GetArtistAlbumsCtx(ctx, input)
+		},
+		func(page *models.ArtistAlbums) ([]models.ArtistAlbum, string) {
+			items := make([]models.ArtistAlbum, len(page.Items))
+			for i, item := range page.Items {
+				items[i] = item.ArtistAlbum
+			}
+			return items, page.Next
+		},
+	)
 }
 
 // GetArtistTopTracks implements the ArtistService's interface GetArtistTopTracks method.
 func (service *DefaultArtistService) GetArtistTopTracks(input models.GetArtistTopTracksRequest) (*models.ArtistTopTracks, error) {
+	return service.GetArtistTopTracksCtx(context.Background(), input)
+}
+
+// GetArtistTopTracksCtx implements the ArtistService's interface GetArtistTopTracksCtx method.
+func (service *DefaultArtistService) GetArtistTopTracksCtx(ctx context.Context, input models.GetArtistTopTracksRequest) (*models.ArtistTopTracks, error) {
 	// Validate the input
 	if input.Id == "" {
-		return nil, &utils.AppError{Status: http.StatusBadRequest, Message: consts.MsgIdRequired}
+		return nil, utils.NewError(http.StatusBadRequest, consts.MsgIdRequired, nil)
 	}
 
 	// Substitute id in the endpoint
 	endpoint := fmt.Sprintf(consts.EndpointArtistTopTracks, input.Id)
 
 	// Add inputs to the query parameters
-	params := map[string]string{"id": input.Id, "market": input.Market}
-
-	// Make an API call
-	res, err := service.client.Get(context.Background(), endpoint, params)
-	if err != nil {
-		return nil, &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToGetArtistTopTracks, Err: err}
-	}
-
-	// Handle Spotify API error
-	if res.StatusCode != http.StatusOK {
-		return nil, utils.ParseSpotifyError(res, utils.RegErrorType)
-	}
-
-	// Read the response body
-	data, err := io.ReadAll(res.Body)
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
+	params := map[string]string{"id": string(input.Id), "market": input.Market}
 
-	// Unmarshal the response data into ArtistTopTracks struct
-	var artistTopTracks models.ArtistTopTracks
-	if err := json.Unmarshal(data, &artistTopTracks); err != nil {
-		return nil, err
-	}
-
-	// Return the ArtistTopTracks
-	return &artistTopTracks, nil
+	// Make an API call and decode the response data into ArtistTopTracks struct
+	return doJSON[models.ArtistTopTracks](ctx, service.client, http.MethodGet, endpoint, params, nil, consts.MsgFailedToGetArtistTopTracks)
 }
 
 // GetRelatedArtists implements the ArtistService's interface GetRelatedArtists method.
 func (service *DefaultArtistService) GetRelatedArtists(input models.GetRelatedArtistsRequest) (*models.Artists, error) {
+	return service.GetRelatedArtistsCtx(context.Background(), input)
+}
+
+// GetRelatedArtistsCtx implements the ArtistService's interface GetRelatedArtistsCtx method.
+func (service *DefaultArtistService) GetRelatedArtistsCtx(ctx context.Context, input models.GetRelatedArtistsRequest) (*models.Artists, error) {
 	// Validate the input
 	if input.Id == "" {
-		return nil, &utils.AppError{Status: http.StatusBadRequest, Message: consts.MsgIdRequired}
+		return nil, utils.NewError(http.StatusBadRequest, consts.MsgIdRequired, nil)
 	}
 
 	// Substitute id in the endpoint
 	endpoint := fmt.Sprintf(consts.EndpointRelatedArtists, input.Id)
 
 	// Add inputs to the query parameters
-	params := map[string]string{"id": input.Id}
-
-	// Make an API call
-	res, err := service.client.Get(context.Background(), endpoint, params)
-	if err != nil {
-		return nil, &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToGetRelatedArtists, Err: err}
-	}
-
-	// Handle Spotify API error
-	if res.StatusCode != http.StatusOK {
-		return nil, utils.ParseSpotifyError(res, utils.RegErrorType)
-	}
-
-	// Read the response body
-	data, err := io.ReadAll(res.Body)
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
-
-	// Unmarshal the response data into Artists struct
-	var relatedArtists models.Artists
-	if err := json.Unmarshal(data, &relatedArtists); err != nil {
-		return nil, err
-	}
+	params := map[string]string{"id": string(input.Id)}
 
-	// Return the Artists
-	return &relatedArtists, nil
+	// Make an API call and decode the response data into Artists struct
+	return doJSON[models.Artists](ctx, service.client, http.MethodGet, endpoint, params, nil, consts.MsgFailedToGetRelatedArtists)
 }
@@ -2,14 +2,14 @@ package apis
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"io"
+	"iter"
 	"net/http"
 	"strconv"
 
 	"github.com/alicse3/gospotify/consts"
 	"github.com/alicse3/gospotify/models"
+	"github.com/alicse3/gospotify/spotifyid"
 	"github.com/alicse3/gospotify/utils"
 )
 
@@ -17,28 +17,55 @@ import (
 type AudiobookService interface {
 	// Get Spotify catalog information for a single audiobook. Audiobooks are only available within the US, UK, Canada, Ireland, New Zealand and Australia markets.
 	GetAudiobook(input models.GetAudiobookRequest) (*models.Audiobook, error)
+	// GetAudiobookCtx is like GetAudiobook but carries the given context through to the API call.
+	GetAudiobookCtx(ctx context.Context, input models.GetAudiobookRequest) (*models.Audiobook, error)
 
 	// Get Spotify catalog information for several audiobooks identified by their Spotify IDs. Audiobooks are only available within the US, UK, Canada, Ireland, New Zealand and Australia markets.
 	GetAudiobooks(input models.GetAudiobooksRequest) (*models.Audiobooks, error)
+	// GetAudiobooksCtx is like GetAudiobooks but carries the given context through to the API call.
+	GetAudiobooksCtx(ctx context.Context, input models.GetAudiobooksRequest) (*models.Audiobooks, error)
+
+	// GetAudiobooksBatch gets any number of audiobooks, splitting the IDs into chunks of the maximum size of GetAudiobooks which are fetched concurrently,
+	// at most concurrency chunks at a time, 0 means a default of 4. The items are in the order of the IDs, nil for the unknown ones.
+	// The failed chunks are reported in the Failures of the result, along with an error joining theirs.
+	GetAudiobooksBatch(ctx context.Context, input models.GetAudiobooksRequest, concurrency int) (*BatchResult[models.Audiobook], error)
 
 	// Get Spotify catalog information about an audiobook's chapters. Audiobooks are only available within the US, UK, Canada, Ireland, New Zealand and Australia markets.
 	GetAudiobookChapters(input models.GetAudiobookChaptersRequest) (*models.AudiobookChapters, error)
+	// GetAudiobookChaptersCtx is like GetAudiobookChapters but carries the given context through to the API call.
+	GetAudiobookChaptersCtx(ctx context.Context, input models.GetAudiobookChaptersRequest) (*models.AudiobookChapters, error)
+
+	// AllAudiobookChapters yields the audiobook's chapters one by one, walking all the pages of GetAudiobookChapters starting at input.Offset.
+	// At most maxItems items are yielded, 0 means there is no cap.
+	AllAudiobookChapters(ctx context.Context, input models.GetAudiobookChaptersRequest, maxItems int) iter.Seq2[models.SimplifiedChapter, error]
 
 	// Get a list of the audiobooks saved in the current Spotify user's 'Your Music' library.
 	// Authorization scopes: user-library-read
 	GetSavedAudiobooks(input models.GetSavedAudiobooksRequest) (*models.SavedAudiobooks, error)
+	// GetSavedAudiobooksCtx is like GetSavedAudiobooks but carries the given context through to the API call.
+	GetSavedAudiobooksCtx(ctx context.Context, input models.GetSavedAudiobooksRequest) (*models.SavedAudiobooks, error)
+
+	// AllSavedAudiobooks yields the saved audiobooks one by one, walking all the pages of GetSavedAudiobooks starting at input.Offset.
+	// At most maxItems items are yielded, 0 means there is no cap.
+	AllSavedAudiobooks(ctx context.Context, input models.GetSavedAudiobooksRequest, maxItems int) iter.Seq2[models.SimplifiedAudiobook, error]
 
 	// Save one or more audiobooks to the current Spotify user's library.
 	// Authorization scopes: user-library-modify
 	SaveAudiobooks(input models.SaveAudiobooksRequest) error
+	// SaveAudiobooksCtx is like SaveAudiobooks but carries the given context through to the API call.
+	SaveAudiobooksCtx(ctx context.Context, input models.SaveAudiobooksRequest) error
 
 	// Remove one or more audiobooks from the Spotify user's library.
 	// Authorization scopes: user-library-modify
 	DeleteAudiobooks(input models.RemoveAudiobooksRequest) error
+	// DeleteAudiobooksCtx is like DeleteAudiobooks but carries the given context through to the API call.
+	DeleteAudiobooksCtx(ctx context.Context, input models.RemoveAudiobooksRequest) error
 
 	// Check if one or more audiobooks are already saved in the current Spotify user's library.
 	// Authorization scopes: user-library-read
 	CheckSavedAudiobooks(input models.CheckSavedAudiobooksRequest) (*models.CheckSavedAudiobooks, error)
+	// CheckSavedAudiobooksCtx is like CheckSavedAudiobooks but carries the given context through to the API call.
+	CheckSavedAudiobooksCtx(ctx context.Context, input models.CheckSavedAudiobooksRequest) (*models.CheckSavedAudiobooks, error)
 }
 
 // DefaultAudiobookService is a struct that implements AudiobookService interface.
@@ -53,238 +80,221 @@ func NewDefaultAudiobookService(client *utils.HttpClient) *DefaultAudiobookServi
 
 // GetAudiobook implements the AudiobookService's interface GetAudiobook method.
 func (service *DefaultAudiobookService) GetAudiobook(input models.GetAudiobookRequest) (*models.Audiobook, error) {
+	return service.GetAudiobookCtx(context.Background(), input)
+}
+
+// GetAudiobookCtx implements the AudiobookService's interface GetAudiobookCtx method.
+func (service *DefaultAudiobookService) GetAudiobookCtx(ctx context.Context, input models.GetAudiobookRequest) (*models.Audiobook, error) {
 	// Validate the input
 	if input.Id == "" {
-		return nil, &utils.AppError{Status: http.StatusBadRequest, Message: consts.MsgIdRequired}
+		return nil, utils.NewError(http.StatusBadRequest, consts.MsgIdRequired, nil)
 	}
 
 	// Substitute id in the endpoint
 	endpoint := fmt.Sprintf(consts.EndpointAudiobook, input.Id)
 
-	// Make an API call
-	res, err := service.client.Get(context.Background(), endpoint, nil)
-	if err != nil {
-		return nil, &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToGetAudiobook, Err: err}
-	}
-
-	// Handle Spotify API error
-	if res.StatusCode != http.StatusOK {
-		return nil, utils.ParseSpotifyError(res, utils.RegErrorType)
-	}
-
-	// Read the response body
-	data, err := io.ReadAll(res.Body)
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
-
-	// Unmarshal the response data into Audiobook struct
-	var audiobook models.Audiobook
-	if err := json.Unmarshal(data, &audiobook); err != nil {
-		return nil, err
-	}
-
-	// Return the Audiobook
-	return &audiobook, nil
+	// Make an API call and decode the response data into Audiobook struct
+	return doJSON[models.Audiobook](ctx, service.client, http.MethodGet, endpoint, nil, nil, consts.MsgFailedToGetAudiobook)
 }
 
 // GetAudiobooks implements the AudiobookService's interface GetAudiobooks method.
 func (service *DefaultAudiobookService) GetAudiobooks(input models.GetAudiobooksRequest) (*models.Audiobooks, error) {
-	// Validate the input
-	if input.Ids == "" {
-		return nil, &utils.AppError{Status: http.StatusBadRequest, Message: consts.MsgIdsRequired}
-	}
-
-	// Add inputs to the query parameters
-	params := map[string]string{"ids": input.Ids, "market": input.Market}
+	return service.GetAudiobooksCtx(context.Background(), input)
+}
 
-	// Make an API call
-	res, err := service.client.Get(context.Background(), consts.EndpointAudiobooks, params)
-	if err != nil {
-		return nil, &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToGetAudiobooks, Err: err}
+// GetAudiobooksCtx implements the AudiobookService's interface GetAudiobooksCtx method.
+func (service *DefaultAudiobookService) GetAudiobooksCtx(ctx context.Context, input models.GetAudiobooksRequest) (*models.Audiobooks, error) {
+	// Validate the input
+	if err := validateIds(input.Ids); err != nil {
+		return nil, err
 	}
 
-	// Handle Spotify API error
-	if res.StatusCode != http.StatusOK {
-		return nil, utils.ParseSpotifyError(res, utils.RegErrorType)
-	}
+	// Make an API call per chunk of IDs and merge the responses
+	return getChunked(input.Ids, consts.MaxAudiobookIds,
+		func(ids []spotifyid.ID) (*models.Audiobooks, error) {
+			// Add inputs to the query parameters
+			params := map[string]string{"ids": join(ids), "market": input.Market}
+
+			// Make an API call and decode the response data into Audiobooks struct
+			return doJSON[models.Audiobooks](ctx, service.client, http.MethodGet, consts.EndpointAudiobooks, params, nil, consts.MsgFailedToGetAudiobooks)
+		},
+		func(result, chunk *models.Audiobooks) {
+			result.Audiobooks = append(result.Audiobooks, chunk.Audiobooks...)
+		},
+	)
+}
 
-	// Read the response body
-	data, err := io.ReadAll(res.Body)
-	if err != nil {
+// GetAudiobooksBatch implements the AudiobookService's interface GetAudiobooksBatch method.
+func (service *DefaultAudiobookService) GetAudiobooksBatch(ctx context.Context, input models.GetAudiobooksRequest, concurrency int) (*BatchResult[models.Audiobook], error) {
+	// Validate the input
+	if err := validateIds(input.Ids); err != nil {
 		return nil, err
 	}
-	defer res.Body.Close()
 
-	// Unmarshal the response data into Audiobooks struct
-	var audiobooks models.Audiobooks
-	if err := json.Unmarshal(data, &audiobooks); err != nil {
-		return nil, err
-	}
+	// Fetch the chunks of IDs concurrently
+	fetch := batchFetcher[models.Audiobook](service.client, consts.EndpointAudiobooks, "audiobooks", map[string]string{"market": input.Market}, consts.MsgFailedToGetAudiobooks)
+	result := FetchBatch(ctx, input.Ids, consts.MaxAudiobookIds, concurrency, fetch)
 
-	// Return the Audiobooks
-	return &audiobooks, nil
+	return result, result.Err()
 }
 
 // GetAudiobookChapters implements the AudiobookService's interface GetAudiobookChapters method.
 func (service *DefaultAudiobookService) GetAudiobookChapters(input models.GetAudiobookChaptersRequest) (*models.AudiobookChapters, error) {
+	return service.GetAudiobookChaptersCtx(context.Background(), input)
+}
+
+// GetAudiobookChaptersCtx implements the AudiobookService's interface GetAudiobookChaptersCtx method.
+func (service *DefaultAudiobookService) GetAudiobookChaptersCtx(ctx context.Context, input models.GetAudiobookChaptersRequest) (*models.AudiobookChapters, error) {
 	// Validate the input
 	if input.Id == "" {
-		return nil, &utils.AppError{Status: http.StatusBadRequest, Message: consts.MsgIdRequired}
+		return nil, utils.NewError(http.StatusBadRequest, consts.MsgIdRequired, nil)
 	}
 
 	// Substitute id in the endpoint
 	endpoint := fmt.Sprintf(consts.EndpointAudiobookChapters, input.Id)
 
 	// Add inputs to the query parameters
-	params := map[string]string{"id": input.Id, "market": input.Market, "limit": strconv.Itoa(input.Limit), "offset": strconv.Itoa(input.Offset)}
+	params := map[string]string{"id": string(input.Id), "market": input.Market, "limit": strconv.Itoa(input.Limit), "offset": strconv.Itoa(input.Offset)}
 
-	// Make an API call
-	res, err := service.client.Get(context.Background(), endpoint, params)
-	if err != nil {
-		return nil, &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToGetAudiobookChapters, Err: err}
-	}
-
-	// Handle Spotify API error
-	if res.StatusCode != http.StatusOK {
-		return nil, utils.ParseSpotifyError(res, utils.RegErrorType)
-	}
-
-	// Read the response body
-	data, err := io.ReadAll(res.Body)
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
-
-	// Unmarshal the response data into AudiobookChapters struct
-	var audiobookChapters models.AudiobookChapters
-	if err := json.Unmarshal(data, &audiobookChapters); err != nil {
-		return nil, err
-	}
+	// Make an API call and decode the response data into AudiobookChapters struct
+	return doJSON[models.AudiobookChapters](ctx, service.client, http.MethodGet, endpoint, params, nil, consts.MsgFailedToGetAudiobookChapters)
+}
 
-	// Return the AudiobookChapters
-	return &audiobookChapters, nil
+// AllAudiobookChapters implements the AudiobookService's interface AllAudiobookChapters method.
+func (service *DefaultAudiobookService) AllAudiobookChapters(ctx context.Context, input models.GetAudiobookChaptersRequest, maxItems int) iter.Seq2[models.SimplifiedChapter, error] {
+	// Default to the maximum page size
+	input.Limit = pageLimit(input.Limit)
+
+	// Walk the pages, fetching each one from its offset
+	return Paginate(ctx, input.Offset, maxItems,
+		func(ctx context.Context, offset int) (*models.AudiobookChapters, error) {
+			input.Offset = offset
+			return service.GetAudiobookChaptersCtx(ctx, input)
+		},
+		func(page *models.AudiobookChapters) ([]models.SimplifiedChapter, string) {
+			return page.Items, page.Next
+		},
+	)
 }
 
 // GetSavedAudiobooks implements the AudiobookService's interface GetSavedAudiobooks method.
 func (service *DefaultAudiobookService) GetSavedAudiobooks(input models.GetSavedAudiobooksRequest) (*models.SavedAudiobooks, error) {
-	// Add inputs to the query parameters
-	params := map[string]string{"limit": strconv.Itoa(input.Limit), "offset": strconv.Itoa(input.Offset)}
-
-	// Make an API call
-	res, err := service.client.Get(context.Background(), consts.EndpointMyAudiobooks, params)
-	if err != nil {
-		return nil, &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToGetSavedAudiobooks, Err: err}
-	}
-
-	// Handle Spotify API error
-	if res.StatusCode != http.StatusOK {
-		return nil, utils.ParseSpotifyError(res, utils.RegErrorType)
-	}
+	return service.GetSavedAudiobooksCtx(context.Background(), input)
+}
 
-	// Read the response body
-	data, err := io.ReadAll(res.Body)
-	if err != nil {
+// GetSavedAudiobooksCtx implements the AudiobookService's interface GetSavedAudiobooksCtx method.
+func (service *DefaultAudiobookService) GetSavedAudiobooksCtx(ctx context.Context, input models.GetSavedAudiobooksRequest) (*models.SavedAudiobooks, error) {
+	// Check the granted scopes
+	if err := checkScopes(service.client, "AudiobookService.GetSavedAudiobooks"); err != nil {
 		return nil, err
 	}
-	defer res.Body.Close()
 
-	// Unmarshal the response data into SavedAudiobooks struct
-	var savedAudiobooks models.SavedAudiobooks
-	if err := json.Unmarshal(data, &savedAudiobooks); err != nil {
-		return nil, err
-	}
+	// Add inputs to the query parameters
+	params := map[string]string{"limit": strconv.Itoa(input.Limit), "offset": strconv.Itoa(input.Offset)}
 
-	// Return the SavedAudiobooks
-	return &savedAudiobooks, nil
+	// Make an API call and decode the response data into SavedAudiobooks struct
+	return doJSON[models.SavedAudiobooks](ctx, service.client, http.MethodGet, consts.EndpointMyAudiobooks, params, nil, consts.MsgFailedToGetSavedAudiobooks)
+}
+
+// AllSavedAudiobooks implements the AudiobookService's interface AllSavedAudiobooks method.
+func (service *DefaultAudiobookService) AllSavedAudiobooks(ctx context.Context, input models.GetSavedAudiobooksRequest, maxItems int) iter.Seq2[models.SimplifiedAudiobook, error] {
+	// Default to the maximum page size
+	input.Limit = pageLimit(input.Limit)
+
+	// Walk the pages, fetching each one from its offset
+	return Paginate(ctx, input.Offset, maxItems,
+		func(ctx context.Context, offset int) (*models.SavedAudiobooks, error) {
+			input.Offset = offset
+			return service.GetSavedAudiobooksCtx(ctx, input)
+		},
+		func(page *models.SavedAudiobooks) ([]models.SimplifiedAudiobook, string) {
+			return page.Items, page.Next
+		},
+	)
 }
 
 // SaveAudiobooks implements the AudiobookService's interface SaveAudiobooks method.
 func (service *DefaultAudiobookService) SaveAudiobooks(input models.SaveAudiobooksRequest) error {
-	// Validate the input
-	if input.Ids == "" {
-		return &utils.AppError{Status: http.StatusBadRequest, Message: consts.MsgIdsRequired}
-	}
-
-	// Add inputs to the query parameters
-	params := map[string]string{"ids": input.Ids}
+	return service.SaveAudiobooksCtx(context.Background(), input)
+}
 
-	// Make an API call
-	res, err := service.client.Put(context.Background(), consts.EndpointMyAudiobooks, nil, params, nil)
-	if err != nil {
-		return &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToSaveAudiobooks, Err: err}
+// SaveAudiobooksCtx implements the AudiobookService's interface SaveAudiobooksCtx method.
+func (service *DefaultAudiobookService) SaveAudiobooksCtx(ctx context.Context, input models.SaveAudiobooksRequest) error {
+	// Check the granted scopes
+	if err := checkScopes(service.client, "AudiobookService.SaveAudiobooks"); err != nil {
+		return err
 	}
 
-	// Handle Spotify API error
-	if res.StatusCode != http.StatusOK {
-		return utils.ParseSpotifyError(res, utils.RegErrorType)
+	// Validate the input
+	if err := validateIds(input.Ids); err != nil {
+		return err
 	}
 
-	// Return the empty response
-	return nil
+	// Make an API call per chunk of IDs
+	return doChunked(input.Ids, consts.MaxAudiobookIds, func(ids []spotifyid.ID) error {
+		// Add inputs to the query parameters
+		params := map[string]string{"ids": join(ids)}
+
+		// Make an API call and discard the response body
+		return doEmpty(ctx, service.client, http.MethodPut, consts.EndpointMyAudiobooks, params, nil, consts.MsgFailedToSaveAudiobooks)
+	})
 }
 
 // DeleteAudiobooks implements the AudiobookService's interface DeleteAudiobooks method.
 func (service *DefaultAudiobookService) DeleteAudiobooks(input models.RemoveAudiobooksRequest) error {
-	// Validate the input
-	if input.Ids == "" {
-		return &utils.AppError{Status: http.StatusBadRequest, Message: consts.MsgIdsRequired}
-	}
-
-	// Add inputs to the query parameters
-	params := map[string]string{"ids": input.Ids}
+	return service.DeleteAudiobooksCtx(context.Background(), input)
+}
 
-	// Make an API call
-	res, err := service.client.Delete(context.Background(), consts.EndpointMyAudiobooks, nil, params, nil)
-	if err != nil {
-		return &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToSaveAudiobooks, Err: err}
+// DeleteAudiobooksCtx implements the AudiobookService's interface DeleteAudiobooksCtx method.
+func (service *DefaultAudiobookService) DeleteAudiobooksCtx(ctx context.Context, input models.RemoveAudiobooksRequest) error {
+	// Check the granted scopes
+	if err := checkScopes(service.client, "AudiobookService.DeleteAudiobooks"); err != nil {
+		return err
 	}
 
-	// Handle Spotify API error
-	if res.StatusCode != http.StatusOK {
-		return utils.ParseSpotifyError(res, utils.RegErrorType)
+	// Validate the input
+	if err := validateIds(input.Ids); err != nil {
+		return err
 	}
 
-	// Return the empty response
-	return nil
+	// Make an API call per chunk of IDs
+	return doChunked(input.Ids, consts.MaxAudiobookIds, func(ids []spotifyid.ID) error {
+		// Add inputs to the query parameters
+		params := map[string]string{"ids": join(ids)}
+
+		// Make an API call and discard the response body
+		return doEmpty(ctx, service.client, http.MethodDelete, consts.EndpointMyAudiobooks, params, nil, consts.MsgFailedToSaveAudiobooks)
+	})
 }
 
 // CheckSavedAudiobooks implements the AudiobookService's interface CheckSavedAudiobooks method.
 func (service *DefaultAudiobookService) CheckSavedAudiobooks(input models.CheckSavedAudiobooksRequest) (*models.CheckSavedAudiobooks, error) {
-	// Validate the input
-	if input.Ids == "" {
-		return nil, &utils.AppError{Status: http.StatusBadRequest, Message: consts.MsgIdsRequired}
-	}
-
-	// Add inputs to the query parameters
-	params := map[string]string{"ids": input.Ids}
-
-	// Make an API call
-	res, err := service.client.Get(context.Background(), consts.EndpointMySavedAudiobooks, params)
-	if err != nil {
-		return nil, &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToCheckSavedAudiobooks, Err: err}
-	}
-
-	// Handle Spotify API error
-	if res.StatusCode != http.StatusOK {
-		return nil, utils.ParseSpotifyError(res, utils.RegErrorType)
-	}
+	return service.CheckSavedAudiobooksCtx(context.Background(), input)
+}
 
-	// Read the response body
-	data, err := io.ReadAll(res.Body)
-	if err != nil {
+// CheckSavedAudiobooksCtx implements the AudiobookService's interface CheckSavedAudiobooksCtx method.
+func (service *DefaultAudiobookService) CheckSavedAudiobooksCtx(ctx context.Context, input models.CheckSavedAudiobooksRequest) (*models.CheckSavedAudiobooks, error) {
+	// Check the granted scopes
+	if err := checkScopes(service.client, "AudiobookService.CheckSavedAudiobooks"); err != nil {
 		return nil, err
 	}
-	defer res.Body.Close()
 
-	// Unmarshal the response data into CheckSavedAudiobooks struct
-	var checkSavedAudiobooks models.CheckSavedAudiobooks
-	if err := json.Unmarshal(data, &checkSavedAudiobooks); err != nil {
+	// Validate the input
+	if err := validateIds(input.Ids); err != nil {
 		return nil, err
 	}
 
-	// Return the CheckSavedAudiobooks
-	return &checkSavedAudiobooks, nil
+	// Make an API call per chunk of IDs and merge the responses
+	return getChunked(input.Ids, consts.MaxAudiobookIds,
+		func(ids []spotifyid.ID) (*models.CheckSavedAudiobooks, error) {
+			// Add inputs to the query parameters
+			params := map[string]string{"ids": join(ids)}
+
+			// Make an API call and decode the response data into CheckSavedAudiobooks struct
+			return doJSON[models.CheckSavedAudiobooks](ctx, service.client, http.MethodGet, consts.EndpointMySavedAudiobooks, params, nil, consts.MsgFailedToCheckSavedAudiobooks)
+		},
+		func(result, chunk *models.CheckSavedAudiobooks) {
+			*result = append(*result, *chunk...)
+		},
+	)
 }
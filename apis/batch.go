@@ -0,0 +1,127 @@
+package apis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"sync"
+
+	"github.com/alicse3/gospotify/consts"
+	"github.com/alicse3/gospotify/spotifyid"
+	"github.com/alicse3/gospotify/utils"
+)
+
+const (
+	// default number of chunks fetched at the same time by the batch helpers
+	defaultBatchConcurrency = 4
+)
+
+// BatchFetcher fetches a single chunk of IDs of a batch, returning one item per ID in the same order, nil for the unknown IDs.
+type BatchFetcher[T any] func(ctx context.Context, ids []spotifyid.ID) ([]*T, error)
+
+// BatchFailure describes a chunk of IDs of a batch which couldn't be fetched.
+type BatchFailure struct {
+	Ids []spotifyid.ID
+	Err error
+}
+
+// BatchResult is the result of fetching a batch of IDs, e.g. with TrackService.GetTracksBatch.
+type BatchResult[T any] struct {
+	// Items in the order of the IDs. An item is nil if Spotify doesn't know its ID, or if the chunk of its ID failed.
+	Items []*T
+	// Chunks which couldn't be fetched, in the order of the IDs
+	Failures []BatchFailure
+}
+
+// Err joins the errors of the failed chunks. It returns nil if all the chunks were fetched.
+func (r *BatchResult[T]) Err() error {
+	errs := make([]error, len(r.Failures))
+	for i, failure := range r.Failures {
+		errs[i] = failure.Err
+	}
+
+	return errors.Join(errs...)
+}
+
+// FetchBatch fetches any number of IDs with fetch, in chunks of at most size IDs.
+// At most concurrency chunks are fetched at the same time, 0 means a default of 4.
+// The chunks are sent through the same client, so they share its retry policy and rate limiter.
+// A failed chunk doesn't stop the others, it's reported in the Failures of the result instead,
+// and once ctx is done the chunks which haven't been started yet fail with its error.
+func FetchBatch[T any](ctx context.Context, ids []spotifyid.ID, size, concurrency int, fetch BatchFetcher[T]) *BatchResult[T] {
+	result := &BatchResult[T]{Items: make([]*T, len(ids))}
+	if len(ids) == 0 {
+		return result
+	}
+	if concurrency <= 0 {
+		concurrency = defaultBatchConcurrency
+	}
+
+	// Fetch the chunks, each one writing its items to its own part of the result
+	batches := chunks(ids, size)
+	errs := make([]error, len(batches))
+	slots := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, batch := range batches {
+		// Wait for a free slot, unless the caller is no longer interested
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			errs[i] = ctx.Err()
+			continue
+		}
+
+		// The select picks either case at random when both are ready, so don't start the chunk if ctx is done meanwhile
+		if err := ctx.Err(); err != nil {
+			<-slots
+			errs[i] = err
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			items, err := fetch(ctx, batch)
+			if err == nil && len(items) != len(batch) {
+				err = utils.NewError(http.StatusInternalServerError, consts.MsgUnexpectedItemCount, fmt.Errorf("%d items for %d IDs", len(items), len(batch)))
+			}
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			copy(result.Items[i*size:], items)
+		}()
+	}
+	wg.Wait()
+
+	// Report the failed chunks
+	for i, err := range errs {
+		if err != nil {
+			result.Failures = append(result.Failures, BatchFailure{Ids: batches[i], Err: err})
+		}
+	}
+
+	return result
+}
+
+// batchFetcher returns a BatchFetcher for an endpoint taking the IDs in the ids query parameter
+// and returning the items in the list under the given key of the response, e.g. "tracks".
+func batchFetcher[T any](client *utils.HttpClient, endpoint, key string, queryParams map[string]string, failure string) BatchFetcher[T] {
+	return func(ctx context.Context, ids []spotifyid.ID) ([]*T, error) {
+		// Add the chunk of IDs to the query parameters
+		params := maps.Clone(queryParams)
+		params["ids"] = join(ids)
+
+		// Make an API call and decode the response data, an unknown ID has null in place of its item
+		res, err := doJSON[map[string][]*T](ctx, client, http.MethodGet, endpoint, params, nil, failure)
+		if err != nil || res == nil {
+			return nil, err
+		}
+
+		return (*res)[key], nil
+	}
+}
@@ -0,0 +1,184 @@
+package apis
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicse3/gospotify/spotifyid"
+)
+
+// batchIds returns the IDs id0, id1 and so on.
+func batchIds(n int) []spotifyid.ID {
+	ids := make([]spotifyid.ID, n)
+	for i := range ids {
+		ids[i] = spotifyid.ID("id" + string(rune('0'+i)))
+	}
+	return ids
+}
+
+// echoFetch is a BatchFetcher which returns the IDs themselves as the items.
+func echoFetch(_ context.Context, ids []spotifyid.ID) ([]*spotifyid.ID, error) {
+	items := make([]*spotifyid.ID, len(ids))
+	for i := range ids {
+		items[i] = &ids[i]
+	}
+	return items, nil
+}
+
+// itemIds returns the IDs of the items returned by echoFetch, an empty one for a nil item.
+func itemIds(items []*spotifyid.ID) []spotifyid.ID {
+	ids := make([]spotifyid.ID, len(items))
+	for i, item := range items {
+		if item != nil {
+			ids[i] = *item
+		}
+	}
+	return ids
+}
+
+func TestFetchBatch(t *testing.T) {
+	errChunk := errors.New("chunk failed")
+
+	tests := []struct {
+		name        string
+		ids         int
+		size        int
+		fetch       BatchFetcher[spotifyid.ID]
+		want        []spotifyid.ID
+		wantFailed  [][]spotifyid.ID
+		wantErrType error
+	}{
+		{name: "no ids", ids: 0, size: 3, fetch: echoFetch, want: []spotifyid.ID{}},
+		{name: "single chunk", ids: 3, size: 3, fetch: echoFetch, want: batchIds(3)},
+		{
+			name: "order kept across chunks finishing out of order",
+			ids:  8,
+			size: 3,
+			fetch: func(ctx context.Context, ids []spotifyid.ID) ([]*spotifyid.ID, error) {
+				// The first chunks finish last
+				if ids[0] == "id0" {
+					time.Sleep(20 * time.Millisecond)
+				}
+				return echoFetch(ctx, ids)
+			},
+			want: batchIds(8),
+		},
+		{
+			name: "failed chunk",
+			ids:  7,
+			size: 3,
+			fetch: func(ctx context.Context, ids []spotifyid.ID) ([]*spotifyid.ID, error) {
+				if ids[0] == "id3" {
+					return nil, errChunk
+				}
+				return echoFetch(ctx, ids)
+			},
+			want:        []spotifyid.ID{"id0", "id1", "id2", "", "", "", "id6"},
+			wantFailed:  [][]spotifyid.ID{{"id3", "id4", "id5"}},
+			wantErrType: errChunk,
+		},
+		{
+			name: "item count mismatch",
+			ids:  5,
+			size: 3,
+			fetch: func(ctx context.Context, ids []spotifyid.ID) ([]*spotifyid.ID, error) {
+				items, _ := echoFetch(ctx, ids)
+				return items[:1], nil
+			},
+			want:       []spotifyid.ID{"", "", "", "", ""},
+			wantFailed: [][]spotifyid.ID{{"id0", "id1", "id2"}, {"id3", "id4"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := FetchBatch(context.Background(), batchIds(tt.ids), tt.size, 2, tt.fetch)
+
+			if got := itemIds(result.Items); !slices.Equal(got, tt.want) {
+				t.Errorf("Items = %v, want %v", got, tt.want)
+			}
+			if len(result.Failures) != len(tt.wantFailed) {
+				t.Fatalf("Failures = %v, want %v", result.Failures, tt.wantFailed)
+			}
+			for i, failure := range result.Failures {
+				if !slices.Equal(failure.Ids, tt.wantFailed[i]) || failure.Err == nil {
+					t.Errorf("Failures[%d] = %v, want the IDs %v with an error", i, failure, tt.wantFailed[i])
+				}
+			}
+			if (result.Err() != nil) != (len(tt.wantFailed) > 0) {
+				t.Errorf("Err() = %v, want an error only for the failed chunks", result.Err())
+			}
+			if tt.wantErrType != nil && !errors.Is(result.Err(), tt.wantErrType) {
+				t.Errorf("Err() = %v, want %v", result.Err(), tt.wantErrType)
+			}
+		})
+	}
+}
+
+func TestFetchBatchConcurrencyLimit(t *testing.T) {
+	var inFlight, peak atomic.Int32
+	var mu sync.Mutex
+	fetch := func(ctx context.Context, ids []spotifyid.ID) ([]*spotifyid.ID, error) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+
+		mu.Lock()
+		peak.Store(max(peak.Load(), n))
+		mu.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+		return echoFetch(ctx, ids)
+	}
+
+	result := FetchBatch(context.Background(), batchIds(10), 1, 3, fetch)
+	if result.Err() != nil {
+		t.Fatalf("Err() = %v", result.Err())
+	}
+	if got := peak.Load(); got > 3 {
+		t.Errorf("%d chunks fetched at the same time, want at most 3", got)
+	}
+}
+
+func TestFetchBatchCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var calls atomic.Int32
+
+	// The first chunk cancels the batch, the slot it frees must not start another chunk
+	fetch := func(ctx context.Context, ids []spotifyid.ID) ([]*spotifyid.ID, error) {
+		calls.Add(1)
+		cancel()
+		return echoFetch(ctx, ids)
+	}
+
+	result := FetchBatch(ctx, batchIds(6), 2, 1, fetch)
+	if got := calls.Load(); got != 1 {
+		t.Errorf("%d chunks fetched, want 1", got)
+	}
+	if got := itemIds(result.Items); !slices.Equal(got, []spotifyid.ID{"id0", "id1", "", "", "", ""}) {
+		t.Errorf("Items = %v, want the first chunk only", got)
+	}
+	if len(result.Failures) != 2 || !errors.Is(result.Err(), context.Canceled) {
+		t.Errorf("Failures = %v, want the 2 chunks not started with context.Canceled", result.Failures)
+	}
+}
+
+func TestFetchBatchCancelledBeforehand(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var calls atomic.Int32
+	fetch := func(ctx context.Context, ids []spotifyid.ID) ([]*spotifyid.ID, error) {
+		calls.Add(1)
+		return echoFetch(ctx, ids)
+	}
+
+	result := FetchBatch(ctx, batchIds(6), 2, 4, fetch)
+	if calls.Load() != 0 || len(result.Failures) != 3 {
+		t.Errorf("%d chunks fetched and %d failed, want none fetched and all 3 failed", calls.Load(), len(result.Failures))
+	}
+}
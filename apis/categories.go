@@ -2,9 +2,8 @@ package apis
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"io"
+	"iter"
 	"net/http"
 	"strconv"
 
@@ -17,9 +16,17 @@ import (
 type CategoryService interface {
 	// Get a list of categories used to tag items in Spotify (on, for example, the Spotify player’s “Browse” tab).
 	GetBrowseCategories(input models.GetBrowseCategoriesRequest) (*models.Categories, error)
+	// GetBrowseCategoriesCtx is like GetBrowseCategories but carries the given context through to the API call.
+	GetBrowseCategoriesCtx(ctx context.Context, input models.GetBrowseCategoriesRequest) (*models.Categories, error)
+
+	// AllBrowseCategories yields the browse categories one by one, walking all the pages of GetBrowseCategories starting at input.Offset.
+	// At most maxItems items are yielded, 0 means there is no cap.
+	AllBrowseCategories(ctx context.Context, input models.GetBrowseCategoriesRequest, maxItems int) iter.Seq2[models.Category, error]
 
 	// Get a single category used to tag items in Spotify (on, for example, the Spotify player’s “Browse” tab).
 	GetBrowseCategory(input models.GetBrowseCategoryRequest) (*models.Category, error)
+	// GetBrowseCategoryCtx is like GetBrowseCategory but carries the given context through to the API call.
+	GetBrowseCategoryCtx(ctx context.Context, input models.GetBrowseCategoryRequest) (*models.Category, error)
 }
 
 // DefaultCategoryService is a struct that implements CategoryService interface.
@@ -34,42 +41,45 @@ func NewDefaultCategoryService(client *utils.HttpClient) *DefaultCategoryService
 
 // GetBrowseCategories implements the CategoryService's interface GetBrowseCategories method.
 func (service *DefaultCategoryService) GetBrowseCategories(input models.GetBrowseCategoriesRequest) (*models.Categories, error) {
+	return service.GetBrowseCategoriesCtx(context.Background(), input)
+}
+
+// GetBrowseCategoriesCtx implements the CategoryService's interface GetBrowseCategoriesCtx method.
+func (service *DefaultCategoryService) GetBrowseCategoriesCtx(ctx context.Context, input models.GetBrowseCategoriesRequest) (*models.Categories, error) {
 	// Add inputs to the query parameters
 	params := map[string]string{"locale": input.Locale, "limit": strconv.Itoa(input.Limit), "offset": strconv.Itoa(input.Offset)}
 
-	// Make an API call
-	res, err := service.client.Get(context.Background(), consts.EndpointBrowseCategories, params)
-	if err != nil {
-		return nil, &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToGetBrowseCategories, Err: err}
-	}
-
-	// Handle Spotify API error
-	if res.StatusCode != http.StatusOK {
-		return nil, utils.ParseSpotifyError(res, utils.RegErrorType)
-	}
-
-	// Read the response body
-	data, err := io.ReadAll(res.Body)
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
-
-	// Unmarshal the response data into Categories struct
-	var categories models.Categories
-	if err := json.Unmarshal(data, &categories); err != nil {
-		return nil, err
-	}
+	// Make an API call and decode the response data into Categories struct
+	return doJSON[models.Categories](ctx, service.client, http.MethodGet, consts.EndpointBrowseCategories, params, nil, consts.MsgFailedToGetBrowseCategories)
+}
 
-	// Return the Categories
-	return &categories, nil
+// AllBrowseCategories implements the CategoryService's interface AllBrowseCategories method.
+func (service *DefaultCategoryService) AllBrowseCategories(ctx context.Context, input models.GetBrowseCategoriesRequest, maxItems int) iter.Seq2[models.Category, error] {
+	// Default to the maximum page size
+	input.Limit = pageLimit(input.Limit)
+
+	// Walk the pages, fetching each one from its offset
+	return Paginate(ctx, input.Offset, maxItems,
+		func(ctx context.Context, offset int) (*models.Categories, error) {
+			input.Offset = offset
+			return service.GetBrowseCategoriesCtx(ctx, input)
+		},
+		func(page *models.Categories) ([]models.Category, string) {
+			return page.Categories.Items, page.Categories.Next
+		},
+	)
 }
 
 // GetBrowseCategory implements the CategoryService's interface GetBrowseCategory method.
 func (service *DefaultCategoryService) GetBrowseCategory(input models.GetBrowseCategoryRequest) (*models.Category, error) {
+	return service.GetBrowseCategoryCtx(context.Background(), input)
+}
+
+// GetBrowseCategoryCtx implements the CategoryService's interface GetBrowseCategoryCtx method.
+func (service *DefaultCategoryService) GetBrowseCategoryCtx(ctx context.Context, input models.GetBrowseCategoryRequest) (*models.Category, error) {
 	// Validate the input
 	if input.CategoryId == "" {
-		return nil, &utils.AppError{Status: http.StatusBadRequest, Message: consts.MsgCategoryIdRequired}
+		return nil, utils.NewError(http.StatusBadRequest, consts.MsgCategoryIdRequired, nil)
 	}
 
 	// Add inputs to the query parameters
@@ -78,30 +88,6 @@ func (service *DefaultCategoryService) GetBrowseCategory(input models.GetBrowseC
 	// Substitute id in the endpoint
 	endpoint := fmt.Sprintf(consts.EndpointBrowseCategory, input.CategoryId)
 
-	// Make an API call
-	res, err := service.client.Get(context.Background(), endpoint, params)
-	if err != nil {
-		return nil, &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToGetBrowseCategory, Err: err}
-	}
-
-	// Handle Spotify API error
-	if res.StatusCode != http.StatusOK {
-		return nil, utils.ParseSpotifyError(res, utils.RegErrorType)
-	}
-
-	// Read the response body
-	data, err := io.ReadAll(res.Body)
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
-
-	// Unmarshal the response data into Category struct
-	var category models.Category
-	if err := json.Unmarshal(data, &category); err != nil {
-		return nil, err
-	}
-
-	// Return the Category
-	return &category, nil
+	// Make an API call and decode the response data into Category struct
+	return doJSON[models.Category](ctx, service.client, http.MethodGet, endpoint, params, nil, consts.MsgFailedToGetBrowseCategory)
 }
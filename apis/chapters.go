@@ -2,13 +2,12 @@ package apis
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
 
 	"github.com/alicse3/gospotify/consts"
 	"github.com/alicse3/gospotify/models"
+	"github.com/alicse3/gospotify/spotifyid"
 	"github.com/alicse3/gospotify/utils"
 )
 
@@ -16,9 +15,13 @@ import (
 type ChapterService interface {
 	// Get Spotify catalog information for a single audiobook chapter. Chapters are only available within the US, UK, Canada, Ireland, New Zealand and Australia markets.
 	GetChapter(input models.GetChapterRequest) (*models.Chapter, error)
+	// GetChapterCtx is like GetChapter but carries the given context through to the API call.
+	GetChapterCtx(ctx context.Context, input models.GetChapterRequest) (*models.Chapter, error)
 
 	// Get Spotify catalog information for several audiobook chapters identified by their Spotify IDs. Chapters are only available within the US, UK, Canada, Ireland, New Zealand and Australia markets.
 	GetChapters(input models.GetChaptersRequest) (*models.Chapters, error)
+	// GetChaptersCtx is like GetChapters but carries the given context through to the API call.
+	GetChaptersCtx(ctx context.Context, input models.GetChaptersRequest) (*models.Chapters, error)
 }
 
 // DefaultChapterService is a struct that implements ChapterService interface.
@@ -33,76 +36,46 @@ func NewDefaultChapterService(client *utils.HttpClient) *DefaultChapterService {
 
 // GetChapter implements the ChapterService's interface GetChapter method.
 func (service *DefaultChapterService) GetChapter(input models.GetChapterRequest) (*models.Chapter, error) {
+	return service.GetChapterCtx(context.Background(), input)
+}
+
+// GetChapterCtx implements the ChapterService's interface GetChapterCtx method.
+func (service *DefaultChapterService) GetChapterCtx(ctx context.Context, input models.GetChapterRequest) (*models.Chapter, error) {
 	// Validate the input
 	if input.Id == "" {
-		return nil, &utils.AppError{Status: http.StatusBadRequest, Message: consts.MsgIdRequired}
+		return nil, utils.NewError(http.StatusBadRequest, consts.MsgIdRequired, nil)
 	}
 
 	// Substitute id in the endpoint
 	endpoint := fmt.Sprintf(consts.EndpointGetChapter, input.Id)
 
-	// Make an API call
-	res, err := service.client.Get(context.Background(), endpoint, nil)
-	if err != nil {
-		return nil, &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToGetChapter, Err: err}
-	}
-
-	// Handle Spotify API error
-	if res.StatusCode != http.StatusOK {
-		return nil, utils.ParseSpotifyError(res, utils.RegErrorType)
-	}
-
-	// Read the response body
-	data, err := io.ReadAll(res.Body)
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
-
-	// Unmarshal the response data into Chapter struct
-	var chapter models.Chapter
-	if err := json.Unmarshal(data, &chapter); err != nil {
-		return nil, err
-	}
-
-	// Return the Chapter
-	return &chapter, nil
+	// Make an API call and decode the response data into Chapter struct
+	return doJSON[models.Chapter](ctx, service.client, http.MethodGet, endpoint, nil, nil, consts.MsgFailedToGetChapter)
 }
 
 // GetChapters implements the ChapterService's interface GetChapters method.
 func (service *DefaultChapterService) GetChapters(input models.GetChaptersRequest) (*models.Chapters, error) {
-	// Validate the input
-	if input.Ids == "" {
-		return nil, &utils.AppError{Status: http.StatusBadRequest, Message: consts.MsgIdsRequired}
-	}
-
-	// Add inputs to the query parameters
-	params := map[string]string{"ids": input.Ids, "market": input.Market}
-
-	// Make an API call
-	res, err := service.client.Get(context.Background(), consts.EndpointGetChapters, params)
-	if err != nil {
-		return nil, &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToGetChapters, Err: err}
-	}
-
-	// Handle Spotify API error
-	if res.StatusCode != http.StatusOK {
-		return nil, utils.ParseSpotifyError(res, utils.RegErrorType)
-	}
-
-	// Read the response body
-	data, err := io.ReadAll(res.Body)
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
+	return service.GetChaptersCtx(context.Background(), input)
+}
 
-	// Unmarshal the response data into Chapters struct
-	var chapters models.Chapters
-	if err := json.Unmarshal(data, &chapters); err != nil {
+// GetChaptersCtx implements the ChapterService's interface GetChaptersCtx method.
+func (service *DefaultChapterService) GetChaptersCtx(ctx context.Context, input models.GetChaptersRequest) (*models.Chapters, error) {
+	// Validate the input
+	if err := validateIds(input.Ids); err != nil {
 		return nil, err
 	}
 
-	// Return the Chapters
-	return &chapters, nil
+	// Make an API call per chunk of IDs and merge the responses
+	return getChunked(input.Ids, consts.MaxChapterIds,
+		func(ids []spotifyid.ID) (*models.Chapters, error) {
+			// Add inputs to the query parameters
+			params := map[string]string{"ids": join(ids), "market": input.Market}
+
+			// Make an API call and decode the response data into Chapters struct
+			return doJSON[models.Chapters](ctx, service.client, http.MethodGet, consts.EndpointGetChapters, params, nil, consts.MsgFailedToGetChapters)
+		},
+		func(result, chunk *models.Chapters) {
+			result.Chapters = append(result.Chapters, chunk.Chapters...)
+		},
+	)
 }
@@ -0,0 +1,96 @@
+package apis
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/alicse3/gospotify/consts"
+	"github.com/alicse3/gospotify/utils"
+)
+
+// validateIds makes sure at least one ID is given and none of them is empty.
+func validateIds[S ~string](ids []S) error {
+	if len(ids) == 0 {
+		return utils.NewError(http.StatusBadRequest, consts.MsgIdsRequired, nil)
+	}
+
+	return validateNotEmpty(ids, consts.MsgEmptyId)
+}
+
+// validateNotEmpty makes sure none of the given IDs or URIs is empty.
+func validateNotEmpty[S ~string](values []S, message string) error {
+	for i, value := range values {
+		if value == "" {
+			return utils.NewError(http.StatusBadRequest, message, fmt.Errorf("value at index %d is empty", i))
+		}
+	}
+
+	return nil
+}
+
+// validateMaxCount makes sure no more than max IDs, URIs or seeds are given to an API call which can't be split.
+func validateMaxCount(count, max int, message string) error {
+	if count > max {
+		return utils.NewError(http.StatusBadRequest, message, fmt.Errorf("%d given, the maximum is %d", count, max))
+	}
+
+	return nil
+}
+
+// chunks splits the ids into consecutive chunks of at most size IDs each.
+func chunks[S ~string](ids []S, size int) [][]S {
+	var result [][]S
+	for len(ids) > size {
+		result = append(result, ids[:size:size])
+		ids = ids[size:]
+	}
+
+	return append(result, ids)
+}
+
+// getChunked gets the ids in chunks of at most size IDs with get, one API call per chunk,
+// and merges the results of the chunks in order with merge. It stops at the first failed chunk.
+func getChunked[T any, S ~string](ids []S, size int, get func(ids []S) (*T, error), merge func(result, chunk *T)) (*T, error) {
+	var result *T
+	for _, chunk := range chunks(ids, size) {
+		r, err := get(chunk)
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case result == nil:
+			result = r
+		case r != nil:
+			merge(result, r)
+		}
+	}
+
+	return result, nil
+}
+
+// doChunked sends the ids in chunks of at most size IDs with send, one API call per chunk.
+// It stops at the first failed chunk, the chunks before it have been applied already.
+func doChunked[S ~string](ids []S, size int, send func(ids []S) error) error {
+	for _, chunk := range chunks(ids, size) {
+		if err := send(chunk); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// join joins the IDs or URIs into the comma-separated list expected in the query parameters.
+func join[S ~string](values []S) string {
+	var sb strings.Builder
+	for i, value := range values {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteString(string(value))
+	}
+
+	return sb.String()
+}
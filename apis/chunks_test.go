@@ -0,0 +1,119 @@
+package apis
+
+import (
+	"errors"
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/alicse3/gospotify/spotifyid"
+)
+
+// chunkIds returns the IDs 1, 2 and so on.
+func chunkIds(n int) []spotifyid.ID {
+	ids := make([]spotifyid.ID, n)
+	for i := range ids {
+		ids[i] = spotifyid.ID(strconv.Itoa(i + 1))
+	}
+	return ids
+}
+
+// chunkSizes returns the sizes of the chunks.
+func chunkSizes(chunks [][]spotifyid.ID) []int {
+	sizes := make([]int, len(chunks))
+	for i, chunk := range chunks {
+		sizes[i] = len(chunk)
+	}
+	return sizes
+}
+
+func TestGetChunked(t *testing.T) {
+	errChunk := errors.New("chunk failed")
+
+	tests := []struct {
+		name      string
+		ids       int
+		failAt    int
+		wantSizes []int
+		wantErr   error
+	}{
+		{name: "single id", ids: 1, wantSizes: []int{1}},
+		{name: "full chunk", ids: 100, wantSizes: []int{100}},
+		{name: "one over a chunk", ids: 101, wantSizes: []int{100, 1}},
+		{name: "two full chunks", ids: 200, wantSizes: []int{100, 100}},
+		{name: "one over two chunks", ids: 201, wantSizes: []int{100, 100, 1}},
+		{name: "stops at the failed chunk", ids: 250, failAt: 2, wantSizes: []int{100, 100}, wantErr: errChunk},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sent [][]spotifyid.ID
+			get := func(ids []spotifyid.ID) (*[]spotifyid.ID, error) {
+				sent = append(sent, ids)
+				if len(sent) == tt.failAt {
+					return nil, errChunk
+				}
+				return &ids, nil
+			}
+			merge := func(result, chunk *[]spotifyid.ID) {
+				*result = append(*result, *chunk...)
+			}
+
+			got, err := getChunked(chunkIds(tt.ids), 100, get, merge)
+			if !slices.Equal(chunkSizes(sent), tt.wantSizes) {
+				t.Errorf("chunk sizes = %v, want %v", chunkSizes(sent), tt.wantSizes)
+			}
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) || got != nil {
+					t.Errorf("getChunked() = %v, %v, want %v", got, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("getChunked() error = %v", err)
+			}
+			if !slices.Equal(*got, chunkIds(tt.ids)) {
+				t.Errorf("getChunked() merged %d IDs out of order, want %d in order", len(*got), tt.ids)
+			}
+		})
+	}
+}
+
+func TestDoChunked(t *testing.T) {
+	errChunk := errors.New("chunk failed")
+
+	tests := []struct {
+		name      string
+		ids       int
+		failAt    int
+		wantSizes []int
+	}{
+		{name: "full chunk", ids: 100, wantSizes: []int{100}},
+		{name: "one over a chunk", ids: 101, wantSizes: []int{100, 1}},
+		{name: "one over two chunks", ids: 201, wantSizes: []int{100, 100, 1}},
+		{name: "stops at the failed chunk", ids: 201, failAt: 1, wantSizes: []int{100}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sent [][]spotifyid.ID
+			err := doChunked(chunkIds(tt.ids), 100, func(ids []spotifyid.ID) error {
+				sent = append(sent, ids)
+				if len(sent) == tt.failAt {
+					return errChunk
+				}
+				return nil
+			})
+
+			if !slices.Equal(chunkSizes(sent), tt.wantSizes) {
+				t.Errorf("chunk sizes = %v, want %v", chunkSizes(sent), tt.wantSizes)
+			}
+			if (tt.failAt > 0) != errors.Is(err, errChunk) {
+				t.Errorf("doChunked() error = %v, want an error only for a failed chunk", err)
+			}
+			if sent := slices.Concat(sent...); !slices.Equal(sent, chunkIds(tt.ids)[:len(sent)]) {
+				t.Errorf("doChunked() sent the IDs out of order")
+			}
+		})
+	}
+}
@@ -2,14 +2,14 @@ package apis
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"io"
+	"iter"
 	"net/http"
 	"strconv"
 
 	"github.com/alicse3/gospotify/consts"
 	"github.com/alicse3/gospotify/models"
+	"github.com/alicse3/gospotify/spotifyid"
 	"github.com/alicse3/gospotify/utils"
 )
 
@@ -18,34 +18,55 @@ type EpisodeService interface {
 	// Get Spotify catalog information for a single episode identified by its unique Spotify ID.
 	// Authorization scopes: user-read-playback-position
 	GetEpisode(input models.GetEpisodeRequest) (*models.Episode, error)
+	// GetEpisodeCtx is like GetEpisode but carries the given context through to the API call.
+	GetEpisodeCtx(ctx context.Context, input models.GetEpisodeRequest) (*models.Episode, error)
 
 	// Get Spotify catalog information for several episodes based on their Spotify IDs.
 	// Authorization scopes: user-read-playback-position
 	GetEpisodes(input models.GetEpisodesRequest) (*models.Episodes, error)
+	// GetEpisodesCtx is like GetEpisodes but carries the given context through to the API call.
+	GetEpisodesCtx(ctx context.Context, input models.GetEpisodesRequest) (*models.Episodes, error)
+
+	// GetEpisodesBatch gets any number of episodes, splitting the IDs into chunks of the maximum size of GetEpisodes which are fetched concurrently,
+	// at most concurrency chunks at a time, 0 means a default of 4. The items are in the order of the IDs, nil for the unknown ones.
+	// The failed chunks are reported in the Failures of the result, along with an error joining theirs.
+	GetEpisodesBatch(ctx context.Context, input models.GetEpisodesRequest, concurrency int) (*BatchResult[models.Episode], error)
 
 	// Get a list of the episodes saved in the current Spotify user's library.
 	// This API endpoint is in beta and could change without warning.
 	// Please share any feedback that you have, or issues that you discover, in Spotify developer community forum.
 	// Authorization scopes: user-library-read, user-read-playback-position
 	GetSavedEpisodes(input models.GetSavedEpisodesRequest) (*models.SavedEpisodes, error)
+	// GetSavedEpisodesCtx is like GetSavedEpisodes but carries the given context through to the API call.
+	GetSavedEpisodesCtx(ctx context.Context, input models.GetSavedEpisodesRequest) (*models.SavedEpisodes, error)
+
+	// AllSavedEpisodes yields the saved episodes one by one, walking all the pages of GetSavedEpisodes starting at input.Offset.
+	// At most maxItems items are yielded, 0 means there is no cap.
+	AllSavedEpisodes(ctx context.Context, input models.GetSavedEpisodesRequest, maxItems int) iter.Seq2[models.SavedEpisode, error]
 
 	// Save one or more episodes to the current user's library.
 	// This API endpoint is in beta and could change without warning.
 	// Please share any feedback that you have, or issues that you discover, in Spotify developer community forum.
 	// Authorization scopes: user-library-modify
 	SaveEpisodes(input models.SaveEpisodesRequest) error
+	// SaveEpisodesCtx is like SaveEpisodes but carries the given context through to the API call.
+	SaveEpisodesCtx(ctx context.Context, input models.SaveEpisodesRequest) error
 
 	// Remove one or more episodes from the current user's library.
 	// This API endpoint is in beta and could change without warning.
 	// Please share any feedback that you have, or issues that you discover, in Spotify developer community forum.
 	// Authorization scopes: user-library-modify
 	RemoveEpisodes(input models.RemoveEpisodesRequest) error
+	// RemoveEpisodesCtx is like RemoveEpisodes but carries the given context through to the API call.
+	RemoveEpisodesCtx(ctx context.Context, input models.RemoveEpisodesRequest) error
 
 	// Check if one or more episodes is already saved in the current Spotify user's 'Your Episodes' library.
 	// This API endpoint is in beta and could change without warning.
 	// Please share any feedback that you have, or issues that you discover, in Spotify developer community forum.
 	// Authorization scopes: user-library-read
 	CheckSavedEpisodes(input models.CheckSavedEpisodesRequest) (*models.CheckSavedEpisodes, error)
+	// CheckSavedEpisodesCtx is like CheckSavedEpisodes but carries the given context through to the API call.
+	CheckSavedEpisodesCtx(ctx context.Context, input models.CheckSavedEpisodesRequest) (*models.CheckSavedEpisodes, error)
 }
 
 // DefaultEpisodeService is a struct that implements EpisodeService interface.
@@ -60,200 +81,185 @@ func NewDefaultEpisodeService(client *utils.HttpClient) *DefaultEpisodeService {
 
 // GetEpisode implements the EpisodeService's interface GetEpisode method.
 func (service *DefaultEpisodeService) GetEpisode(input models.GetEpisodeRequest) (*models.Episode, error) {
+	return service.GetEpisodeCtx(context.Background(), input)
+}
+
+// GetEpisodeCtx implements the EpisodeService's interface GetEpisodeCtx method.
+func (service *DefaultEpisodeService) GetEpisodeCtx(ctx context.Context, input models.GetEpisodeRequest) (*models.Episode, error) {
 	// Validate the input
 	if input.Id == "" {
-		return nil, &utils.AppError{Status: http.StatusBadRequest, Message: consts.MsgIdRequired}
+		return nil, utils.NewError(http.StatusBadRequest, consts.MsgIdRequired, nil)
 	}
 
 	// Substitute id in the endpoint
 	endpoint := fmt.Sprintf(consts.EndpointEpisode, input.Id)
 
 	// Add inputs to the query parameters
-	params := map[string]string{"id": input.Id, "market": input.Market}
-
-	// Make an API call
-	res, err := service.client.Get(context.Background(), endpoint, params)
-	if err != nil {
-		return nil, &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToGetEpisode, Err: err}
-	}
-
-	// Handle Spotify API error
-	if res.StatusCode != http.StatusOK {
-		return nil, utils.ParseSpotifyError(res, utils.RegErrorType)
-	}
+	params := map[string]string{"id": string(input.Id), "market": input.Market}
 
-	// Read the response body
-	data, err := io.ReadAll(res.Body)
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
-
-	// Unmarshal the response data into Episode struct
-	var episode models.Episode
-	if err := json.Unmarshal(data, &episode); err != nil {
-		return nil, err
-	}
-
-	// Return the Episode
-	return &episode, nil
+	// Make an API call and decode the response data into Episode struct
+	return doJSON[models.Episode](ctx, service.client, http.MethodGet, endpoint, params, nil, consts.MsgFailedToGetEpisode)
 }
 
 // GetEpisodes implements the EpisodeService's interface GetEpisodes method.
 func (service *DefaultEpisodeService) GetEpisodes(input models.GetEpisodesRequest) (*models.Episodes, error) {
-	// Validate the input
-	if input.Ids == "" {
-		return nil, &utils.AppError{Status: http.StatusBadRequest, Message: consts.MsgIdsRequired}
-	}
-
-	// Add inputs to the query parameters
-	params := map[string]string{"ids": input.Ids, "market": input.Market}
+	return service.GetEpisodesCtx(context.Background(), input)
+}
 
-	// Make an API call
-	res, err := service.client.Get(context.Background(), consts.EndpointEpisodes, params)
-	if err != nil {
-		return nil, &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToGetEpisodes, Err: err}
+// GetEpisodesCtx implements the EpisodeService's interface GetEpisodesCtx method.
+func (service *DefaultEpisodeService) GetEpisodesCtx(ctx context.Context, input models.GetEpisodesRequest) (*models.Episodes, error) {
+	// Validate the input
+	if err := validateIds(input.Ids); err != nil {
+		return nil, err
 	}
 
-	// Handle Spotify API error
-	if res.StatusCode != http.StatusOK {
-		return nil, utils.ParseSpotifyError(res, utils.RegErrorType)
-	}
+	// Make an API call per chunk of IDs and merge the responses
+	return getChunked(input.Ids, consts.MaxEpisodeIds,
+		func(ids []spotifyid.ID) (*models.Episodes, error) {
+			// Add inputs to the query parameters
+			params := map[string]string{"ids": join(ids), "market": input.Market}
+
+			// Make an API call and decode the response data into Episodes struct
+			return doJSON[models.Episodes](ctx, service.client, http.MethodGet, consts.EndpointEpisodes, params, nil, consts.MsgFailedToGetEpisodes)
+		},
+		func(result, chunk *models.Episodes) {
+			result.Episodes = append(result.Episodes, chunk.Episodes...)
+		},
+	)
+}
 
-	// Read the response body
-	data, err := io.ReadAll(res.Body)
-	if err != nil {
+// GetEpisodesBatch implements the EpisodeService's interface GetEpisodesBatch method.
+func (service *DefaultEpisodeService) GetEpisodesBatch(ctx context.Context, input models.GetEpisodesRequest, concurrency int) (*BatchResult[models.Episode], error) {
+	// Validate the input
+	if err := validateIds(input.Ids); err != nil {
 		return nil, err
 	}
-	defer res.Body.Close()
 
-	// Unmarshal the response data into Episodes struct
-	var episodes models.Episodes
-	if err := json.Unmarshal(data, &episodes); err != nil {
-		return nil, err
-	}
+	// Fetch the chunks of IDs concurrently
+	fetch := batchFetcher[models.Episode](service.client, consts.EndpointEpisodes, "episodes", map[string]string{"market": input.Market}, consts.MsgFailedToGetEpisodes)
+	result := FetchBatch(ctx, input.Ids, consts.MaxEpisodeIds, concurrency, fetch)
 
-	// Return the Episodes
-	return &episodes, nil
+	return result, result.Err()
 }
 
 // GetSavedEpisodes implements the EpisodeService's interface GetSavedEpisodes method.
 func (service *DefaultEpisodeService) GetSavedEpisodes(input models.GetSavedEpisodesRequest) (*models.SavedEpisodes, error) {
-	// Add inputs to the query parameters
-	params := map[string]string{"market": input.Market, "limit": strconv.Itoa(input.Limit), "offset": strconv.Itoa(input.Offset)}
-
-	// Make an API call
-	res, err := service.client.Get(context.Background(), consts.EndpointMyEpisodes, params)
-	if err != nil {
-		return nil, &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToGetSavedEpisodes, Err: err}
-	}
-
-	// Handle Spotify API error
-	if res.StatusCode != http.StatusOK {
-		return nil, utils.ParseSpotifyError(res, utils.RegErrorType)
-	}
+	return service.GetSavedEpisodesCtx(context.Background(), input)
+}
 
-	// Read the response body
-	data, err := io.ReadAll(res.Body)
-	if err != nil {
+// GetSavedEpisodesCtx implements the EpisodeService's interface GetSavedEpisodesCtx method.
+func (service *DefaultEpisodeService) GetSavedEpisodesCtx(ctx context.Context, input models.GetSavedEpisodesRequest) (*models.SavedEpisodes, error) {
+	// Check the granted scopes
+	if err := checkScopes(service.client, "EpisodeService.GetSavedEpisodes"); err != nil {
 		return nil, err
 	}
-	defer res.Body.Close()
 
-	// Unmarshal the response data into SavedEpisodes struct
-	var savedEpisodes models.SavedEpisodes
-	if err := json.Unmarshal(data, &savedEpisodes); err != nil {
-		return nil, err
-	}
+	// Add inputs to the query parameters
+	params := map[string]string{"market": input.Market, "limit": strconv.Itoa(input.Limit), "offset": strconv.Itoa(input.Offset)}
+
+	// Make an API call and decode the response data into SavedEpisodes struct
+	return doJSON[models.SavedEpisodes](ctx, service.client, http.MethodGet, consts.EndpointMyEpisodes, params, nil, consts.MsgFailedToGetSavedEpisodes)
+}
 
-	// Return the SavedEpisodes
-	return &savedEpisodes, nil
+// AllSavedEpisodes implements the EpisodeService's interface AllSavedEpisodes method.
+func (service *DefaultEpisodeService) AllSavedEpisodes(ctx context.Context, input models.GetSavedEpisodesRequest, maxItems int) iter.Seq2[models.SavedEpisode, error] {
+	// Default to the maximum page size
+	input.Limit = pageLimit(input.Limit)
+
+	// Walk the pages, fetching each one from its offset
+	return Paginate(ctx, input.Offset, maxItems,
+		func(ctx context.Context, offset int) (*models.SavedEpisodes, error) {
+			input.Offset = offset
+			return service.GetSavedEpisodesCtx(ctx, input)
+		},
+		func(page *models.SavedEpisodes) ([]models.SavedEpisode, string) {
+			return page.Items, page.Next
+		},
+	)
 }
 
 // SaveEpisodes implements the EpisodeService's interface SaveEpisodes method.
 func (service *DefaultEpisodeService) SaveEpisodes(input models.SaveEpisodesRequest) error {
-	// Validate the input
-	if input.Ids == "" {
-		return &utils.AppError{Status: http.StatusBadRequest, Message: consts.MsgIdsRequired}
-	}
-
-	// Add inputs to the query parameters
-	params := map[string]string{"ids": input.Ids}
+	return service.SaveEpisodesCtx(context.Background(), input)
+}
 
-	// Make an API call
-	res, err := service.client.Put(context.Background(), consts.EndpointMyEpisodes, nil, params, nil)
-	if err != nil {
-		return &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToSaveEpisodes, Err: err}
+// SaveEpisodesCtx implements the EpisodeService's interface SaveEpisodesCtx method.
+func (service *DefaultEpisodeService) SaveEpisodesCtx(ctx context.Context, input models.SaveEpisodesRequest) error {
+	// Check the granted scopes
+	if err := checkScopes(service.client, "EpisodeService.SaveEpisodes"); err != nil {
+		return err
 	}
 
-	// Handle Spotify API error
-	if res.StatusCode != http.StatusOK {
-		return utils.ParseSpotifyError(res, utils.RegErrorType)
+	// Validate the input
+	if err := validateIds(input.Ids); err != nil {
+		return err
 	}
 
-	// Return the empty response
-	return nil
+	// Make an API call per chunk of IDs
+	return doChunked(input.Ids, consts.MaxEpisodeIds, func(ids []spotifyid.ID) error {
+		// Add inputs to the query parameters
+		params := map[string]string{"ids": join(ids)}
+
+		// Make an API call and discard the response body
+		return doEmpty(ctx, service.client, http.MethodPut, consts.EndpointMyEpisodes, params, nil, consts.MsgFailedToSaveEpisodes)
+	})
 }
 
 // RemoveEpisodes implements the EpisodeService's interface RemoveEpisodes method.
 func (service *DefaultEpisodeService) RemoveEpisodes(input models.RemoveEpisodesRequest) error {
-	// Validate the input
-	if input.Ids == "" {
-		return &utils.AppError{Status: http.StatusBadRequest, Message: consts.MsgIdsRequired}
-	}
-
-	// Add inputs to the query parameters
-	params := map[string]string{"ids": input.Ids}
+	return service.RemoveEpisodesCtx(context.Background(), input)
+}
 
-	// Make an API call
-	res, err := service.client.Delete(context.Background(), consts.EndpointMyEpisodes, nil, params, nil)
-	if err != nil {
-		return &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToRemoveEpisodes, Err: err}
+// RemoveEpisodesCtx implements the EpisodeService's interface RemoveEpisodesCtx method.
+func (service *DefaultEpisodeService) RemoveEpisodesCtx(ctx context.Context, input models.RemoveEpisodesRequest) error {
+	// Check the granted scopes
+	if err := checkScopes(service.client, "EpisodeService.RemoveEpisodes"); err != nil {
+		return err
 	}
 
-	// Handle Spotify API error
-	if res.StatusCode != http.StatusOK {
-		return utils.ParseSpotifyError(res, utils.RegErrorType)
+	// Validate the input
+	if err := validateIds(input.Ids); err != nil {
+		return err
 	}
 
-	// Return the empty response
-	return nil
+	// Make an API call per chunk of IDs
+	return doChunked(input.Ids, consts.MaxEpisodeIds, func(ids []spotifyid.ID) error {
+		// Add inputs to the query parameters
+		params := map[string]string{"ids": join(ids)}
+
+		// Make an API call and discard the response body
+		return doEmpty(ctx, service.client, http.MethodDelete, consts.EndpointMyEpisodes, params, nil, consts.MsgFailedToRemoveEpisodes)
+	})
 }
 
 // CheckSavedEpisodes implements the EpisodeService's interface CheckSavedEpisodes method.
 func (service *DefaultEpisodeService) CheckSavedEpisodes(input models.CheckSavedEpisodesRequest) (*models.CheckSavedEpisodes, error) {
-	// Validate the input
-	if input.Ids == "" {
-		return nil, &utils.AppError{Status: http.StatusBadRequest, Message: consts.MsgIdsRequired}
-	}
-
-	// Add inputs to the query parameters
-	params := map[string]string{"id": input.Ids}
-
-	// Make an API call
-	res, err := service.client.Get(context.Background(), consts.EndpointCheckMyEpisodes, params)
-	if err != nil {
-		return nil, &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToCheckSavedEpisodes, Err: err}
-	}
-
-	// Handle Spotify API error
-	if res.StatusCode != http.StatusOK {
-		return nil, utils.ParseSpotifyError(res, utils.RegErrorType)
-	}
+	return service.CheckSavedEpisodesCtx(context.Background(), input)
+}
 
-	// Read the response body
-	data, err := io.ReadAll(res.Body)
-	if err != nil {
+// CheckSavedEpisodesCtx implements the EpisodeService's interface CheckSavedEpisodesCtx method.
+func (service *DefaultEpisodeService) CheckSavedEpisodesCtx(ctx context.Context, input models.CheckSavedEpisodesRequest) (*models.CheckSavedEpisodes, error) {
+	// Check the granted scopes
+	if err := checkScopes(service.client, "EpisodeService.CheckSavedEpisodes"); err != nil {
 		return nil, err
 	}
-	defer res.Body.Close()
 
-	// Unmarshal the response data into CheckSavedEpisodes struct
-	var checkSavedEpisodes models.CheckSavedEpisodes
-	if err := json.Unmarshal(data, &checkSavedEpisodes); err != nil {
+	// Validate the input
+	if err := validateIds(input.Ids); err != nil {
 		return nil, err
 	}
 
-	// Return the CheckSavedEpisodes
-	return &checkSavedEpisodes, nil
+	// Make an API call per chunk of IDs and merge the responses
+	return getChunked(input.Ids, consts.MaxEpisodeIds,
+		func(ids []spotifyid.ID) (*models.CheckSavedEpisodes, error) {
+			// Add inputs to the query parameters
+			params := map[string]string{"ids": join(ids)}
+
+			// Make an API call and decode the response data into CheckSavedEpisodes struct
+			return doJSON[models.CheckSavedEpisodes](ctx, service.client, http.MethodGet, consts.EndpointCheckMyEpisodes, params, nil, consts.MsgFailedToCheckSavedEpisodes)
+		},
+		func(result, chunk *models.CheckSavedEpisodes) {
+			*result = append(*result, *chunk...)
+		},
+	)
 }
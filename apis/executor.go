@@ -0,0 +1,83 @@
+package apis
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/alicse3/gospotify/consts"
+	"github.com/alicse3/gospotify/utils"
+)
+
+// doJSON sends a request to the Spotify API and decodes the JSON response data into a new T.
+// It returns an empty T for a 204 No Content response, so the callers never get a nil result without an error.
+// The failure message describes the error if the request can't be sent, e.g. consts.MsgFailedToGetTrack.
+func doJSON[T any](ctx context.Context, client *utils.HttpClient, method, endpoint string, queryParams map[string]string, body any, failure string) (*T, error) {
+	v, err := doOptionalJSON[T](ctx, client, method, endpoint, queryParams, body, failure)
+	if err == nil && v == nil {
+		v = new(T)
+	}
+
+	return v, err
+}
+
+// doOptionalJSON is like doJSON, but returns nil without an error for a 204 No Content response.
+// It's meant for the player endpoints which document it, e.g. when nothing is being played.
+func doOptionalJSON[T any](ctx context.Context, client *utils.HttpClient, method, endpoint string, queryParams map[string]string, body any, failure string) (*T, error) {
+	// Make an API call
+	res, err := send(ctx, client, method, endpoint, queryParams, body, failure)
+	if err != nil {
+		return nil, err
+	}
+
+	// Handle the response without content
+	if res.StatusCode == http.StatusNoContent {
+		res.Body.Close()
+		return nil, nil
+	}
+
+	// Handle Spotify API error and decode the response data into T
+	var v T
+	if err := utils.DecodeResponse(res, utils.RegErrorType, &v); err != nil {
+		return nil, err
+	}
+
+	return &v, nil
+}
+
+// doEmpty sends a request to the Spotify API whose response has no data of interest, and discards the response body.
+// The failure message describes the error if the request can't be sent, e.g. consts.MsgFailedToSaveTracks.
+func doEmpty(ctx context.Context, client *utils.HttpClient, method, endpoint string, queryParams map[string]string, body any, failure string) error {
+	// Make an API call
+	res, err := send(ctx, client, method, endpoint, queryParams, body, failure)
+	if err != nil {
+		return err
+	}
+
+	// Handle Spotify API error and discard the response body
+	return utils.DecodeResponse(res, utils.RegErrorType, nil)
+}
+
+// send is the single place all the services send their requests to the Spotify API through.
+// The body, if not nil, is sent as JSON, except for GET requests which have none.
+func send(ctx context.Context, client *utils.HttpClient, method, endpoint string, queryParams map[string]string, body any, failure string) (*http.Response, error) {
+	var res *http.Response
+	var err error
+
+	switch method {
+	case http.MethodGet:
+		res, err = client.Get(ctx, endpoint, queryParams)
+	case http.MethodPost:
+		res, err = client.Post(ctx, endpoint, nil, queryParams, nil, body)
+	case http.MethodPut:
+		res, err = client.Put(ctx, endpoint, nil, queryParams, body)
+	case http.MethodDelete:
+		res, err = client.Delete(ctx, endpoint, nil, queryParams, body)
+	default:
+		return nil, utils.NewError(http.StatusInternalServerError, consts.MsgUnsupportedMethod, nil)
+	}
+	if err != nil {
+		return nil, utils.WrapError(http.StatusInternalServerError, failure, err)
+	}
+
+	return res, nil
+}
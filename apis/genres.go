@@ -2,8 +2,6 @@ package apis
 
 import (
 	"context"
-	"encoding/json"
-	"io"
 	"net/http"
 
 	"github.com/alicse3/gospotify/consts"
@@ -15,6 +13,8 @@ import (
 type GenreService interface {
 	// Retrieve a list of available genres seed parameter values for recommendations.
 	GetAvailableGenresSeeds() (*models.Genres, error)
+	// GetAvailableGenresSeedsCtx is like GetAvailableGenresSeeds but carries the given context through to the API call.
+	GetAvailableGenresSeedsCtx(ctx context.Context) (*models.Genres, error)
 }
 
 // DefaultGenreService is a struct that implements GenreService interface.
@@ -29,30 +29,11 @@ func NewDefaultGenreService(client *utils.HttpClient) *DefaultGenreService {
 
 // GetAvailableGenresSeeds implements the DefaultGenreService's interface GetAvailableGenresSeeds method.
 func (service *DefaultGenreService) GetAvailableGenresSeeds() (*models.Genres, error) {
-	// Make an API call
-	res, err := service.client.Get(context.Background(), consts.EndpointGetAvailableGenreSeeds, nil)
-	if err != nil {
-		return nil, &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToGetAvailableGenreSeeds, Err: err}
-	}
-
-	// Handle Spotify API error
-	if res.StatusCode != http.StatusOK {
-		return nil, utils.ParseSpotifyError(res, utils.RegErrorType)
-	}
-
-	// Read the response body
-	data, err := io.ReadAll(res.Body)
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
-
-	// Unmarshal the response data into Genres struct
-	var genres models.Genres
-	if err := json.Unmarshal(data, &genres); err != nil {
-		return nil, err
-	}
+	return service.GetAvailableGenresSeedsCtx(context.Background())
+}
 
-	// Return the Genres
-	return &genres, nil
+// GetAvailableGenresSeedsCtx implements the DefaultGenreService's interface GetAvailableGenresSeedsCtx method.
+func (service *DefaultGenreService) GetAvailableGenresSeedsCtx(ctx context.Context) (*models.Genres, error) {
+	// Make an API call and decode the response data into Genres struct
+	return doJSON[models.Genres](ctx, service.client, http.MethodGet, consts.EndpointGetAvailableGenreSeeds, nil, nil, consts.MsgFailedToGetAvailableGenreSeeds)
 }
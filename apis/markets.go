@@ -2,8 +2,6 @@ package apis
 
 import (
 	"context"
-	"encoding/json"
-	"io"
 	"net/http"
 
 	"github.com/alicse3/gospotify/consts"
@@ -15,6 +13,8 @@ import (
 type MarketService interface {
 	// Get the list of markets where Spotify is available.
 	GetAvailableMarkets() (*models.Markets, error)
+	// GetAvailableMarketsCtx is like GetAvailableMarkets but carries the given context through to the API call.
+	GetAvailableMarketsCtx(ctx context.Context) (*models.Markets, error)
 }
 
 // DefaultMarketService is a struct that implements MarketService interface.
@@ -29,30 +29,11 @@ func NewDefaultMarketService(client *utils.HttpClient) *DefaultMarketService {
 
 // GetAvailableMarkets implements the DefaultMarketService's interface GetAvailableMarkets method.
 func (service *DefaultMarketService) GetAvailableMarkets() (*models.Markets, error) {
-	// Make an API call
-	res, err := service.client.Get(context.Background(), consts.EndpointGetAvailableMarkets, nil)
-	if err != nil {
-		return nil, &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToGetAvailableMarkets, Err: err}
-	}
-
-	// Handle Spotify API error
-	if res.StatusCode != http.StatusOK {
-		return nil, utils.ParseSpotifyError(res, utils.RegErrorType)
-	}
-
-	// Read the response body
-	data, err := io.ReadAll(res.Body)
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
-
-	// Unmarshal the response data into Markets struct
-	var markets models.Markets
-	if err := json.Unmarshal(data, &markets); err != nil {
-		return nil, err
-	}
+	return service.GetAvailableMarketsCtx(context.Background())
+}
 
-	// Return the Markets
-	return &markets, nil
+// GetAvailableMarketsCtx implements the DefaultMarketService's interface GetAvailableMarketsCtx method.
+func (service *DefaultMarketService) GetAvailableMarketsCtx(ctx context.Context) (*models.Markets, error) {
+	// Make an API call and decode the response data into Markets struct
+	return doJSON[models.Markets](ctx, service.client, http.MethodGet, consts.EndpointGetAvailableMarkets, nil, nil, consts.MsgFailedToGetAvailableMarkets)
 }
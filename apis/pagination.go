@@ -0,0 +1,144 @@
+package apis
+
+import (
+	"context"
+	"iter"
+)
+
+const (
+	// default page size used by the iterators when the request doesn't set a limit
+	defaultPageLimit = 50
+)
+
+// PageFetcher fetches a single page of a paged endpoint, starting at the given offset.
+type PageFetcher[P any] func(ctx context.Context, offset int) (*P, error)
+
+// PageItems extracts the items of a page along with the URL of the next page, which is empty on the last page.
+type PageItems[P, T any] func(page *P) (items []T, next string)
+
+// Paginate walks an offset based paged endpoint and yields its items one by one.
+// Pages are fetched lazily, starting at offset and following on with the offset after the last yielded item,
+// until a page has no next page or no items, or there is no page at all.
+// At most maxItems items are yielded, 0 means there is no cap.
+// Errors from fetching a page, including the cancellation of ctx, are yielded once and end the iteration.
+//
+// For example, to walk all the saved tracks of the current user:
+//
+//	for track, err := range apis.Paginate(ctx, 0, 0,
+//		func(ctx context.Context, offset int) (*models.SavedTracks, error) {
+//			return client.TrackService.GetSavedTracksCtx(ctx, models.GetSavedTracksRequest{Limit: 50, Offset: offset})
+//		},
+//		func(page *models.SavedTracks) ([]models.SavedTrack, string) { return page.Items, page.Next },
+//	) {
+//		...
+//	}
+func Paginate[P, T any](ctx context.Context, offset, maxItems int, fetch PageFetcher[P], pageItems PageItems[P, T]) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		count := 0
+
+		for {
+			// Stop early if the caller is no longer interested
+			if err := ctx.Err(); err != nil {
+				yield(zero, err)
+				return
+			}
+
+			// Fetch the page starting at the current offset
+			page, err := fetch(ctx, offset)
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+
+			// A response without content has no more items
+			if page == nil {
+				return
+			}
+
+			// Yield the items of the page
+			items, next := pageItems(page)
+			for _, item := range items {
+				if !yield(item, nil) {
+					return
+				}
+
+				count++
+				if maxItems > 0 && count >= maxItems {
+					return
+				}
+			}
+
+			// Stop at the last page
+			if next == "" || len(items) == 0 {
+				return
+			}
+			offset += len(items)
+		}
+	}
+}
+
+// CursorFetcher fetches a single page of a cursor based endpoint, starting at the given cursor, which is empty for the first page.
+type CursorFetcher[P any] func(ctx context.Context, cursor string) (*P, error)
+
+// CursorItems extracts the items of a page along with the cursor of the next page, which is empty on the last page.
+type CursorItems[P, T any] func(page *P) (items []T, cursor string)
+
+// PaginateCursor walks a cursor based paged endpoint and yields its items one by one.
+// Pages are fetched lazily, starting at cursor and following on with the cursor returned by each page,
+// until a page has no next cursor or no items, or there is no page at all. Whether the walk goes forward or backward depends on the cursor the page returns.
+// At most maxItems items are yielded, 0 means there is no cap.
+// Errors from fetching a page, including the cancellation of ctx, are yielded once and end the iteration.
+func PaginateCursor[P, T any](ctx context.Context, cursor string, maxItems int, fetch CursorFetcher[P], cursorItems CursorItems[P, T]) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		count := 0
+
+		for {
+			// Stop early if the caller is no longer interested
+			if err := ctx.Err(); err != nil {
+				yield(zero, err)
+				return
+			}
+
+			// Fetch the page starting at the current cursor
+			page, err := fetch(ctx, cursor)
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+
+			// A response without content has no more items
+			if page == nil {
+				return
+			}
+
+			// Yield the items of the page
+			items, next := cursorItems(page)
+			for _, item := range items {
+				if !yield(item, nil) {
+					return
+				}
+
+				count++
+				if maxItems > 0 && count >= maxItems {
+					return
+				}
+			}
+
+			// Stop at the last page, or if the cursor doesn't move anymore
+			if next == "" || next == cursor || len(items) == 0 {
+				return
+			}
+			cursor = next
+		}
+	}
+}
+
+// pageLimit returns the given limit, or the default page size if it's not set.
+func pageLimit(limit int) int {
+	if limit <= 0 {
+		return defaultPageLimit
+	}
+	return limit
+}
@@ -2,96 +2,142 @@ package apis
 
 import (
 	"context"
-	"encoding/json"
-	"io"
+	"iter"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/alicse3/gospotify/consts"
 	"github.com/alicse3/gospotify/models"
+	"github.com/alicse3/gospotify/spotifyid"
 	"github.com/alicse3/gospotify/utils"
 )
 
 // PlayerService interface defines the methods for interacting with the Spotify Player's API.
 type PlayerService interface {
 	// Get information about the user’s current playback state, including track or episode, progress, and active device.
+	// It returns nil if there is no active device.
 	// Authorization scopes: user-read-playback-state
 	GetPlaybackState(models.GetPlaybackStateRequest) (*models.PlaybackState, error)
+	// GetPlaybackStateCtx is like GetPlaybackState but carries the given context through to the API call.
+	GetPlaybackStateCtx(context.Context, models.GetPlaybackStateRequest) (*models.PlaybackState, error)
 
 	// Transfer playback to a new device and optionally begin playback. This API only works for users who have Spotify Premium. The order of execution is not guaranteed when you use this API with other Player API endpoints.
 	// Authorization scopes: user-modify-playback-state
 	TransferPlayback(models.TransferPlaybackRequest) error
+	// TransferPlaybackCtx is like TransferPlayback but carries the given context through to the API call.
+	TransferPlaybackCtx(context.Context, models.TransferPlaybackRequest) error
 
 	// Get information about a user’s available Spotify Connect devices. Some device models are not supported and will not be listed in the API response.
 	// Authorization scopes: user-read-playback-state
 	GetAvailableDevices() (*models.AvailableDevices, error)
+	// GetAvailableDevicesCtx is like GetAvailableDevices but carries the given context through to the API call.
+	GetAvailableDevicesCtx(context.Context) (*models.AvailableDevices, error)
 
 	// Get the object currently being played on the user's Spotify account.
+	// It returns nil if nothing is being played.
 	// Authorization scopes: user-read-currently-playing
 	GetCurrentlyPlayingTrack(models.GetCurrentlyPlayingTrackRequest) (*models.PlaybackState, error)
+	// GetCurrentlyPlayingTrackCtx is like GetCurrentlyPlayingTrack but carries the given context through to the API call.
+	GetCurrentlyPlayingTrackCtx(context.Context, models.GetCurrentlyPlayingTrackRequest) (*models.PlaybackState, error)
 
 	// Start a new context or resume current playback on the user's active device.
 	// This API only works for users who have Spotify Premium.
 	// The order of execution is not guaranteed when you use this API with other Player API endpoints.
 	// Authorization scopes: user-modify-playback-state
 	StartOrResumePlayback(models.StartOrResumePlaybackRequest) error
+	// StartOrResumePlaybackCtx is like StartOrResumePlayback but carries the given context through to the API call.
+	StartOrResumePlaybackCtx(context.Context, models.StartOrResumePlaybackRequest) error
 
 	// Pause playback on the user's account.
 	// This API only works for users who have Spotify Premium.
 	// The order of execution is not guaranteed when you use this API with other Player API endpoints.
 	// Authorization scopes: user-modify-playback-state
 	PausePlayback(models.PausePlaybackRequest) error
+	// PausePlaybackCtx is like PausePlayback but carries the given context through to the API call.
+	PausePlaybackCtx(context.Context, models.PausePlaybackRequest) error
 
 	// Skips to next track in the user’s queue.
 	// This API only works for users who have Spotify Premium.
 	// The order of execution is not guaranteed when you use this API with other Player API endpoints.
 	// Authorization scopes: user-modify-playback-state
 	SkipToNext(models.SkipToNextRequest) error
+	// SkipToNextCtx is like SkipToNext but carries the given context through to the API call.
+	SkipToNextCtx(context.Context, models.SkipToNextRequest) error
 
 	// Skips to previous track in the user’s queue.
 	// This API only works for users who have Spotify Premium.
 	// The order of execution is not guaranteed when you use this API with other Player API endpoints.
 	// Authorization scopes: user-modify-playback-state
 	SkipToPrevious(models.SkipToPreviousRequest) error
+	// SkipToPreviousCtx is like SkipToPrevious but carries the given context through to the API call.
+	SkipToPreviousCtx(context.Context, models.SkipToPreviousRequest) error
 
 	// Seeks to the given position in the user’s currently playing track.
 	// This API only works for users who have Spotify Premium.
 	// The order of execution is not guaranteed when you use this API with other Player API endpoints.
 	// Authorization scopes: user-modify-playback-state
 	SeekToPosition(models.SeekToPositionRequest) error
+	// SeekToPositionCtx is like SeekToPosition but carries the given context through to the API call.
+	SeekToPositionCtx(context.Context, models.SeekToPositionRequest) error
 
 	// Set the repeat mode for the user's playback.
 	// This API only works for users who have Spotify Premium.
 	// The order of execution is not guaranteed when you use this API with other Player API endpoints.
 	// Authorization scopes: user-modify-playback-state
 	SetRepeatMode(models.SetRepeatModeRequest) error
+	// SetRepeatModeCtx is like SetRepeatMode but carries the given context through to the API call.
+	SetRepeatModeCtx(context.Context, models.SetRepeatModeRequest) error
 
 	// Set the volume for the user’s current playback device.
 	// This API only works for users who have Spotify Premium.
 	// The order of execution is not guaranteed when you use this API with other Player API endpoints.
 	// Authorization scopes: user-modify-playback-state
 	SetPlaybackVolume(models.SetPlaybackVolumeRequest) error
+	// SetPlaybackVolumeCtx is like SetPlaybackVolume but carries the given context through to the API call.
+	SetPlaybackVolumeCtx(context.Context, models.SetPlaybackVolumeRequest) error
 
 	// Toggle shuffle on or off for user’s playback.
 	// This API only works for users who have Spotify Premium.
 	// The order of execution is not guaranteed when you use this API with other Player API endpoints.
 	// Authorization scopes: user-modify-playback-state
 	TogglePlaybackShuffle(models.TogglePlaybackShuffleRequest) error
+	// TogglePlaybackShuffleCtx is like TogglePlaybackShuffle but carries the given context through to the API call.
+	TogglePlaybackShuffleCtx(context.Context, models.TogglePlaybackShuffleRequest) error
 
 	// Get tracks from the current user's recently played tracks.
+	// Only one of After and Before can be set.
 	// Note: Currently doesn't support podcast episodes.
 	// Authorization scopes: user-read-recently-played
 	GetRecentlyPlayedTracks(models.GetRecentlyPlayedTracksRequest) (*models.RecentlyPlayedTracks, error)
+	// GetRecentlyPlayedTracksCtx is like GetRecentlyPlayedTracks but carries the given context through to the API call.
+	GetRecentlyPlayedTracksCtx(context.Context, models.GetRecentlyPlayedTracksRequest) (*models.RecentlyPlayedTracks, error)
+
+	// AllRecentlyPlayedTracks yields the current user's recently played tracks one by one, walking all the pages of GetRecentlyPlayedTracks.
+	// If After is set, the pages are walked forward in time from that cursor, otherwise backward from Before, or from now if it's not set either.
+	// Setting both After and Before is an error, which is yielded before fetching any page.
+	// At most maxItems items are yielded, 0 means there is no cap.
+	AllRecentlyPlayedTracks(ctx context.Context, input models.GetRecentlyPlayedTracksRequest, maxItems int) iter.Seq2[models.PlayHistory, error]
+
+	// RecentlyPlayedTracksSince yields the tracks the current user played after the given time one by one.
+	// It walks the pages forward in time from since, using the after cursor of the API, so only the plays after it are fetched.
+	// The pages come oldest first, and the items of a page in the order Spotify returns them, most recent first.
+	// Syncing the listening history incrementally only needs the most recent PlayedAt of the previous run.
+	RecentlyPlayedTracksSince(ctx context.Context, since time.Time) iter.Seq2[models.PlayHistory, error]
 
 	// Get the list of objects that make up the user's queue.
 	// Authorization scopes: user-read-currently-playing, user-read-playback-state
 	GetUsersQueue() (*models.UsersQueue, error)
+	// GetUsersQueueCtx is like GetUsersQueue but carries the given context through to the API call.
+	GetUsersQueueCtx(context.Context) (*models.UsersQueue, error)
 
 	// Add an item to the end of the user's current playback queue.
 	// This API only works for users who have Spotify Premium.
 	// The order of execution is not guaranteed when you use this API with other Player API endpoints.
 	// Authorization scopes: user-modify-playback-state
 	AddItemToPlaybackQueue(models.AddItemToPlaybackQueueRequest) error
+	// AddItemToPlaybackQueueCtx is like AddItemToPlaybackQueue but carries the given context through to the API call.
+	AddItemToPlaybackQueueCtx(context.Context, models.AddItemToPlaybackQueueRequest) error
 }
 
 // DefaultPlayerService is a struct that implements PlayerService interface.
@@ -106,311 +152,275 @@ func NewDefaultPlayerService(client *utils.HttpClient) *DefaultPlayerService {
 
 // GetPlaybackState implements the DefaultPlayerService's interface GetPlaybackState method.
 func (service *DefaultPlayerService) GetPlaybackState(input models.GetPlaybackStateRequest) (*models.PlaybackState, error) {
-	// Add inputs to the query parameters
-	params := map[string]string{"market": input.Market}
-	if input.AdditionalTypes != "" {
-		params["additional_types"] = input.AdditionalTypes
-	}
-
-	// Make an API call
-	res, err := service.client.Get(context.Background(), consts.EndpointPlaybackState, params)
-	if err != nil {
-		return nil, &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToGetPlaybackState, Err: err}
-	}
-
-	// Handle Spotify API error
-	if res.StatusCode != http.StatusOK {
-		return nil, utils.ParseSpotifyError(res, utils.RegErrorType)
-	}
+	return service.GetPlaybackStateCtx(context.Background(), input)
+}
 
-	// Read the response body
-	data, err := io.ReadAll(res.Body)
-	if err != nil {
+// GetPlaybackStateCtx implements the DefaultPlayerService's interface GetPlaybackStateCtx method.
+func (service *DefaultPlayerService) GetPlaybackStateCtx(ctx context.Context, input models.GetPlaybackStateRequest) (*models.PlaybackState, error) {
+	// Check the granted scopes
+	if err := checkScopes(service.client, "PlayerService.GetPlaybackState"); err != nil {
 		return nil, err
 	}
-	defer res.Body.Close()
 
-	// Unmarshal the response data into PlaybackState struct
-	var playbackState models.PlaybackState
-	if err := json.Unmarshal(data, &playbackState); err != nil {
-		return nil, err
+	// Add inputs to the query parameters
+	params := map[string]string{"market": input.Market}
+	if input.AdditionalTypes != "" {
+		params["additional_types"] = input.AdditionalTypes
 	}
 
-	// Return the PlaybackState
-	return &playbackState, nil
+	// Make an API call and decode the response data into PlaybackState struct
+	return doOptionalJSON[models.PlaybackState](ctx, service.client, http.MethodGet, consts.EndpointPlaybackState, params, nil, consts.MsgFailedToGetPlaybackState)
 }
 
 // TransferPlayback implements the DefaultPlayerService's interface TransferPlayback method.
 func (service *DefaultPlayerService) TransferPlayback(input models.TransferPlaybackRequest) error {
+	return service.TransferPlaybackCtx(context.Background(), input)
+}
+
+// TransferPlaybackCtx implements the DefaultPlayerService's interface TransferPlaybackCtx method.
+func (service *DefaultPlayerService) TransferPlaybackCtx(ctx context.Context, input models.TransferPlaybackRequest) error {
+	// Check the granted scopes
+	if err := checkScopes(service.client, "PlayerService.TransferPlayback"); err != nil {
+		return err
+	}
+
 	// Validate the input
 	if len(input.Body.DeviceIds) == 0 {
-		return &utils.AppError{Status: http.StatusBadRequest, Message: consts.MsgDeviceIdsRequired}
+		return utils.NewError(http.StatusBadRequest, consts.MsgDeviceIdsRequired, nil)
 	}
 
 	// Add inputs to the body
 	body := &models.TransferPlaybackRequestBody{DeviceIds: input.Body.DeviceIds, Play: input.Body.Play}
 
-	// Make an API call
-	res, err := service.client.Put(context.Background(), consts.EndpointPlaybackState, nil, nil, body)
-	if err != nil {
-		return &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToTransferPlayback, Err: err}
-	}
-
-	// Handle Spotify API error
-	if res.StatusCode != http.StatusNoContent {
-		return utils.ParseSpotifyError(res, utils.RegErrorType)
-	}
-
-	// Return the empty response
-	return nil
+	// Make an API call and discard the response body
+	return doEmpty(ctx, service.client, http.MethodPut, consts.EndpointPlaybackState, nil, body, consts.MsgFailedToTransferPlayback)
 }
 
 // GetAvailableDevices implements the DefaultPlayerService's interface GetAvailableDevices method.
 func (service *DefaultPlayerService) GetAvailableDevices() (*models.AvailableDevices, error) {
-	// Make an API call
-	res, err := service.client.Get(context.Background(), consts.EndpointAvailableDevices, nil)
-	if err != nil {
-		return nil, &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToGetAvailableDevices, Err: err}
-	}
-
-	// Handle Spotify API error
-	if res.StatusCode != http.StatusOK {
-		return nil, utils.ParseSpotifyError(res, utils.RegErrorType)
-	}
-
-	// Read the response body
-	data, err := io.ReadAll(res.Body)
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
+	return service.GetAvailableDevicesCtx(context.Background())
+}
 
-	// Unmarshal the response data into AvailableDevices struct
-	var availableDevices models.AvailableDevices
-	if err := json.Unmarshal(data, &availableDevices); err != nil {
+// GetAvailableDevicesCtx implements the DefaultPlayerService's interface GetAvailableDevicesCtx method.
+func (service *DefaultPlayerService) GetAvailableDevicesCtx(ctx context.Context) (*models.AvailableDevices, error) {
+	// Check the granted scopes
+	if err := checkScopes(service.client, "PlayerService.GetAvailableDevices"); err != nil {
 		return nil, err
 	}
 
-	// Return the AvailableDevices
-	return &availableDevices, nil
+	// Make an API call and decode the response data into AvailableDevices struct
+	return doJSON[models.AvailableDevices](ctx, service.client, http.MethodGet, consts.EndpointAvailableDevices, nil, nil, consts.MsgFailedToGetAvailableDevices)
 }
 
 // GetCurrentlyPlayingTrack implements the DefaultPlayerService's interface GetCurrentlyPlayingTrack method.
 func (service *DefaultPlayerService) GetCurrentlyPlayingTrack(input models.GetCurrentlyPlayingTrackRequest) (*models.PlaybackState, error) {
-	// Add inputs to the query parameters
-	params := map[string]string{"market": input.Market}
-	if input.AdditionalTypes != "" {
-		params["additional_types"] = input.AdditionalTypes
-	}
-
-	// Make an API call
-	res, err := service.client.Get(context.Background(), consts.EndpointCurrentlyPlayingTrack, params)
-	if err != nil {
-		return nil, &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToGetCurrentlyPlayingTrack, Err: err}
-	}
-
-	// Handle Spotify API error
-	if res.StatusCode != http.StatusOK {
-		return nil, utils.ParseSpotifyError(res, utils.RegErrorType)
-	}
+	return service.GetCurrentlyPlayingTrackCtx(context.Background(), input)
+}
 
-	// Read the response body
-	data, err := io.ReadAll(res.Body)
-	if err != nil {
+// GetCurrentlyPlayingTrackCtx implements the DefaultPlayerService's interface GetCurrentlyPlayingTrackCtx method.
+func (service *DefaultPlayerService) GetCurrentlyPlayingTrackCtx(ctx context.Context, input models.GetCurrentlyPlayingTrackRequest) (*models.PlaybackState, error) {
+	// Check the granted scopes
+	if err := checkScopes(service.client, "PlayerService.GetCurrentlyPlayingTrack"); err != nil {
 		return nil, err
 	}
-	defer res.Body.Close()
 
-	// Unmarshal the response data into PlaybackState struct
-	var currentlyPlayingTrack models.PlaybackState
-	if err := json.Unmarshal(data, &currentlyPlayingTrack); err != nil {
-		return nil, err
+	// Add inputs to the query parameters
+	params := map[string]string{"market": input.Market}
+	if input.AdditionalTypes != "" {
+		params["additional_types"] = input.AdditionalTypes
 	}
 
-	// Return the PlaybackState
-	return &currentlyPlayingTrack, nil
+	// Make an API call and decode the response data into PlaybackState struct
+	return doOptionalJSON[models.PlaybackState](ctx, service.client, http.MethodGet, consts.EndpointCurrentlyPlayingTrack, params, nil, consts.MsgFailedToGetCurrentlyPlayingTrack)
 }
 
 // StartOrResumePlayback implements the DefaultPlayerService's interface StartOrResumePlayback method.
 func (service *DefaultPlayerService) StartOrResumePlayback(input models.StartOrResumePlaybackRequest) error {
+	return service.StartOrResumePlaybackCtx(context.Background(), input)
+}
+
+// StartOrResumePlaybackCtx implements the DefaultPlayerService's interface StartOrResumePlaybackCtx method.
+func (service *DefaultPlayerService) StartOrResumePlaybackCtx(ctx context.Context, input models.StartOrResumePlaybackRequest) error {
+	// Check the granted scopes
+	if err := checkScopes(service.client, "PlayerService.StartOrResumePlayback"); err != nil {
+		return err
+	}
+
 	// Add inputs to the query parameters
 	params := map[string]string{"device_id": input.DeviceId}
 
 	// Add inputs to the body
 	body := &models.StartOrResumePlaybackRequestBody{ContextUri: input.Body.ContextUri, Uris: input.Body.Uris, Offset: input.Body.Offset, PositionMs: input.Body.PositionMs}
 
-	// Make an API call
-	res, err := service.client.Put(context.Background(), consts.EndpointStartOrResumePlayback, nil, params, body)
-	if err != nil {
-		return &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToStartOrResumePlayback, Err: err}
-	}
-
-	// Handle Spotify API error
-	if res.StatusCode != http.StatusOK {
-		return utils.ParseSpotifyError(res, utils.RegErrorType)
-	}
-
-	// Return the empty response
-	return nil
+	// Make an API call and discard the response body
+	return doEmpty(ctx, service.client, http.MethodPut, consts.EndpointStartOrResumePlayback, params, body, consts.MsgFailedToStartOrResumePlayback)
 }
 
 // PausePlayback implements the DefaultPlayerService's interface PausePlayback method.
 func (service *DefaultPlayerService) PausePlayback(input models.PausePlaybackRequest) error {
-	// Add inputs to the query parameters
-	params := map[string]string{"device_id": input.DeviceId}
+	return service.PausePlaybackCtx(context.Background(), input)
+}
 
-	// Make an API call
-	res, err := service.client.Put(context.Background(), consts.EndpointPausePlayback, nil, params, nil)
-	if err != nil {
-		return &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToPausePlayback, Err: err}
+// PausePlaybackCtx implements the DefaultPlayerService's interface PausePlaybackCtx method.
+func (service *DefaultPlayerService) PausePlaybackCtx(ctx context.Context, input models.PausePlaybackRequest) error {
+	// Check the granted scopes
+	if err := checkScopes(service.client, "PlayerService.PausePlayback"); err != nil {
+		return err
 	}
 
-	// Handle Spotify API error
-	if res.StatusCode != http.StatusOK {
-		return utils.ParseSpotifyError(res, utils.RegErrorType)
-	}
+	// Add inputs to the query parameters
+	params := map[string]string{"device_id": input.DeviceId}
 
-	// Return the empty response
-	return nil
+	// Make an API call and discard the response body
+	return doEmpty(ctx, service.client, http.MethodPut, consts.EndpointPausePlayback, params, nil, consts.MsgFailedToPausePlayback)
 }
 
 // SkipToNext implements the DefaultPlayerService's interface SkipToNext method.
 func (service *DefaultPlayerService) SkipToNext(input models.SkipToNextRequest) error {
-	// Add inputs to the query parameters
-	params := map[string]string{"device_id": input.DeviceId}
+	return service.SkipToNextCtx(context.Background(), input)
+}
 
-	// Make an API call
-	res, err := service.client.Post(context.Background(), consts.EndpointSkipToNext, nil, params, nil, nil)
-	if err != nil {
-		return &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToSkipToNext, Err: err}
+// SkipToNextCtx implements the DefaultPlayerService's interface SkipToNextCtx method.
+func (service *DefaultPlayerService) SkipToNextCtx(ctx context.Context, input models.SkipToNextRequest) error {
+	// Check the granted scopes
+	if err := checkScopes(service.client, "PlayerService.SkipToNext"); err != nil {
+		return err
 	}
 
-	// Handle Spotify API error
-	if res.StatusCode != http.StatusOK {
-		return utils.ParseSpotifyError(res, utils.RegErrorType)
-	}
+	// Add inputs to the query parameters
+	params := map[string]string{"device_id": input.DeviceId}
 
-	// Return the empty response
-	return nil
+	// Make an API call and discard the response body
+	return doEmpty(ctx, service.client, http.MethodPost, consts.EndpointSkipToNext, params, nil, consts.MsgFailedToSkipToNext)
 }
 
 // SkipToPrevious implements the DefaultPlayerService's interface SkipToPrevious method.
 func (service *DefaultPlayerService) SkipToPrevious(input models.SkipToPreviousRequest) error {
-	// Add inputs to the query parameters
-	params := map[string]string{"device_id": input.DeviceId}
+	return service.SkipToPreviousCtx(context.Background(), input)
+}
 
-	// Make an API call
-	res, err := service.client.Post(context.Background(), consts.EndpointSkipToPrevious, nil, params, nil, nil)
-	if err != nil {
-		return &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToSkipToPrevious, Err: err}
+// SkipToPreviousCtx implements the DefaultPlayerService's interface SkipToPreviousCtx method.
+func (service *DefaultPlayerService) SkipToPreviousCtx(ctx context.Context, input models.SkipToPreviousRequest) error {
+	// Check the granted scopes
+	if err := checkScopes(service.client, "PlayerService.SkipToPrevious"); err != nil {
+		return err
 	}
 
-	// Handle Spotify API error
-	if res.StatusCode != http.StatusOK {
-		return utils.ParseSpotifyError(res, utils.RegErrorType)
-	}
+	// Add inputs to the query parameters
+	params := map[string]string{"device_id": input.DeviceId}
 
-	// Return the empty response
-	return nil
+	// Make an API call and discard the response body
+	return doEmpty(ctx, service.client, http.MethodPost, consts.EndpointSkipToPrevious, params, nil, consts.MsgFailedToSkipToPrevious)
 }
 
 // SeekToPosition implements the DefaultPlayerService's interface SeekToPosition method.
 func (service *DefaultPlayerService) SeekToPosition(input models.SeekToPositionRequest) error {
+	return service.SeekToPositionCtx(context.Background(), input)
+}
+
+// SeekToPositionCtx implements the DefaultPlayerService's interface SeekToPositionCtx method.
+func (service *DefaultPlayerService) SeekToPositionCtx(ctx context.Context, input models.SeekToPositionRequest) error {
+	// Check the granted scopes
+	if err := checkScopes(service.client, "PlayerService.SeekToPosition"); err != nil {
+		return err
+	}
+
 	// Validate the input
 	if input.PositionMs < 0 {
-		return &utils.AppError{Status: http.StatusBadRequest, Message: consts.MsgMustBePositiveNumber}
+		return utils.NewError(http.StatusBadRequest, consts.MsgMustBePositiveNumber, nil)
 	}
 
 	// Add inputs to the query parameters
 	params := map[string]string{"position_ms": strconv.Itoa(input.PositionMs), "device_id": input.DeviceId}
 
-	// Make an API call
-	res, err := service.client.Put(context.Background(), consts.EndpointSeekToPosition, nil, params, nil)
-	if err != nil {
-		return &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToSeekToPosition, Err: err}
-	}
-
-	// Handle Spotify API error
-	if res.StatusCode != http.StatusOK {
-		return utils.ParseSpotifyError(res, utils.RegErrorType)
-	}
-
-	// Return the empty response
-	return nil
+	// Make an API call and discard the response body
+	return doEmpty(ctx, service.client, http.MethodPut, consts.EndpointSeekToPosition, params, nil, consts.MsgFailedToSeekToPosition)
 }
 
 // SetRepeatMode implements the DefaultPlayerService's interface SetRepeatMode method.
 func (service *DefaultPlayerService) SetRepeatMode(input models.SetRepeatModeRequest) error {
+	return service.SetRepeatModeCtx(context.Background(), input)
+}
+
+// SetRepeatModeCtx implements the DefaultPlayerService's interface SetRepeatModeCtx method.
+func (service *DefaultPlayerService) SetRepeatModeCtx(ctx context.Context, input models.SetRepeatModeRequest) error {
+	// Check the granted scopes
+	if err := checkScopes(service.client, "PlayerService.SetRepeatMode"); err != nil {
+		return err
+	}
+
 	// Validate the input
 	if input.State == "" {
-		return &utils.AppError{Status: http.StatusBadRequest, Message: consts.MsgStateRequired}
+		return utils.NewError(http.StatusBadRequest, consts.MsgStateRequired, nil)
 	}
 
 	// Add inputs to the query parameters
 	params := map[string]string{"state": input.State, "device_id": input.DeviceId}
 
-	// Make an API call
-	res, err := service.client.Put(context.Background(), consts.EndpointRepeatMode, nil, params, nil)
-	if err != nil {
-		return &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToSetRepeatMode, Err: err}
-	}
-
-	// Handle Spotify API error
-	if res.StatusCode != http.StatusOK {
-		return utils.ParseSpotifyError(res, utils.RegErrorType)
-	}
-
-	// Return the empty response
-	return nil
+	// Make an API call and discard the response body
+	return doEmpty(ctx, service.client, http.MethodPut, consts.EndpointRepeatMode, params, nil, consts.MsgFailedToSetRepeatMode)
 }
 
 // SetPlaybackVolume implements the DefaultPlayerService's interface SetPlaybackVolume method.
 func (service *DefaultPlayerService) SetPlaybackVolume(input models.SetPlaybackVolumeRequest) error {
+	return service.SetPlaybackVolumeCtx(context.Background(), input)
+}
+
+// SetPlaybackVolumeCtx implements the DefaultPlayerService's interface SetPlaybackVolumeCtx method.
+func (service *DefaultPlayerService) SetPlaybackVolumeCtx(ctx context.Context, input models.SetPlaybackVolumeRequest) error {
+	// Check the granted scopes
+	if err := checkScopes(service.client, "PlayerService.SetPlaybackVolume"); err != nil {
+		return err
+	}
+
 	// Validate the input
 	if input.VolumePercent < 0 || input.VolumePercent > 100 {
-		return &utils.AppError{Status: http.StatusBadRequest, Message: consts.MsgVolumePercentMustBeInclusive}
+		return utils.NewError(http.StatusBadRequest, consts.MsgVolumePercentMustBeInclusive, nil)
 	}
 
 	// Add inputs to the query parameters
 	params := map[string]string{"volume_percent": strconv.Itoa(input.VolumePercent), "device_id": input.DeviceId}
 
-	// Make an API call
-	res, err := service.client.Put(context.Background(), consts.EndpointPlaybackVolume, nil, params, nil)
-	if err != nil {
-		return &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToSetPlaybackVolume, Err: err}
-	}
-
-	// Handle Spotify API error
-	if res.StatusCode != http.StatusOK {
-		return utils.ParseSpotifyError(res, utils.RegErrorType)
-	}
-
-	// Return the empty response
-	return nil
+	// Make an API call and discard the response body
+	return doEmpty(ctx, service.client, http.MethodPut, consts.EndpointPlaybackVolume, params, nil, consts.MsgFailedToSetPlaybackVolume)
 }
 
 // TogglePlaybackShuffle implements the DefaultPlayerService's interface TogglePlaybackShuffle method.
 func (service *DefaultPlayerService) TogglePlaybackShuffle(input models.TogglePlaybackShuffleRequest) error {
-	// Add inputs to the query parameters
-	params := map[string]string{"state": strconv.FormatBool(input.State), "device_id": input.DeviceId}
+	return service.TogglePlaybackShuffleCtx(context.Background(), input)
+}
 
-	// Make an API call
-	res, err := service.client.Put(context.Background(), consts.EndpointTogglePlaybackShuffle, nil, params, nil)
-	if err != nil {
-		return &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToTogglePlaybackShuffle, Err: err}
+// TogglePlaybackShuffleCtx implements the DefaultPlayerService's interface TogglePlaybackShuffleCtx method.
+func (service *DefaultPlayerService) TogglePlaybackShuffleCtx(ctx context.Context, input models.TogglePlaybackShuffleRequest) error {
+	// Check the granted scopes
+	if err := checkScopes(service.client, "PlayerService.TogglePlaybackShuffle"); err != nil {
+		return err
 	}
 
-	// Handle Spotify API error
-	if res.StatusCode != http.StatusOK {
-		return utils.ParseSpotifyError(res, utils.RegErrorType)
-	}
+	// Add inputs to the query parameters
+	params := map[string]string{"state": strconv.FormatBool(input.State), "device_id": input.DeviceId}
 
-	// Return the empty response
-	return nil
+	// Make an API call and discard the response body
+	return doEmpty(ctx, service.client, http.MethodPut, consts.EndpointTogglePlaybackShuffle, params, nil, consts.MsgFailedToTogglePlaybackShuffle)
 }
 
 // GetRecentlyPlayedTracks implements the DefaultPlayerService's interface GetRecentlyPlayedTracks method.
 func (service *DefaultPlayerService) GetRecentlyPlayedTracks(input models.GetRecentlyPlayedTracksRequest) (*models.RecentlyPlayedTracks, error) {
+	return service.GetRecentlyPlayedTracksCtx(context.Background(), input)
+}
+
+// GetRecentlyPlayedTracksCtx implements the DefaultPlayerService's interface GetRecentlyPlayedTracksCtx method.
+func (service *DefaultPlayerService) GetRecentlyPlayedTracksCtx(ctx context.Context, input models.GetRecentlyPlayedTracksRequest) (*models.RecentlyPlayedTracks, error) {
+	// Check the granted scopes
+	if err := checkScopes(service.client, "PlayerService.GetRecentlyPlayedTracks"); err != nil {
+		return nil, err
+	}
+
+	// Validate the input
+	if input.After > 0 && input.Before > 0 {
+		return nil, utils.NewError(http.StatusBadRequest, consts.MsgAfterAndBeforeExclusive, nil)
+	}
+
 	// Add inputs to the query parameters
 	params := map[string]string{"limit": strconv.Itoa(input.Limit)}
 	if input.After > 0 {
@@ -420,85 +430,133 @@ func (service *DefaultPlayerService) GetRecentlyPlayedTracks(input models.GetRec
 		params["before"] = strconv.Itoa(input.Before)
 	}
 
-	// Make an API call
-	res, err := service.client.Get(context.Background(), consts.EndpointRecentlyPlayedTracks, params)
-	if err != nil {
-		return nil, &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToGetRecentlyPlayedTracks, Err: err}
-	}
-
-	// Handle Spotify API error
-	if res.StatusCode != http.StatusOK {
-		return nil, utils.ParseSpotifyError(res, utils.RegErrorType)
-	}
+	// Make an API call and decode the response data into RecentlyPlayedTracks struct
+	return doJSON[models.RecentlyPlayedTracks](ctx, service.client, http.MethodGet, consts.EndpointRecentlyPlayedTracks, params, nil, consts.MsgFailedToGetRecentlyPlayedTracks)
+}
 
-	// Read the response body
-	data, err := io.ReadAll(res.Body)
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
+// AllRecentlyPlayedTracks implements the DefaultPlayerService's interface AllRecentlyPlayedTracks method.
+func (service *DefaultPlayerService) AllRecentlyPlayedTracks(ctx context.Context, input models.GetRecentlyPlayedTracksRequest, maxItems int) iter.Seq2[models.PlayHistory, error] {
+	// Both cursors can't be sent along, and the walk moves only one of them
+	if input.After > 0 && input.Before > 0 {
+		return func(yield func(models.PlayHistory, error) bool) {
+			yield(models.PlayHistory{}, utils.NewError(http.StatusBadRequest, consts.MsgAfterAndBeforeExclusive, nil))
+		}
+	}
+
+	// Default to the maximum page size
+	input.Limit = pageLimit(input.Limit)
+
+	// Walk forward in time when the after cursor is set, backward otherwise
+	forward := input.After > 0
+	cursor := ""
+	if forward {
+		cursor = strconv.Itoa(input.After)
+	} else if input.Before > 0 {
+		cursor = strconv.Itoa(input.Before)
+	}
+
+	// Walk the pages, fetching each one from its cursor
+	return PaginateCursor(ctx, cursor, maxItems,
+		func(ctx context.Context, cursor string) (*models.RecentlyPlayedTracks, error) {
+			// The cursors are unix timestamps in milliseconds
+			position := 0
+			if cursor != "" {
+				var err error
+				if position, err = strconv.Atoi(cursor); err != nil {
+					return nil, utils.NewError(http.StatusBadRequest, consts.MsgInvalidCursor, err)
+				}
+			}
+
+			if forward {
+				input.After = position
+			} else {
+				input.Before = position
+			}
+			return service.GetRecentlyPlayedTracksCtx(ctx, input)
+		},
+		func(page *models.RecentlyPlayedTracks) ([]models.PlayHistory, string) {
+			switch {
+			case page.Next == "":
+				return page.Items, ""
+			case forward:
+				return page.Items, page.Cursors.After
+			default:
+				return page.Items, page.Cursors.Before
+			}
+		},
+	)
+}
 
-	// Unmarshal the response data into RecentlyPlayedTracks struct
-	var recentlyPlayedTracks models.RecentlyPlayedTracks
-	if err := json.Unmarshal(data, &recentlyPlayedTracks); err != nil {
-		return nil, err
+// RecentlyPlayedTracksSince implements the DefaultPlayerService's interface RecentlyPlayedTracksSince method.
+func (service *DefaultPlayerService) RecentlyPlayedTracksSince(ctx context.Context, since time.Time) iter.Seq2[models.PlayHistory, error] {
+	// The after cursor is a unix timestamp in milliseconds, 0 would mean it's not set
+	input := models.GetRecentlyPlayedTracksRequest{After: max(int(since.UnixMilli()), 1)}
+
+	return func(yield func(models.PlayHistory, error) bool) {
+		// Walk forward in time from since
+		for playHistory, err := range service.AllRecentlyPlayedTracks(ctx, input, 0) {
+			if err != nil {
+				yield(playHistory, err)
+				return
+			}
+
+			playedAt, err := playHistory.PlayedAtTime()
+			if err != nil {
+				yield(playHistory, err)
+				return
+			}
+
+			// Skip a play at since itself, which has been synced before
+			if !playedAt.After(since) {
+				continue
+			}
+
+			if !yield(playHistory, nil) {
+				return
+			}
+		}
 	}
-
-	// Return the RecentlyPlayedTracks
-	return &recentlyPlayedTracks, nil
 }
 
 // GetUsersQueue implements the DefaultPlayerService's interface GetUsersQueue method.
 func (service *DefaultPlayerService) GetUsersQueue() (*models.UsersQueue, error) {
-	// Make an API call
-	res, err := service.client.Get(context.Background(), consts.EndpointUsersQueue, nil)
-	if err != nil {
-		return nil, &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToGetUsersQueue, Err: err}
-	}
-
-	// Handle Spotify API error
-	if res.StatusCode != http.StatusOK {
-		return nil, utils.ParseSpotifyError(res, utils.RegErrorType)
-	}
-
-	// Read the response body
-	data, err := io.ReadAll(res.Body)
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
+	return service.GetUsersQueueCtx(context.Background())
+}
 
-	// Unmarshal the response data into UsersQueue struct
-	var usersQueue models.UsersQueue
-	if err := json.Unmarshal(data, &usersQueue); err != nil {
+// GetUsersQueueCtx implements the DefaultPlayerService's interface GetUsersQueueCtx method.
+func (service *DefaultPlayerService) GetUsersQueueCtx(ctx context.Context) (*models.UsersQueue, error) {
+	// Check the granted scopes
+	if err := checkScopes(service.client, "PlayerService.GetUsersQueue"); err != nil {
 		return nil, err
 	}
 
-	// Return the UsersQueue
-	return &usersQueue, nil
+	// Make an API call and decode the response data into UsersQueue struct
+	return doJSON[models.UsersQueue](ctx, service.client, http.MethodGet, consts.EndpointUsersQueue, nil, nil, consts.MsgFailedToGetUsersQueue)
 }
 
 // AddItemToPlaybackQueue implements the DefaultPlayerService's interface AddItemToPlaybackQueue method.
 func (service *DefaultPlayerService) AddItemToPlaybackQueue(input models.AddItemToPlaybackQueueRequest) error {
+	return service.AddItemToPlaybackQueueCtx(context.Background(), input)
+}
+
+// AddItemToPlaybackQueueCtx implements the DefaultPlayerService's interface AddItemToPlaybackQueueCtx method.
+func (service *DefaultPlayerService) AddItemToPlaybackQueueCtx(ctx context.Context, input models.AddItemToPlaybackQueueRequest) error {
+	// Check the granted scopes
+	if err := checkScopes(service.client, "PlayerService.AddItemToPlaybackQueue"); err != nil {
+		return err
+	}
+
 	// Validate the input
 	if input.Uri == "" {
-		return &utils.AppError{Status: http.StatusBadRequest, Message: consts.MsgUriRequired}
+		return utils.NewError(http.StatusBadRequest, consts.MsgUriRequired, nil)
 	}
-
-	// Add inputs to the query parameters
-	params := map[string]string{"uri": input.Uri, "device_id": input.DeviceId}
-
-	// Make an API call
-	res, err := service.client.Post(context.Background(), consts.EndpointPlaybackVolume, nil, params, nil, nil)
-	if err != nil {
-		return &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToAddItemToPlaybackQueue, Err: err}
+	if t := input.Uri.Type(); t != spotifyid.TypeTrack && t != spotifyid.TypeEpisode {
+		return utils.NewError(http.StatusBadRequest, consts.MsgTrackOrEpisodeUriRequired, nil)
 	}
 
-	// Handle Spotify API error
-	if res.StatusCode != http.StatusOK {
-		return utils.ParseSpotifyError(res, utils.RegErrorType)
-	}
+	// Add inputs to the query parameters
+	params := map[string]string{"uri": string(input.Uri), "device_id": input.DeviceId}
 
-	// Return the empty response
-	return nil
+	// Make an API call and discard the response body
+	return doEmpty(ctx, service.client, http.MethodPost, consts.EndpointPlaybackQueue, params, nil, consts.MsgFailedToAddItemToPlaybackQueue)
 }
@@ -0,0 +1,207 @@
+package apis
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicse3/gospotify/models"
+	"github.com/alicse3/gospotify/utils"
+)
+
+// historyStart is the time of the first play of the fake listening history, which has a play every minute.
+var historyStart = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+// playedAt returns the time of the nth play of the fake listening history, starting at 1.
+func playedAt(n int) time.Time {
+	return historyStart.Add(time.Duration(n) * time.Minute)
+}
+
+// historyServer serves the recently played tracks endpoint out of a listening history of the given number of plays,
+// named t1, t2 and so on, as Spotify does: the pages hold the plays before or after the cursor, most recent first.
+type historyServer struct {
+	plays   int
+	queries []url.Values
+	mu      sync.Mutex
+}
+
+func (hs *historyServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	hs.mu.Lock()
+	hs.queries = append(hs.queries, query)
+	hs.mu.Unlock()
+
+	if query.Has("after") && query.Has("before") {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":{"status":400,"message":"Only one of after and before can be set"}}`))
+		return
+	}
+	limit, _ := strconv.Atoi(query.Get("limit"))
+	after, _ := strconv.ParseInt(query.Get("after"), 10, 64)
+	before, _ := strconv.ParseInt(query.Get("before"), 10, 64)
+
+	// Select the plays after the after cursor, the oldest first, or before the before cursor, the most recent first
+	var selected []int
+	if query.Has("after") {
+		for n := 1; n <= hs.plays && len(selected) < limit; n++ {
+			if playedAt(n).UnixMilli() > after {
+				selected = append(selected, n)
+			}
+		}
+		slices.Reverse(selected)
+	} else {
+		for n := hs.plays; n >= 1 && len(selected) < limit; n-- {
+			if before == 0 || playedAt(n).UnixMilli() < before {
+				selected = append(selected, n)
+			}
+		}
+	}
+
+	var page models.RecentlyPlayedTracks
+	for _, n := range selected {
+		var playHistory models.PlayHistory
+		playHistory.Track.Name = "t" + strconv.Itoa(n)
+		playHistory.PlayedAt = playedAt(n).Format(time.RFC3339)
+		page.Items = append(page.Items, playHistory)
+	}
+	if len(selected) > 0 {
+		newest, oldest := selected[0], selected[len(selected)-1]
+		page.Cursors.After = strconv.FormatInt(playedAt(newest).UnixMilli(), 10)
+		page.Cursors.Before = strconv.FormatInt(playedAt(oldest).UnixMilli(), 10)
+
+		// There is a next page if there are plays beyond the page in the walking direction
+		if (query.Has("after") && newest < hs.plays) || (!query.Has("after") && oldest > 1) {
+			page.Next = "https://api.spotify.com/v1/me/player/recently-played?next"
+		}
+	}
+
+	json.NewEncoder(w).Encode(page)
+}
+
+// newHistoryService returns a PlayerService for a fake listening history of the given number of plays.
+func newHistoryService(t *testing.T, plays int) (PlayerService, *historyServer) {
+	hs := &historyServer{plays: plays}
+	server := httptest.NewServer(hs)
+	t.Cleanup(server.Close)
+
+	return NewDefaultPlayerService(utils.NewHttpClient(server.URL)), hs
+}
+
+// trackNames collects the names of the yielded tracks, failing the test on an error.
+func trackNames(t *testing.T, seq func(yield func(models.PlayHistory, error) bool)) []string {
+	t.Helper()
+
+	var names []string
+	for playHistory, err := range seq {
+		if err != nil {
+			t.Fatalf("iteration error = %v", err)
+		}
+		names = append(names, playHistory.Track.Name)
+	}
+	return names
+}
+
+func TestAllRecentlyPlayedTracks(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       models.GetRecentlyPlayedTracksRequest
+		maxItems    int
+		want        []string
+		wantQueries int
+	}{
+		{
+			name:        "backward from now",
+			input:       models.GetRecentlyPlayedTracksRequest{Limit: 2},
+			want:        []string{"t5", "t4", "t3", "t2", "t1"},
+			wantQueries: 3,
+		},
+		{
+			name:        "backward from before",
+			input:       models.GetRecentlyPlayedTracksRequest{Limit: 2, Before: int(playedAt(4).UnixMilli())},
+			want:        []string{"t3", "t2", "t1"},
+			wantQueries: 2,
+		},
+		{
+			name:        "forward from after",
+			input:       models.GetRecentlyPlayedTracksRequest{Limit: 2, After: int(playedAt(1).UnixMilli())},
+			want:        []string{"t3", "t2", "t5", "t4"},
+			wantQueries: 2,
+		},
+		{
+			name:        "max items",
+			input:       models.GetRecentlyPlayedTracksRequest{Limit: 2},
+			maxItems:    3,
+			want:        []string{"t5", "t4", "t3"},
+			wantQueries: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, hs := newHistoryService(t, 5)
+
+			got := trackNames(t, service.AllRecentlyPlayedTracks(context.Background(), tt.input, tt.maxItems))
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("AllRecentlyPlayedTracks() = %v, want %v", got, tt.want)
+			}
+			if len(hs.queries) != tt.wantQueries {
+				t.Errorf("%d requests, want %d", len(hs.queries), tt.wantQueries)
+			}
+
+			// Only the cursor of the walking direction is ever sent
+			for _, query := range hs.queries {
+				if query.Has("after") && query.Has("before") {
+					t.Errorf("request with both cursors: %v", query)
+				}
+			}
+		})
+	}
+}
+
+func TestAllRecentlyPlayedTracksBothCursors(t *testing.T) {
+	service, hs := newHistoryService(t, 5)
+	input := models.GetRecentlyPlayedTracksRequest{After: int(playedAt(1).UnixMilli()), Before: int(playedAt(4).UnixMilli())}
+
+	var errs int
+	for _, err := range service.AllRecentlyPlayedTracks(context.Background(), input, 0) {
+		if err == nil {
+			t.Fatal("AllRecentlyPlayedTracks() yielded an item, want an error")
+		}
+		errs++
+	}
+	if errs != 1 || len(hs.queries) != 0 {
+		t.Errorf("%d errors after %d requests, want 1 error without a request", errs, len(hs.queries))
+	}
+}
+
+func TestRecentlyPlayedTracksSince(t *testing.T) {
+	service, hs := newHistoryService(t, 5)
+
+	got := trackNames(t, service.RecentlyPlayedTracksSince(context.Background(), playedAt(2)))
+	if want := []string{"t5", "t4", "t3"}; !slices.Equal(got, want) {
+		t.Errorf("RecentlyPlayedTracksSince() = %v, want %v", got, want)
+	}
+
+	// The plays are queried after since, rather than walking back from now
+	if len(hs.queries) != 1 {
+		t.Fatalf("%d requests, want 1", len(hs.queries))
+	}
+	if after := hs.queries[0].Get("after"); after != strconv.FormatInt(playedAt(2).UnixMilli(), 10) {
+		t.Errorf("after = %q, want the time of since", after)
+	}
+}
+
+func TestRecentlyPlayedTracksSinceNothingNew(t *testing.T) {
+	service, _ := newHistoryService(t, 5)
+
+	if got := trackNames(t, service.RecentlyPlayedTracksSince(context.Background(), playedAt(5))); len(got) != 0 {
+		t.Errorf("RecentlyPlayedTracksSince() = %v, want nothing", got)
+	}
+}
@@ -2,14 +2,14 @@ package apis
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"io"
+	"iter"
 	"net/http"
 	"strconv"
 
 	"github.com/alicse3/gospotify/consts"
 	"github.com/alicse3/gospotify/models"
+	"github.com/alicse3/gospotify/spotifyid"
 	"github.com/alicse3/gospotify/utils"
 )
 
@@ -17,14 +17,24 @@ import (
 type PlaylistService interface {
 	// Get a playlist owned by a Spotify user.
 	GetPlaylist(input models.GetPlaylistRequest) (*models.Playlist, error)
+	// GetPlaylistCtx is like GetPlaylist but carries the given context through to the API call.
+	GetPlaylistCtx(ctx context.Context, input models.GetPlaylistRequest) (*models.Playlist, error)
 
 	// Change a playlist's name and public/private state. (The user must, of course, own the playlist.)
 	// Authorization scopes: playlist-modify-public, playlist-modify-private
 	ChangePlaylistDetails(input models.ChangePlaylistDetailsRequest) error
+	// ChangePlaylistDetailsCtx is like ChangePlaylistDetails but carries the given context through to the API call.
+	ChangePlaylistDetailsCtx(ctx context.Context, input models.ChangePlaylistDetailsRequest) error
 
 	// Get full details of the items of a playlist owned by a Spotify user.
 	// Authorization scopes: playlist-read-private
 	GetPlaylistItems(input models.GetPlaylistItemsRequest) (*models.PlaylistItems, error)
+	// GetPlaylistItemsCtx is like GetPlaylistItems but carries the given context through to the API call.
+	GetPlaylistItemsCtx(ctx context.Context, input models.GetPlaylistItemsRequest) (*models.PlaylistItems, error)
+
+	// AllPlaylistItems yields the playlist's items one by one, walking all the pages of GetPlaylistItems starting at input.Offset.
+	// At most maxItems items are yielded, 0 means there is no cap.
+	AllPlaylistItems(ctx context.Context, input models.GetPlaylistItemsRequest, maxItems int) iter.Seq2[models.PlaylistItem, error]
 
 	// Either reorder or replace items in a playlist depending on the request's parameters.
 	// To reorder items, include range_start, insert_before, range_length and snapshot_id in the request's body.
@@ -37,39 +47,75 @@ type PlaylistService interface {
 
 	// Authorization scopes: playlist-modify-public, playlist-modify-private
 	UpdatePlaylistItems(input models.UpdatePlaylistItemsRequest) (*models.UpdatePlaylistItems, error)
+	// UpdatePlaylistItemsCtx is like UpdatePlaylistItems but carries the given context through to the API call.
+	UpdatePlaylistItemsCtx(ctx context.Context, input models.UpdatePlaylistItemsRequest) (*models.UpdatePlaylistItems, error)
 
 	// Add one or more items to a user's playlist.
 	// Authorization scopes: playlist-modify-public, playlist-modify-private
 	AddPlaylistItems(input models.AddPlaylistItemsRequest) (*models.AddPlaylistItems, error)
+	// AddPlaylistItemsCtx is like AddPlaylistItems but carries the given context through to the API call.
+	AddPlaylistItemsCtx(ctx context.Context, input models.AddPlaylistItemsRequest) (*models.AddPlaylistItems, error)
 
 	// Remove one or more items from a user's playlist.
 	// Authorization scopes: playlist-modify-public, playlist-modify-private
 	RemovePlaylistItems(input models.RemovePlaylistItemsRequest) (*models.RemovePlaylistItems, error)
+	// RemovePlaylistItemsCtx is like RemovePlaylistItems but carries the given context through to the API call.
+	RemovePlaylistItemsCtx(ctx context.Context, input models.RemovePlaylistItemsRequest) (*models.RemovePlaylistItems, error)
 
 	// Get a list of the playlists owned or followed by the current Spotify user.
 	// Authorization scopes: playlist-read-private
 	GetCurrentUserPlaylists(input models.GetCurrentUsersPlaylistsRequest) (*models.Playlists, error)
+	// GetCurrentUserPlaylistsCtx is like GetCurrentUserPlaylists but carries the given context through to the API call.
+	GetCurrentUserPlaylistsCtx(ctx context.Context, input models.GetCurrentUsersPlaylistsRequest) (*models.Playlists, error)
+
+	// AllCurrentUserPlaylists yields the current user's playlists one by one, walking all the pages of GetCurrentUserPlaylists starting at input.Offset.
+	// At most maxItems items are yielded, 0 means there is no cap.
+	AllCurrentUserPlaylists(ctx context.Context, input models.GetCurrentUsersPlaylistsRequest, maxItems int) iter.Seq2[models.SimplifiedPlaylist, error]
 
 	// Get a list of the playlists owned or followed by a Spotify user.
 	// Authorization scopes: playlist-read-private, playlist-read-collaborative
 	GetUserPlaylists(input models.GetUsersPlaylistsRequest) (*models.Playlists, error)
+	// GetUserPlaylistsCtx is like GetUserPlaylists but carries the given context through to the API call.
+	GetUserPlaylistsCtx(ctx context.Context, input models.GetUsersPlaylistsRequest) (*models.Playlists, error)
+
+	// AllUserPlaylists yields the user's playlists one by one, walking all the pages of GetUserPlaylists starting at input.Offset.
+	// At most maxItems items are yielded, 0 means there is no cap.
+	AllUserPlaylists(ctx context.Context, input models.GetUsersPlaylistsRequest, maxItems int) iter.Seq2[models.SimplifiedPlaylist, error]
 
 	// Create a playlist for a Spotify user. (The playlist will be empty until you add tracks.) Each user is generally limited to a maximum of 11000 playlists.
 	// Authorization scopes: playlist-modify-public, playlist-modify-private
 	CreatePlaylist(input models.CreatePlaylistRequest) (*models.Playlist, error)
+	// CreatePlaylistCtx is like CreatePlaylist but carries the given context through to the API call.
+	CreatePlaylistCtx(ctx context.Context, input models.CreatePlaylistRequest) (*models.Playlist, error)
 
 	// Get a list of Spotify featured playlists (shown, for example, on a Spotify player's 'Browse' tab).
 	GetFeaturedPlaylists(input models.GetFeaturedPlaylistsRequest) (*models.FeaturedPlaylists, error)
+	// GetFeaturedPlaylistsCtx is like GetFeaturedPlaylists but carries the given context through to the API call.
+	GetFeaturedPlaylistsCtx(ctx context.Context, input models.GetFeaturedPlaylistsRequest) (*models.FeaturedPlaylists, error)
+
+	// AllFeaturedPlaylists yields the featured playlists one by one, walking all the pages of GetFeaturedPlaylists starting at input.Offset.
+	// At most maxItems items are yielded, 0 means there is no cap.
+	AllFeaturedPlaylists(ctx context.Context, input models.GetFeaturedPlaylistsRequest, maxItems int) iter.Seq2[models.SimplifiedPlaylist, error]
 
 	// Get a list of Spotify playlists tagged with a particular category.
 	GetCategoryPlaylists(input models.GetCategoryPlaylistsRequest) (*models.CategoryPlaylists, error)
+	// GetCategoryPlaylistsCtx is like GetCategoryPlaylists but carries the given context through to the API call.
+	GetCategoryPlaylistsCtx(ctx context.Context, input models.GetCategoryPlaylistsRequest) (*models.CategoryPlaylists, error)
+
+	// AllCategoryPlaylists yields the category's playlists one by one, walking all the pages of GetCategoryPlaylists starting at input.Offset.
+	// At most maxItems items are yielded, 0 means there is no cap.
+	AllCategoryPlaylists(ctx context.Context, input models.GetCategoryPlaylistsRequest, maxItems int) iter.Seq2[models.SimplifiedPlaylist, error]
 
 	// Get the current image associated with a specific playlist.
 	GetPlaylistCoverImage(input models.GetPlaylistCoverImageRequest) (*models.PlaylistCoverImage, error)
+	// GetPlaylistCoverImageCtx is like GetPlaylistCoverImage but carries the given context through to the API call.
+	GetPlaylistCoverImageCtx(ctx context.Context, input models.GetPlaylistCoverImageRequest) (*models.PlaylistCoverImage, error)
 
 	// Replace the image used to represent a specific playlist.
 	// Authorization scopes: ugc-image-upload, playlist-modify-public, playlist-modify-private
 	AddCustomPlaylistCoverImage(input models.GetCustomPlaylistCoverImageRequest) error
+	// AddCustomPlaylistCoverImageCtx is like AddCustomPlaylistCoverImage but carries the given context through to the API call.
+	AddCustomPlaylistCoverImageCtx(ctx context.Context, input models.GetCustomPlaylistCoverImageRequest) error
 }
 
 // DefaultPlaylistService is a struct that implements PlaylistService interface.
@@ -84,396 +130,374 @@ func NewDefaultPlaylistService(client *utils.HttpClient) *DefaultPlaylistService
 
 // GetPlaylist implements the DefaultPlaylistService's interface GetPlaylist method.
 func (service *DefaultPlaylistService) GetPlaylist(input models.GetPlaylistRequest) (*models.Playlist, error) {
+	return service.GetPlaylistCtx(context.Background(), input)
+}
+
+// GetPlaylistCtx implements the DefaultPlaylistService's interface GetPlaylistCtx method.
+func (service *DefaultPlaylistService) GetPlaylistCtx(ctx context.Context, input models.GetPlaylistRequest) (*models.Playlist, error) {
 	// Validate the input
 	if input.PlaylistId == "" {
-		return nil, &utils.AppError{Status: http.StatusBadRequest, Message: consts.MsgPlaylistIdRequired}
+		return nil, utils.NewError(http.StatusBadRequest, consts.MsgPlaylistIdRequired, nil)
 	}
 
 	// Substitute id in the endpoint
 	endpoint := fmt.Sprintf(consts.EndpointPlaylists, input.PlaylistId)
 
 	// Add inputs to the query parameters
-	params := map[string]string{"playlist_id": input.PlaylistId, "market": input.Market, "fields": input.Fields}
+	params := map[string]string{"playlist_id": string(input.PlaylistId), "market": input.Market, "fields": input.Fields}
 	if input.AdditionalTypes != "" {
 		params["additional_types"] = input.AdditionalTypes
 	}
 
-	// Make an API call
-	res, err := service.client.Get(context.Background(), endpoint, params)
-	if err != nil {
-		return nil, &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToGetPlaylist, Err: err}
-	}
-
-	// Handle Spotify API error
-	if res.StatusCode != http.StatusOK {
-		return nil, utils.ParseSpotifyError(res, utils.RegErrorType)
-	}
-
-	// Read the response body
-	data, err := io.ReadAll(res.Body)
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
-
-	// Unmarshal the response data into Playlist struct
-	var playlist models.Playlist
-	if err := json.Unmarshal(data, &playlist); err != nil {
-		return nil, err
-	}
-
-	// Return the Playlist
-	return &playlist, nil
+	// Make an API call and decode the response data into Playlist struct
+	return doJSON[models.Playlist](ctx, service.client, http.MethodGet, endpoint, params, nil, consts.MsgFailedToGetPlaylist)
 }
 
 // ChangePlaylistDetails implements the DefaultPlaylistService's interface ChangePlaylistDetails method.
 func (service *DefaultPlaylistService) ChangePlaylistDetails(input models.ChangePlaylistDetailsRequest) error {
+	return service.ChangePlaylistDetailsCtx(context.Background(), input)
+}
+
+// ChangePlaylistDetailsCtx implements the DefaultPlaylistService's interface ChangePlaylistDetailsCtx method.
+func (service *DefaultPlaylistService) ChangePlaylistDetailsCtx(ctx context.Context, input models.ChangePlaylistDetailsRequest) error {
+	// Check the granted scopes
+	if err := checkScopes(service.client, "PlaylistService.ChangePlaylistDetails"); err != nil {
+		return err
+	}
+
 	// Validate the input
 	if input.PlaylistId == "" {
-		return &utils.AppError{Status: http.StatusBadRequest, Message: consts.MsgPlaylistIdRequired}
+		return utils.NewError(http.StatusBadRequest, consts.MsgPlaylistIdRequired, nil)
 	}
 
 	// Substitute id in the endpoint
 	endpoint := fmt.Sprintf(consts.EndpointPlaylists, input.PlaylistId)
 
 	// Add inputs to the query parameters
-	params := map[string]string{"playlist_id": input.PlaylistId}
+	params := map[string]string{"playlist_id": string(input.PlaylistId)}
 
-	// Make an API call
-	res, err := service.client.Put(context.Background(), endpoint, nil, params, input.Body)
-	if err != nil {
-		return &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToChangePlaylistDetails, Err: err}
-	}
-
-	// Handle Spotify API error
-	if res.StatusCode != http.StatusOK {
-		return utils.ParseSpotifyError(res, utils.RegErrorType)
-	}
-
-	// Return the empty response
-	return nil
+	// Make an API call and discard the response body
+	return doEmpty(ctx, service.client, http.MethodPut, endpoint, params, input.Body, consts.MsgFailedToChangePlaylistDetails)
 }
 
 // GetPlaylistItems implements the DefaultPlaylistService's interface GetPlaylistItems method.
 func (service *DefaultPlaylistService) GetPlaylistItems(input models.GetPlaylistItemsRequest) (*models.PlaylistItems, error) {
+	return service.GetPlaylistItemsCtx(context.Background(), input)
+}
+
+// GetPlaylistItemsCtx implements the DefaultPlaylistService's interface GetPlaylistItemsCtx method.
+func (service *DefaultPlaylistService) GetPlaylistItemsCtx(ctx context.Context, input models.GetPlaylistItemsRequest) (*models.PlaylistItems, error) {
 	// Validate the input
 	if input.PlaylistId == "" {
-		return nil, &utils.AppError{Status: http.StatusBadRequest, Message: consts.MsgPlaylistIdRequired}
+		return nil, utils.NewError(http.StatusBadRequest, consts.MsgPlaylistIdRequired, nil)
 	}
 
 	// Substitute id in the endpoint
 	endpoint := fmt.Sprintf(consts.EndpointPlaylistItems, input.PlaylistId)
 
 	// Add inputs to the query parameters
-	params := map[string]string{"playlist_id": input.PlaylistId, "market": input.Market, "fields": input.Fields, "limit": strconv.Itoa(input.Limit), "offset": strconv.Itoa(input.Offset)}
+	params := map[string]string{"playlist_id": string(input.PlaylistId), "market": input.Market, "fields": input.Fields, "limit": strconv.Itoa(input.Limit), "offset": strconv.Itoa(input.Offset)}
 	if input.AdditionalTypes != "" {
 		params["additional_types"] = input.AdditionalTypes
 	}
 
-	// Make an API call
-	res, err := service.client.Get(context.Background(), endpoint, params)
-	if err != nil {
-		return nil, &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToGetPlaylistItems, Err: err}
-	}
-
-	// Handle Spotify API error
-	if res.StatusCode != http.StatusOK {
-		return nil, utils.ParseSpotifyError(res, utils.RegErrorType)
-	}
-
-	// Read the response body
-	data, err := io.ReadAll(res.Body)
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
-
-	// Unmarshal the response data into PlaylistItems struct
-	var playlistItems models.PlaylistItems
-	if err := json.Unmarshal(data, &playlistItems); err != nil {
-		return nil, err
-	}
+	// Make an API call and decode the response data into PlaylistItems struct
+	return doJSON[models.PlaylistItems](ctx, service.client, http.MethodGet, endpoint, params, nil, consts.MsgFailedToGetPlaylistItems)
+}
 
-	// Return the PlaylistItems
-	return &playlistItems, nil
+// AllPlaylistItems implements the DefaultPlaylistService's interface AllPlaylistItems method.
+func (service *DefaultPlaylistService) AllPlaylistItems(ctx context.Context, input models.GetPlaylistItemsRequest, maxItems int) iter.Seq2[models.PlaylistItem, error] {
+	// Default to the maximum page size
+	input.Limit = pageLimit(input.Limit)
+
+	// Walk the pages, fetching each one from its offset
+	return Paginate(ctx, input.Offset, maxItems,
+		func(ctx context.Context, offset int) (*models.PlaylistItems, error) {
+			input.Offset = offset
+			return service.GetPlaylistItemsCtx(ctx, input)
+		},
+		func(page *models.PlaylistItems) ([]models.PlaylistItem, string) {
+			return page.Items, page.Next
+		},
+	)
 }
 
 // UpdatePlaylistItems implements the DefaultPlaylistService's interface UpdatePlaylistItems method.
 func (service *DefaultPlaylistService) UpdatePlaylistItems(input models.UpdatePlaylistItemsRequest) (*models.UpdatePlaylistItems, error) {
-	// Validate the input
-	if input.PlaylistId == "" {
-		return nil, &utils.AppError{Status: http.StatusBadRequest, Message: consts.MsgPlaylistIdRequired}
-	}
-
-	// Substitute id in the endpoint
-	endpoint := fmt.Sprintf(consts.EndpointPlaylistItems, input.PlaylistId)
-
-	// Add inputs to the query parameters
-	params := map[string]string{"playlist_id": input.PlaylistId, "uris": input.Uris}
+	return service.UpdatePlaylistItemsCtx(context.Background(), input)
+}
 
-	// Make an API call
-	res, err := service.client.Put(context.Background(), endpoint, nil, params, input.Body)
-	if err != nil {
-		return nil, &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToUpdatePlaylistItems, Err: err}
+// UpdatePlaylistItemsCtx implements the DefaultPlaylistService's interface UpdatePlaylistItemsCtx method.
+func (service *DefaultPlaylistService) UpdatePlaylistItemsCtx(ctx context.Context, input models.UpdatePlaylistItemsRequest) (*models.UpdatePlaylistItems, error) {
+	// Check the granted scopes
+	if err := checkScopes(service.client, "PlaylistService.UpdatePlaylistItems"); err != nil {
+		return nil, err
 	}
 
-	// Handle Spotify API error
-	if res.StatusCode != http.StatusOK {
-		return nil, utils.ParseSpotifyError(res, utils.RegErrorType)
+	// Validate the input
+	if input.PlaylistId == "" {
+		return nil, utils.NewError(http.StatusBadRequest, consts.MsgPlaylistIdRequired, nil)
 	}
 
-	// Read the response body
-	data, err := io.ReadAll(res.Body)
-	if err != nil {
+	if err := validateNotEmpty(input.Uris, consts.MsgEmptyUri); err != nil {
 		return nil, err
 	}
-	defer res.Body.Close()
 
-	// Unmarshal the response data into UpdatePlaylistItems struct
-	var updatePlaylistItems models.UpdatePlaylistItems
-	if err := json.Unmarshal(data, &updatePlaylistItems); err != nil {
-		return nil, err
-	}
+	// Substitute id in the endpoint
+	endpoint := fmt.Sprintf(consts.EndpointPlaylistItems, input.PlaylistId)
 
-	// Return the UpdatePlaylistItems
-	return &updatePlaylistItems, nil
+	// Reorder the items as described by the body, if no URIs are given
+	if len(input.Uris) == 0 {
+		if err := validateMaxCount(len(input.Body.Uris), consts.MaxPlaylistItemUris, consts.MsgTooManyUris); err != nil {
+			return nil, err
+		}
+
+		params := map[string]string{"playlist_id": string(input.PlaylistId)}
+		return doJSON[models.UpdatePlaylistItems](ctx, service.client, http.MethodPut, endpoint, params, input.Body, consts.MsgFailedToUpdatePlaylistItems)
+	}
+
+	// Replace the items with the first chunk of URIs, and append the rest chunk by chunk
+	replaced := false
+	return getChunked(input.Uris, consts.MaxPlaylistItemUris,
+		func(uris []spotifyid.URI) (*models.UpdatePlaylistItems, error) {
+			// Add inputs to the query parameters
+			params := map[string]string{"playlist_id": string(input.PlaylistId), "uris": join(uris)}
+
+			// Make an API call and decode the response data into UpdatePlaylistItems struct
+			if !replaced {
+				replaced = true
+				return doJSON[models.UpdatePlaylistItems](ctx, service.client, http.MethodPut, endpoint, params, nil, consts.MsgFailedToUpdatePlaylistItems)
+			}
+			return doJSON[models.UpdatePlaylistItems](ctx, service.client, http.MethodPost, endpoint, params, nil, consts.MsgFailedToUpdatePlaylistItems)
+		},
+		func(result, chunk *models.UpdatePlaylistItems) {
+			// The snapshot ID of the last API call is the one of the playlist
+			*result = *chunk
+		},
+	)
 }
 
 // AddPlaylistItems implements the DefaultPlaylistService's interface AddPlaylistItems method.
 func (service *DefaultPlaylistService) AddPlaylistItems(input models.AddPlaylistItemsRequest) (*models.AddPlaylistItems, error) {
-	// Validate the input
-	if input.PlaylistId == "" {
-		return nil, &utils.AppError{Status: http.StatusBadRequest, Message: consts.MsgPlaylistIdRequired}
-	}
-
-	// Substitute id in the endpoint
-	endpoint := fmt.Sprintf(consts.EndpointPlaylistItems, input.PlaylistId)
-
-	// Add inputs to the query parameters
-	params := map[string]string{"playlist_id": input.PlaylistId, "position": strconv.Itoa(input.Position), "uris": input.Uris}
+	return service.AddPlaylistItemsCtx(context.Background(), input)
+}
 
-	// Make an API call
-	res, err := service.client.Post(context.Background(), endpoint, nil, params, nil, input.Body)
-	if err != nil {
-		return nil, &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToAddPlaylistItems, Err: err}
+// AddPlaylistItemsCtx implements the DefaultPlaylistService's interface AddPlaylistItemsCtx method.
+func (service *DefaultPlaylistService) AddPlaylistItemsCtx(ctx context.Context, input models.AddPlaylistItemsRequest) (*models.AddPlaylistItems, error) {
+	// Check the granted scopes
+	if err := checkScopes(service.client, "PlaylistService.AddPlaylistItems"); err != nil {
+		return nil, err
 	}
 
-	// Handle Spotify API error
-	if res.StatusCode != http.StatusOK {
-		return nil, utils.ParseSpotifyError(res, utils.RegErrorType)
+	// Validate the input
+	if input.PlaylistId == "" {
+		return nil, utils.NewError(http.StatusBadRequest, consts.MsgPlaylistIdRequired, nil)
 	}
 
-	// Read the response body
-	data, err := io.ReadAll(res.Body)
-	if err != nil {
+	if err := validateNotEmpty(input.Uris, consts.MsgEmptyUri); err != nil {
 		return nil, err
 	}
-	defer res.Body.Close()
 
-	// Unmarshal the response data into AddPlaylistItems struct
-	var addPlaylistItems models.AddPlaylistItems
-	if err := json.Unmarshal(data, &addPlaylistItems); err != nil {
-		return nil, err
-	}
+	// Substitute id in the endpoint
+	endpoint := fmt.Sprintf(consts.EndpointPlaylistItems, input.PlaylistId)
 
-	// Return the AddPlaylistItems
-	return &addPlaylistItems, nil
+	// Add the items given in the body, if no URIs are given
+	if len(input.Uris) == 0 {
+		if err := validateMaxCount(len(input.Body.Uris), consts.MaxPlaylistItemUris, consts.MsgTooManyUris); err != nil {
+			return nil, err
+		}
+
+		params := map[string]string{"playlist_id": string(input.PlaylistId), "position": strconv.Itoa(input.Position)}
+		return doJSON[models.AddPlaylistItems](ctx, service.client, http.MethodPost, endpoint, params, input.Body, consts.MsgFailedToAddPlaylistItems)
+	}
+
+	// Add the URIs chunk by chunk, each chunk right after the previous one
+	position := input.Position
+	return getChunked(input.Uris, consts.MaxPlaylistItemUris,
+		func(uris []spotifyid.URI) (*models.AddPlaylistItems, error) {
+			// Add inputs to the query parameters
+			params := map[string]string{"playlist_id": string(input.PlaylistId), "position": strconv.Itoa(position), "uris": join(uris)}
+			position += len(uris)
+
+			// Make an API call and decode the response data into AddPlaylistItems struct
+			return doJSON[models.AddPlaylistItems](ctx, service.client, http.MethodPost, endpoint, params, nil, consts.MsgFailedToAddPlaylistItems)
+		},
+		func(result, chunk *models.AddPlaylistItems) {
+			// The snapshot ID of the last API call is the one of the playlist
+			*result = *chunk
+		},
+	)
 }
 
 // RemovePlaylistItems implements the DefaultPlaylistService's interface RemovePlaylistItems method.
 func (service *DefaultPlaylistService) RemovePlaylistItems(input models.RemovePlaylistItemsRequest) (*models.RemovePlaylistItems, error) {
-	// Validate the input
-	if input.PlaylistId == "" {
-		return nil, &utils.AppError{Status: http.StatusBadRequest, Message: consts.MsgPlaylistIdRequired}
-	}
-
-	// Substitute id in the endpoint
-	endpoint := fmt.Sprintf(consts.EndpointPlaylistItems, input.PlaylistId)
-
-	// Add inputs to the query parameters
-	params := map[string]string{"playlist_id": input.PlaylistId}
+	return service.RemovePlaylistItemsCtx(context.Background(), input)
+}
 
-	// Make an API call
-	res, err := service.client.Delete(context.Background(), endpoint, nil, params, input.Body)
-	if err != nil {
-		return nil, &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToRemovePlaylistItems, Err: err}
+// RemovePlaylistItemsCtx implements the DefaultPlaylistService's interface RemovePlaylistItemsCtx method.
+func (service *DefaultPlaylistService) RemovePlaylistItemsCtx(ctx context.Context, input models.RemovePlaylistItemsRequest) (*models.RemovePlaylistItems, error) {
+	// Check the granted scopes
+	if err := checkScopes(service.client, "PlaylistService.RemovePlaylistItems"); err != nil {
+		return nil, err
 	}
 
-	// Handle Spotify API error
-	if res.StatusCode != http.StatusOK {
-		return nil, utils.ParseSpotifyError(res, utils.RegErrorType)
+	// Validate the input
+	if input.PlaylistId == "" {
+		return nil, utils.NewError(http.StatusBadRequest, consts.MsgPlaylistIdRequired, nil)
 	}
 
-	// Read the response body
-	data, err := io.ReadAll(res.Body)
-	if err != nil {
+	if err := validateMaxCount(len(input.Body.Tracks), consts.MaxPlaylistItemUris, consts.MsgTooManyUris); err != nil {
 		return nil, err
 	}
-	defer res.Body.Close()
 
-	// Unmarshal the response data into RemovePlaylistItems struct
-	var removePlaylistItems models.RemovePlaylistItems
-	if err := json.Unmarshal(data, &removePlaylistItems); err != nil {
-		return nil, err
-	}
+	// Substitute id in the endpoint
+	endpoint := fmt.Sprintf(consts.EndpointPlaylistItems, input.PlaylistId)
+
+	// Add inputs to the query parameters
+	params := map[string]string{"playlist_id": string(input.PlaylistId)}
 
-	// Return the RemovePlaylistItems
-	return &removePlaylistItems, nil
+	// Make an API call and decode the response data into RemovePlaylistItems struct
+	return doJSON[models.RemovePlaylistItems](ctx, service.client, http.MethodDelete, endpoint, params, input.Body, consts.MsgFailedToRemovePlaylistItems)
 }
 
 // GetCurrentUserPlaylists implements the DefaultPlaylistService's interface GetCurrentUserPlaylists method.
 func (service *DefaultPlaylistService) GetCurrentUserPlaylists(input models.GetCurrentUsersPlaylistsRequest) (*models.Playlists, error) {
+	return service.GetCurrentUserPlaylistsCtx(context.Background(), input)
+}
+
+// GetCurrentUserPlaylistsCtx implements the DefaultPlaylistService's interface GetCurrentUserPlaylistsCtx method.
+func (service *DefaultPlaylistService) GetCurrentUserPlaylistsCtx(ctx context.Context, input models.GetCurrentUsersPlaylistsRequest) (*models.Playlists, error) {
 	// Add inputs to the query parameters
 	params := map[string]string{"limit": strconv.Itoa(input.Limit), "offset": strconv.Itoa(input.Offset)}
 
-	// Make an API call
-	res, err := service.client.Get(context.Background(), consts.EndpointCurrentUsersPlaylists, params)
-	if err != nil {
-		return nil, &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToGetCurrentUsersPlaylists, Err: err}
-	}
-
-	// Handle Spotify API error
-	if res.StatusCode != http.StatusOK {
-		return nil, utils.ParseSpotifyError(res, utils.RegErrorType)
-	}
-
-	// Read the response body
-	data, err := io.ReadAll(res.Body)
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
-
-	// Unmarshal the response data into Playlists struct
-	var playlists models.Playlists
-	if err := json.Unmarshal(data, &playlists); err != nil {
-		return nil, err
-	}
+	// Make an API call and decode the response data into Playlists struct
+	return doJSON[models.Playlists](ctx, service.client, http.MethodGet, consts.EndpointCurrentUsersPlaylists, params, nil, consts.MsgFailedToGetCurrentUsersPlaylists)
+}
 
-	// Return the Playlists
-	return &playlists, nil
+// AllCurrentUserPlaylists implements the DefaultPlaylistService's interface AllCurrentUserPlaylists method.
+func (service *DefaultPlaylistService) AllCurrentUserPlaylists(ctx context.Context, input models.GetCurrentUsersPlaylistsRequest, maxItems int) iter.Seq2[models.SimplifiedPlaylist, error] {
+	// Default to the maximum page size
+	input.Limit = pageLimit(input.Limit)
+
+	// Walk the pages, fetching each one from its offset
+	return Paginate(ctx, input.Offset, maxItems,
+		func(ctx context.Context, offset int) (*models.Playlists, error) {
+			input.Offset = offset
+			return service.GetCurrentUserPlaylistsCtx(ctx, input)
+		},
+		func(page *models.Playlists) ([]models.SimplifiedPlaylist, string) {
+			return page.Items, page.Next
+		},
+	)
 }
 
 // GetUserPlaylists implements the DefaultPlaylistService's interface GetUserPlaylists method.
 func (service *DefaultPlaylistService) GetUserPlaylists(input models.GetUsersPlaylistsRequest) (*models.Playlists, error) {
+	return service.GetUserPlaylistsCtx(context.Background(), input)
+}
+
+// GetUserPlaylistsCtx implements the DefaultPlaylistService's interface GetUserPlaylistsCtx method.
+func (service *DefaultPlaylistService) GetUserPlaylistsCtx(ctx context.Context, input models.GetUsersPlaylistsRequest) (*models.Playlists, error) {
 	// Validate the input
 	if input.UserId == "" {
-		return nil, &utils.AppError{Status: http.StatusBadRequest, Message: consts.MsgUserIdRequired}
+		return nil, utils.NewError(http.StatusBadRequest, consts.MsgUserIdRequired, nil)
 	}
 
 	// Substitute id in the endpoint
 	endpoint := fmt.Sprintf(consts.EndpointUsersPlaylists, input.UserId)
 
 	// Add inputs to the query parameters
-	params := map[string]string{"user_id": input.UserId, "limit": strconv.Itoa(input.Limit), "offset": strconv.Itoa(input.Offset)}
+	params := map[string]string{"user_id": string(input.UserId), "limit": strconv.Itoa(input.Limit), "offset": strconv.Itoa(input.Offset)}
 
-	// Make an API call
-	res, err := service.client.Get(context.Background(), endpoint, params)
-	if err != nil {
-		return nil, &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToGetUsersItems, Err: err}
-	}
+	// Make an API call and decode the response data into Playlists struct
+	return doJSON[models.Playlists](ctx, service.client, http.MethodGet, endpoint, params, nil, consts.MsgFailedToGetUsersItems)
+}
 
-	// Handle Spotify API error
-	if res.StatusCode != http.StatusOK {
-		return nil, utils.ParseSpotifyError(res, utils.RegErrorType)
-	}
+// AllUserPlaylists implements the DefaultPlaylistService's interface AllUserPlaylists method.
+func (service *DefaultPlaylistService) AllUserPlaylists(ctx context.Context, input models.GetUsersPlaylistsRequest, maxItems int) iter.Seq2[models.SimplifiedPlaylist, error] {
+	// Default to the maximum page size
+	input.Limit = pageLimit(input.Limit)
+
+	// Walk the pages, fetching each one from its offset
+	return Paginate(ctx, input.Offset, maxItems,
+		func(ctx context.Context, offset int) (*models.Playlists, error) {
+			input.Offset = offset
+			return service.GetUserPlaylistsCtx(ctx, input)
+		},
+		func(page *models.Playlists) ([]models.SimplifiedPlaylist, string) {
+			return page.Items, page.Next
+		},
+	)
+}
 
-	// Read the response body
-	data, err := io.ReadAll(res.Body)
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
+// CreatePlaylist implements the DefaultPlaylistService's interface CreatePlaylist method.
+func (service *DefaultPlaylistService) CreatePlaylist(input models.CreatePlaylistRequest) (*models.Playlist, error) {
+	return service.CreatePlaylistCtx(context.Background(), input)
+}
 
-	// Unmarshal the response data into Playlists struct
-	var userPlaylists models.Playlists
-	if err := json.Unmarshal(data, &userPlaylists); err != nil {
+// CreatePlaylistCtx implements the DefaultPlaylistService's interface CreatePlaylistCtx method.
+func (service *DefaultPlaylistService) CreatePlaylistCtx(ctx context.Context, input models.CreatePlaylistRequest) (*models.Playlist, error) {
+	// Check the granted scopes
+	if err := checkScopes(service.client, "PlaylistService.CreatePlaylist"); err != nil {
 		return nil, err
 	}
 
-	// Return the Playlists
-	return &userPlaylists, nil
-}
-
-// CreatePlaylist implements the DefaultPlaylistService's interface CreatePlaylist method.
-func (service *DefaultPlaylistService) CreatePlaylist(input models.CreatePlaylistRequest) (*models.Playlist, error) {
 	// Validate the input
 	if input.UserId == "" {
-		return nil, &utils.AppError{Status: http.StatusBadRequest, Message: consts.MsgUserIdRequired}
+		return nil, utils.NewError(http.StatusBadRequest, consts.MsgUserIdRequired, nil)
 	}
 
 	// Substitute id in the endpoint
 	endpoint := fmt.Sprintf(consts.EndpointUsersPlaylists, input.UserId)
 
 	// Add inputs to the query parameters
-	params := map[string]string{"user_id": input.UserId}
-
-	// Make an API call
-	res, err := service.client.Post(context.Background(), endpoint, nil, params, nil, input.Body)
-	if err != nil {
-		return nil, &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToCreatePlaylist, Err: err}
-	}
+	params := map[string]string{"user_id": string(input.UserId)}
 
-	// Handle Spotify API error
-	if res.StatusCode != http.StatusOK {
-		return nil, utils.ParseSpotifyError(res, utils.RegErrorType)
-	}
-
-	// Read the response body
-	data, err := io.ReadAll(res.Body)
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
-
-	// Unmarshal the response data into Playlist struct
-	var playlist models.Playlist
-	if err := json.Unmarshal(data, &playlist); err != nil {
-		return nil, err
-	}
-
-	// Return the Playlist
-	return &playlist, nil
+	// Make an API call and decode the response data into Playlist struct
+	return doJSON[models.Playlist](ctx, service.client, http.MethodPost, endpoint, params, input.Body, consts.MsgFailedToCreatePlaylist)
 }
 
 // GetFeaturedPlaylists implements the DefaultPlaylistService's interface GetFeaturedPlaylists method.
 func (service *DefaultPlaylistService) GetFeaturedPlaylists(input models.GetFeaturedPlaylistsRequest) (*models.FeaturedPlaylists, error) {
+	return service.GetFeaturedPlaylistsCtx(context.Background(), input)
+}
+
+// GetFeaturedPlaylistsCtx implements the DefaultPlaylistService's interface GetFeaturedPlaylistsCtx method.
+func (service *DefaultPlaylistService) GetFeaturedPlaylistsCtx(ctx context.Context, input models.GetFeaturedPlaylistsRequest) (*models.FeaturedPlaylists, error) {
 	// Add inputs to the query parameters
 	params := map[string]string{"locale": input.Locale, "limit": strconv.Itoa(input.Limit), "offset": strconv.Itoa(input.Offset)}
 
-	// Make an API call
-	res, err := service.client.Get(context.Background(), consts.EndpointFeaturedPlaylists, params)
-	if err != nil {
-		return nil, &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToGetFeaturedPlaylists, Err: err}
-	}
-
-	// Handle Spotify API error
-	if res.StatusCode != http.StatusOK {
-		return nil, utils.ParseSpotifyError(res, utils.RegErrorType)
-	}
-
-	// Read the response body
-	data, err := io.ReadAll(res.Body)
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
-
-	// Unmarshal the response data into FeaturedPlaylists struct
-	var featuredPlaylists models.FeaturedPlaylists
-	if err := json.Unmarshal(data, &featuredPlaylists); err != nil {
-		return nil, err
-	}
+	// Make an API call and decode the response data into FeaturedPlaylists struct
+	return doJSON[models.FeaturedPlaylists](ctx, service.client, http.MethodGet, consts.EndpointFeaturedPlaylists, params, nil, consts.MsgFailedToGetFeaturedPlaylists)
+}
 
-	// Return the FeaturedPlaylists
-	return &featuredPlaylists, nil
+// AllFeaturedPlaylists implements the DefaultPlaylistService's interface AllFeaturedPlaylists method.
+func (service *DefaultPlaylistService) AllFeaturedPlaylists(ctx context.Context, input models.GetFeaturedPlaylistsRequest, maxItems int) iter.Seq2[models.SimplifiedPlaylist, error] {
+	// Default to the maximum page size
+	input.Limit = pageLimit(input.Limit)
+
+	// Walk the pages, fetching each one from its offset
+	return Paginate(ctx, input.Offset, maxItems,
+		func(ctx context.Context, offset int) (*models.FeaturedPlaylists, error) {
+			input.Offset = offset
+			return service.GetFeaturedPlaylistsCtx(ctx, input)
+		},
+		func(page *models.FeaturedPlaylists) ([]models.SimplifiedPlaylist, string) {
+			return page.Playlists.Items, page.Playlists.Next
+		},
+	)
 }
 
 // GetCategoryPlaylists implements the DefaultPlaylistService's interface GetCategoryPlaylists method.
 func (service *DefaultPlaylistService) GetCategoryPlaylists(input models.GetCategoryPlaylistsRequest) (*models.CategoryPlaylists, error) {
+	return service.GetCategoryPlaylistsCtx(context.Background(), input)
+}
+
+// GetCategoryPlaylistsCtx implements the DefaultPlaylistService's interface GetCategoryPlaylistsCtx method.
+func (service *DefaultPlaylistService) GetCategoryPlaylistsCtx(ctx context.Context, input models.GetCategoryPlaylistsRequest) (*models.CategoryPlaylists, error) {
 	// Validate the input
 	if input.CategoryId == "" {
-		return nil, &utils.AppError{Status: http.StatusBadRequest, Message: consts.MsgCategoryIdRequired}
+		return nil, utils.NewError(http.StatusBadRequest, consts.MsgCategoryIdRequired, nil)
 	}
 
 	// Substitute id in the endpoint
@@ -482,96 +506,69 @@ func (service *DefaultPlaylistService) GetCategoryPlaylists(input models.GetCate
 	// Add inputs to the query parameters
 	params := map[string]string{"category_id": input.CategoryId, "limit": strconv.Itoa(input.Limit), "offset": strconv.Itoa(input.Offset)}
 
-	// Make an API call
-	res, err := service.client.Get(context.Background(), endpoint, params)
-	if err != nil {
-		return nil, &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToGetCategoryPlaylists, Err: err}
-	}
-
-	// Handle Spotify API error
-	if res.StatusCode != http.StatusOK {
-		return nil, utils.ParseSpotifyError(res, utils.RegErrorType)
-	}
-
-	// Read the response body
-	data, err := io.ReadAll(res.Body)
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
-
-	// Unmarshal the response data into CategoryPlaylists struct
-	var categoryPlaylists models.CategoryPlaylists
-	if err := json.Unmarshal(data, &categoryPlaylists); err != nil {
-		return nil, err
-	}
+	// Make an API call and decode the response data into CategoryPlaylists struct
+	return doJSON[models.CategoryPlaylists](ctx, service.client, http.MethodGet, endpoint, params, nil, consts.MsgFailedToGetCategoryPlaylists)
+}
 
-	// Return the CategoryPlaylists
-	return &categoryPlaylists, nil
+// AllCategoryPlaylists implements the DefaultPlaylistService's interface AllCategoryPlaylists method.
+func (service *DefaultPlaylistService) AllCategoryPlaylists(ctx context.Context, input models.GetCategoryPlaylistsRequest, maxItems int) iter.Seq2[models.SimplifiedPlaylist, error] {
+	// Default to the maximum page size
+	input.Limit = pageLimit(input.Limit)
+
+	// Walk the pages, fetching each one from its offset
+	return Paginate(ctx, input.Offset, maxItems,
+		func(ctx context.Context, offset int) (*models.CategoryPlaylists, error) {
+			input.Offset = offset
+			return service.GetCategoryPlaylistsCtx(ctx, input)
+		},
+		func(page *models.CategoryPlaylists) ([]models.SimplifiedPlaylist, string) {
+			return page.Playlists.Items, page.Playlists.Next
+		},
+	)
 }
 
 // GetPlaylistCoverImage implements the DefaultPlaylistService's interface GetPlaylistCoverImage method.
 func (service *DefaultPlaylistService) GetPlaylistCoverImage(input models.GetPlaylistCoverImageRequest) (*models.PlaylistCoverImage, error) {
+	return service.GetPlaylistCoverImageCtx(context.Background(), input)
+}
+
+// GetPlaylistCoverImageCtx implements the DefaultPlaylistService's interface GetPlaylistCoverImageCtx method.
+func (service *DefaultPlaylistService) GetPlaylistCoverImageCtx(ctx context.Context, input models.GetPlaylistCoverImageRequest) (*models.PlaylistCoverImage, error) {
 	// Validate the input
 	if input.PlaylistId == "" {
-		return nil, &utils.AppError{Status: http.StatusBadRequest, Message: consts.MsgPlaylistIdRequired}
+		return nil, utils.NewError(http.StatusBadRequest, consts.MsgPlaylistIdRequired, nil)
 	}
 
 	// Substitute id in the endpoint
 	endpoint := fmt.Sprintf(consts.EndpointPlaylistCoverImage, input.PlaylistId)
 
 	// Add inputs to the query parameters
-	params := map[string]string{"playlist_id": input.PlaylistId}
+	params := map[string]string{"playlist_id": string(input.PlaylistId)}
 
-	// Make an API call
-	res, err := service.client.Get(context.Background(), endpoint, params)
-	if err != nil {
-		return nil, &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToGetPlaylistCoverImage, Err: err}
-	}
-
-	// Handle Spotify API error
-	if res.StatusCode != http.StatusOK {
-		return nil, utils.ParseSpotifyError(res, utils.RegErrorType)
-	}
-
-	// Read the response body
-	data, err := io.ReadAll(res.Body)
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
-
-	// Unmarshal the response data into PlaylistCoverImage struct
-	var playlistCoverImage models.PlaylistCoverImage
-	if err := json.Unmarshal(data, &playlistCoverImage); err != nil {
-		return nil, err
-	}
-
-	// Return the PlaylistCoverImage
-	return &playlistCoverImage, nil
+	// Make an API call and decode the response data into PlaylistCoverImage struct
+	return doJSON[models.PlaylistCoverImage](ctx, service.client, http.MethodGet, endpoint, params, nil, consts.MsgFailedToGetPlaylistCoverImage)
 }
 
 // AddCustomPlaylistCoverImage implements the DefaultPlaylistService's interface AddCustomPlaylistCoverImage method.
 func (service *DefaultPlaylistService) AddCustomPlaylistCoverImage(input models.GetCustomPlaylistCoverImageRequest) error {
+	return service.AddCustomPlaylistCoverImageCtx(context.Background(), input)
+}
+
+// AddCustomPlaylistCoverImageCtx implements the DefaultPlaylistService's interface AddCustomPlaylistCoverImageCtx method.
+func (service *DefaultPlaylistService) AddCustomPlaylistCoverImageCtx(ctx context.Context, input models.GetCustomPlaylistCoverImageRequest) error {
+	// Check the granted scopes
+	if err := checkScopes(service.client, "PlaylistService.AddCustomPlaylistCoverImage"); err != nil {
+		return err
+	}
+
 	// Validate the input
 	if input.PlaylistId == "" {
-		return &utils.AppError{Status: http.StatusBadRequest, Message: consts.MsgPlaylistIdRequired}
+		return utils.NewError(http.StatusBadRequest, consts.MsgPlaylistIdRequired, nil)
 	}
 
 	// Add inputs to the query parameters
-	params := map[string]string{"playlist_id": input.PlaylistId}
-
-	// Make an API call
-	res, err := service.client.Put(context.Background(), consts.EndpointPlaylistCoverImage, nil, params, input.Body)
-	if err != nil {
-		return &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToAddCustomPlaylistCoverImage, Err: err}
-	}
-
-	// Handle Spotify API error
-	if res.StatusCode != http.StatusOK {
-		return utils.ParseSpotifyError(res, utils.RegErrorType)
-	}
+	params := map[string]string{"playlist_id": string(input.PlaylistId)}
 
-	// Return the empty response
-	return nil
+	// Make an API call and discard the response body
+	return doEmpty(ctx, service.client, http.MethodPut, consts.EndpointPlaylistCoverImage, params, input.Body, consts.MsgFailedToAddCustomPlaylistCoverImage)
 }
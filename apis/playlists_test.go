@@ -0,0 +1,213 @@
+package apis
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/alicse3/gospotify/models"
+	"github.com/alicse3/gospotify/spotifyid"
+	"github.com/alicse3/gospotify/utils"
+)
+
+// playlistRequest is a request to the playlist items endpoint, as received by the playlistServer.
+type playlistRequest struct {
+	method   string
+	uris     int
+	position string
+}
+
+// playlistServer serves the playlist items endpoint, recording the requests and failing the failAt-th one.
+// Every request answers with the snapshot ID s1, s2 and so on.
+type playlistServer struct {
+	failAt   int
+	requests []playlistRequest
+	mu       sync.Mutex
+}
+
+func (ps *playlistServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	ps.mu.Lock()
+	ps.requests = append(ps.requests, playlistRequest{
+		method:   r.Method,
+		uris:     len(strings.Split(query.Get("uris"), ",")),
+		position: query.Get("position"),
+	})
+	n := len(ps.requests)
+	ps.mu.Unlock()
+
+	if n == ps.failAt {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(`{"error":{"status":500,"message":"Server error"}}`))
+		return
+	}
+	fmt.Fprintf(w, `{"snapshot_id":"s%d"}`, n)
+}
+
+// newPlaylistService returns a PlaylistService for a playlistServer failing the failAt-th request, if any.
+func newPlaylistService(t *testing.T, failAt int) (PlaylistService, *playlistServer) {
+	ps := &playlistServer{failAt: failAt}
+	server := httptest.NewServer(ps)
+	t.Cleanup(server.Close)
+
+	return NewDefaultPlaylistService(utils.NewHttpClient(server.URL)), ps
+}
+
+// playlistUris returns n track URIs.
+func playlistUris(n int) []spotifyid.URI {
+	uris := make([]spotifyid.URI, n)
+	for i := range uris {
+		uris[i] = spotifyid.NewURI(spotifyid.TypeTrack, spotifyid.ID(strconv.Itoa(i)))
+	}
+	return uris
+}
+
+func TestUpdatePlaylistItemsChunks(t *testing.T) {
+	tests := []struct {
+		name         string
+		uris         int
+		failAt       int
+		want         []playlistRequest
+		wantSnapshot string
+	}{
+		{
+			name:         "full chunk",
+			uris:         100,
+			want:         []playlistRequest{{method: http.MethodPut, uris: 100}},
+			wantSnapshot: "s1",
+		},
+		{
+			name:         "one over a chunk",
+			uris:         101,
+			want:         []playlistRequest{{method: http.MethodPut, uris: 100}, {method: http.MethodPost, uris: 1}},
+			wantSnapshot: "s2",
+		},
+		{
+			name:         "two full chunks",
+			uris:         200,
+			want:         []playlistRequest{{method: http.MethodPut, uris: 100}, {method: http.MethodPost, uris: 100}},
+			wantSnapshot: "s2",
+		},
+		{
+			name: "one over two chunks",
+			uris: 201,
+			want: []playlistRequest{
+				{method: http.MethodPut, uris: 100}, {method: http.MethodPost, uris: 100}, {method: http.MethodPost, uris: 1},
+			},
+			wantSnapshot: "s3",
+		},
+		{
+			name:   "failed chunk",
+			uris:   201,
+			failAt: 2,
+			want:   []playlistRequest{{method: http.MethodPut, uris: 100}, {method: http.MethodPost, uris: 100}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, ps := newPlaylistService(t, tt.failAt)
+
+			got, err := service.UpdatePlaylistItemsCtx(context.Background(), models.UpdatePlaylistItemsRequest{PlaylistId: "playlist", Uris: playlistUris(tt.uris)})
+			if !slices.Equal(ps.requests, tt.want) {
+				t.Errorf("requests = %+v, want %+v", ps.requests, tt.want)
+			}
+			if tt.failAt > 0 {
+				if err == nil {
+					t.Errorf("UpdatePlaylistItemsCtx() = %+v, want the error of the failed chunk", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("UpdatePlaylistItemsCtx() error = %v", err)
+			}
+			if got.SnapshotId != tt.wantSnapshot {
+				t.Errorf("SnapshotId = %q, want the one of the last chunk %q", got.SnapshotId, tt.wantSnapshot)
+			}
+		})
+	}
+}
+
+func TestAddPlaylistItemsChunks(t *testing.T) {
+	tests := []struct {
+		name         string
+		uris         int
+		position     int
+		failAt       int
+		want         []playlistRequest
+		wantSnapshot string
+	}{
+		{
+			name:         "full chunk",
+			uris:         100,
+			position:     5,
+			want:         []playlistRequest{{method: http.MethodPost, uris: 100, position: "5"}},
+			wantSnapshot: "s1",
+		},
+		{
+			name:     "one over a chunk",
+			uris:     101,
+			position: 5,
+			want: []playlistRequest{
+				{method: http.MethodPost, uris: 100, position: "5"}, {method: http.MethodPost, uris: 1, position: "105"},
+			},
+			wantSnapshot: "s2",
+		},
+		{
+			name: "two full chunks",
+			uris: 200,
+			want: []playlistRequest{
+				{method: http.MethodPost, uris: 100, position: "0"}, {method: http.MethodPost, uris: 100, position: "100"},
+			},
+			wantSnapshot: "s2",
+		},
+		{
+			name:     "one over two chunks",
+			uris:     201,
+			position: 5,
+			want: []playlistRequest{
+				{method: http.MethodPost, uris: 100, position: "5"},
+				{method: http.MethodPost, uris: 100, position: "105"},
+				{method: http.MethodPost, uris: 1, position: "205"},
+			},
+			wantSnapshot: "s3",
+		},
+		{
+			name:     "failed chunk",
+			uris:     201,
+			position: 5,
+			failAt:   1,
+			want:     []playlistRequest{{method: http.MethodPost, uris: 100, position: "5"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, ps := newPlaylistService(t, tt.failAt)
+
+			input := models.AddPlaylistItemsRequest{PlaylistId: "playlist", Position: tt.position, Uris: playlistUris(tt.uris)}
+			got, err := service.AddPlaylistItemsCtx(context.Background(), input)
+			if !slices.Equal(ps.requests, tt.want) {
+				t.Errorf("requests = %+v, want %+v", ps.requests, tt.want)
+			}
+			if tt.failAt > 0 {
+				if err == nil {
+					t.Errorf("AddPlaylistItemsCtx() = %+v, want the error of the failed chunk", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("AddPlaylistItemsCtx() error = %v", err)
+			}
+			if got.SnapshotId != tt.wantSnapshot {
+				t.Errorf("SnapshotId = %q, want the one of the last chunk %q", got.SnapshotId, tt.wantSnapshot)
+			}
+		})
+	}
+}
@@ -0,0 +1,251 @@
+package apis
+
+import (
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/alicse3/gospotify/consts"
+	"github.com/alicse3/gospotify/utils"
+)
+
+// ScopeRequirement describes the authorization scopes a method needs.
+// For details, visit: https://developer.spotify.com/documentation/web-api/concepts/scopes
+type ScopeRequirement struct {
+	// Scopes which are all needed
+	Required []string
+	// Scopes of which at least one is needed, e.g. playlist-modify-public or playlist-modify-private depending on the playlist
+	AnyOf []string
+	// Scopes which are not needed, but without which some data is left out of the response, e.g. the user's email
+	Optional []string
+}
+
+// Missing returns the needed scopes which are not among the granted ones, all of AnyOf if none of them is granted.
+func (sr ScopeRequirement) Missing(granted []string) []string {
+	var missing []string
+	for _, scope := range sr.Required {
+		if !slices.Contains(granted, scope) {
+			missing = append(missing, scope)
+		}
+	}
+	if len(sr.AnyOf) > 0 && !slices.ContainsFunc(sr.AnyOf, func(scope string) bool { return slices.Contains(granted, scope) }) {
+		missing = append(missing, sr.AnyOf...)
+	}
+
+	return missing
+}
+
+// Scope requirements shared by several methods
+var (
+	scopesLibraryRead     = ScopeRequirement{Required: []string{"user-library-read"}}
+	scopesLibraryModify   = ScopeRequirement{Required: []string{"user-library-modify"}}
+	scopesPlaybackRead    = ScopeRequirement{Required: []string{"user-read-playback-state"}}
+	scopesPlaybackModify  = ScopeRequirement{Required: []string{"user-modify-playback-state"}}
+	scopesCurrentlyPlayed = ScopeRequirement{Required: []string{"user-read-currently-playing"}}
+	scopesQueue           = ScopeRequirement{Required: []string{"user-read-currently-playing", "user-read-playback-state"}}
+	scopesRecentlyPlayed  = ScopeRequirement{Required: []string{"user-read-recently-played"}}
+	scopesPlaybackPos     = ScopeRequirement{Optional: []string{"user-read-playback-position"}}
+	scopesPlaylistModify  = ScopeRequirement{AnyOf: []string{"playlist-modify-public", "playlist-modify-private"}}
+	scopesPlaylistRead    = ScopeRequirement{Optional: []string{"playlist-read-private"}}
+	scopesUserPlaylists   = ScopeRequirement{Optional: []string{"playlist-read-private", "playlist-read-collaborative"}}
+	scopesFollowRead      = ScopeRequirement{Required: []string{"user-follow-read"}}
+	scopesFollowModify    = ScopeRequirement{Required: []string{"user-follow-modify"}}
+	scopesTopRead         = ScopeRequirement{Required: []string{"user-top-read"}}
+	scopesSavedEpisodes   = ScopeRequirement{Required: []string{"user-library-read"}, Optional: []string{"user-read-playback-position"}}
+	scopesPlaylistImage   = ScopeRequirement{Required: []string{"ugc-image-upload"}, AnyOf: []string{"playlist-modify-public", "playlist-modify-private"}}
+	scopesCurrentUser     = ScopeRequirement{Optional: []string{"user-read-private", "user-read-email"}}
+)
+
+// MethodScopes holds the scope requirements of the service methods which need or make use of any scope, keyed by "Service.Method",
+// e.g. "TrackService.SaveTracks". The ...Ctx variants, iterators and ...Batch methods have the requirements of the method they're based on.
+var MethodScopes = map[string]ScopeRequirement{
+	"AlbumService.GetSavedAlbums":   scopesLibraryRead,
+	"AlbumService.AllSavedAlbums":   scopesLibraryRead,
+	"AlbumService.SaveAlbums":       scopesLibraryModify,
+	"AlbumService.RemoveAlbums":     scopesLibraryModify,
+	"AlbumService.CheckSavedAlbums": scopesLibraryRead,
+
+	"AudiobookService.GetSavedAudiobooks":   scopesLibraryRead,
+	"AudiobookService.AllSavedAudiobooks":   scopesLibraryRead,
+	"AudiobookService.SaveAudiobooks":       scopesLibraryModify,
+	"AudiobookService.DeleteAudiobooks":     scopesLibraryModify,
+	"AudiobookService.CheckSavedAudiobooks": scopesLibraryRead,
+
+	"EpisodeService.GetEpisode":         scopesPlaybackPos,
+	"EpisodeService.GetEpisodes":        scopesPlaybackPos,
+	"EpisodeService.GetEpisodesBatch":   scopesPlaybackPos,
+	"EpisodeService.GetSavedEpisodes":   scopesSavedEpisodes,
+	"EpisodeService.AllSavedEpisodes":   scopesSavedEpisodes,
+	"EpisodeService.SaveEpisodes":       scopesLibraryModify,
+	"EpisodeService.RemoveEpisodes":     scopesLibraryModify,
+	"EpisodeService.CheckSavedEpisodes": scopesLibraryRead,
+
+	"PlayerService.GetPlaybackState":          scopesPlaybackRead,
+	"PlayerService.TransferPlayback":          scopesPlaybackModify,
+	"PlayerService.GetAvailableDevices":       scopesPlaybackRead,
+	"PlayerService.GetCurrentlyPlayingTrack":  scopesCurrentlyPlayed,
+	"PlayerService.StartOrResumePlayback":     scopesPlaybackModify,
+	"PlayerService.PausePlayback":             scopesPlaybackModify,
+	"PlayerService.SkipToNext":                scopesPlaybackModify,
+	"PlayerService.SkipToPrevious":            scopesPlaybackModify,
+	"PlayerService.SeekToPosition":            scopesPlaybackModify,
+	"PlayerService.SetRepeatMode":             scopesPlaybackModify,
+	"PlayerService.SetPlaybackVolume":         scopesPlaybackModify,
+	"PlayerService.TogglePlaybackShuffle":     scopesPlaybackModify,
+	"PlayerService.GetRecentlyPlayedTracks":   scopesRecentlyPlayed,
+	"PlayerService.AllRecentlyPlayedTracks":   scopesRecentlyPlayed,
+	"PlayerService.RecentlyPlayedTracksSince": scopesRecentlyPlayed,
+	"PlayerService.GetUsersQueue":             scopesQueue,
+	"PlayerService.AddItemToPlaybackQueue":    scopesPlaybackModify,
+
+	"PlaylistService.ChangePlaylistDetails":       scopesPlaylistModify,
+	"PlaylistService.GetPlaylistItems":            scopesPlaylistRead,
+	"PlaylistService.AllPlaylistItems":            scopesPlaylistRead,
+	"PlaylistService.UpdatePlaylistItems":         scopesPlaylistModify,
+	"PlaylistService.AddPlaylistItems":            scopesPlaylistModify,
+	"PlaylistService.RemovePlaylistItems":         scopesPlaylistModify,
+	"PlaylistService.GetCurrentUserPlaylists":     scopesPlaylistRead,
+	"PlaylistService.AllCurrentUserPlaylists":     scopesPlaylistRead,
+	"PlaylistService.GetUserPlaylists":            scopesUserPlaylists,
+	"PlaylistService.AllUserPlaylists":            scopesUserPlaylists,
+	"PlaylistService.CreatePlaylist":              scopesPlaylistModify,
+	"PlaylistService.AddCustomPlaylistCoverImage": scopesPlaylistImage,
+
+	"ShowService.GetShow":          scopesPlaybackPos,
+	"ShowService.GetShowsBatch":    scopesPlaybackPos,
+	"ShowService.GetShowEpisodes":  scopesPlaybackPos,
+	"ShowService.AllShowEpisodes":  scopesPlaybackPos,
+	"ShowService.GetSavedShows":    scopesLibraryRead,
+	"ShowService.AllSavedShows":    scopesLibraryRead,
+	"ShowService.SaveShows":        scopesLibraryModify,
+	"ShowService.RemoveSavedShows": scopesLibraryModify,
+	"ShowService.CheckSavedShows":  scopesLibraryRead,
+
+	"TrackService.GetSavedTracks":    scopesLibraryRead,
+	"TrackService.AllSavedTracks":    scopesLibraryRead,
+	"TrackService.SaveTracks":        scopesLibraryModify,
+	"TrackService.RemoveSavedTracks": scopesLibraryModify,
+	"TrackService.CheckSavedTracks":  scopesLibraryRead,
+
+	"UserService.GetCurrentUserProfile":          scopesCurrentUser,
+	"UserService.GetUserTopItems":                scopesTopRead,
+	"UserService.AllUserTopItems":                scopesTopRead,
+	"UserService.FollowPlaylist":                 scopesPlaylistModify,
+	"UserService.UnfollowPlaylist":               scopesPlaylistModify,
+	"UserService.GetFollowedArtists":             scopesFollowRead,
+	"UserService.AllFollowedArtists":             scopesFollowRead,
+	"UserService.FollowArtistsOrUsers":           scopesFollowModify,
+	"UserService.UnfollowArtistsOrUsers":         scopesFollowModify,
+	"UserService.CheckUserFollowsArtistsOrUsers": scopesFollowRead,
+}
+
+// ScopesOf returns the scope requirements of the given method, e.g. "TrackService.SaveTracks" or "TrackService.SaveTracksCtx".
+// The methods which don't need any scope, e.g. the catalog ones, have an empty requirement.
+func ScopesOf(method string) ScopeRequirement {
+	return MethodScopes[strings.TrimSuffix(method, "Ctx")]
+}
+
+// MinimalScopes returns the smallest set of scopes needed for calling all the given methods, in the format of MethodScopes.
+// The optional scopes are left out, add them for the data they give access to.
+// Where one of several scopes is enough, the one needed by the most methods is picked, the first listed one on a tie.
+func MinimalScopes(methods ...string) ([]string, error) {
+	var scopes []string
+	var anyOfs [][]string
+	for _, method := range methods {
+		requirement, ok := MethodScopes[strings.TrimSuffix(method, "Ctx")]
+		if !ok && !isServiceMethod(method) {
+			return nil, utils.NewError(http.StatusBadRequest, consts.MsgUnknownMethod+": "+method, nil)
+		}
+
+		for _, scope := range requirement.Required {
+			if !slices.Contains(scopes, scope) {
+				scopes = append(scopes, scope)
+			}
+		}
+		if len(requirement.AnyOf) > 0 {
+			anyOfs = append(anyOfs, requirement.AnyOf)
+		}
+	}
+
+	// Pick a scope for every group which isn't satisfied yet, greedily by the number of groups it satisfies
+	for {
+		counts := map[string]int{}
+		var candidates []string
+		for _, anyOf := range anyOfs {
+			if slices.ContainsFunc(anyOf, func(scope string) bool { return slices.Contains(scopes, scope) }) {
+				continue
+			}
+			for _, scope := range anyOf {
+				if counts[scope] == 0 {
+					candidates = append(candidates, scope)
+				}
+				counts[scope]++
+			}
+		}
+		if len(candidates) == 0 {
+			return scopes, nil
+		}
+
+		best := candidates[0]
+		for _, scope := range candidates[1:] {
+			if counts[scope] > counts[best] {
+				best = scope
+			}
+		}
+		scopes = append(scopes, best)
+	}
+}
+
+// serviceTypes holds the service interfaces by name, for checking the method names given to MinimalScopes.
+var serviceTypes = map[string]reflect.Type{
+	"AlbumService":     reflect.TypeFor[AlbumService](),
+	"ArtistService":    reflect.TypeFor[ArtistService](),
+	"AudiobookService": reflect.TypeFor[AudiobookService](),
+	"CategoryService":  reflect.TypeFor[CategoryService](),
+	"ChapterService":   reflect.TypeFor[ChapterService](),
+	"EpisodeService":   reflect.TypeFor[EpisodeService](),
+	"GenreService":     reflect.TypeFor[GenreService](),
+	"MarketService":    reflect.TypeFor[MarketService](),
+	"PlayerService":    reflect.TypeFor[PlayerService](),
+	"PlaylistService":  reflect.TypeFor[PlaylistService](),
+	"SearchService":    reflect.TypeFor[SearchService](),
+	"ShowService":      reflect.TypeFor[ShowService](),
+	"TrackService":     reflect.TypeFor[TrackService](),
+	"UserService":      reflect.TypeFor[UserService](),
+}
+
+// isServiceMethod reports whether the given method of the format of MethodScopes is a method of one of the services,
+// e.g. one which doesn't need any scope.
+func isServiceMethod(method string) bool {
+	serviceName, methodName, ok := strings.Cut(method, ".")
+	if !ok {
+		return false
+	}
+
+	service, ok := serviceTypes[serviceName]
+	if !ok {
+		return false
+	}
+	_, ok = service.MethodByName(methodName)
+	return ok
+}
+
+// checkScopes makes sure the token of the client has been granted the scopes needed by the given method,
+// so a call which is bound to be rejected fails early with an error matching utils.ErrMissingScope.
+// It's skipped if the granted scopes are unknown, e.g. for a token given as is.
+func checkScopes(client *utils.HttpClient, method string) error {
+	tokens := client.TokenManager()
+	if tokens == nil {
+		return nil
+	}
+	authToken := tokens.Token()
+	if authToken == nil || authToken.Scope == "" {
+		return nil
+	}
+
+	if missing := ScopesOf(method).Missing(strings.Fields(authToken.Scope)); len(missing) > 0 {
+		return utils.NewMissingScopeError(method, missing)
+	}
+	return nil
+}
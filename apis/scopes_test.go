@@ -0,0 +1,117 @@
+package apis
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/alicse3/gospotify/models"
+	"github.com/alicse3/gospotify/utils"
+)
+
+func TestMinimalScopes(t *testing.T) {
+	tests := []struct {
+		name    string
+		methods []string
+		want    []string
+	}{
+		{name: "no method", methods: nil, want: nil},
+		{name: "catalog method", methods: []string{"AlbumService.GetAlbum"}, want: nil},
+		{name: "required scope", methods: []string{"TrackService.SaveTracks"}, want: []string{"user-library-modify"}},
+		{name: "ctx variant", methods: []string{"TrackService.SaveTracksCtx"}, want: []string{"user-library-modify"}},
+		{
+			name:    "shared scope listed once",
+			methods: []string{"TrackService.SaveTracks", "AlbumService.SaveAlbums", "AlbumService.GetSavedAlbums"},
+			want:    []string{"user-library-modify", "user-library-read"},
+		},
+		{name: "optional scopes left out", methods: []string{"UserService.GetCurrentUserProfile"}, want: nil},
+		{name: "first of any of on a tie", methods: []string{"PlaylistService.AddPlaylistItems"}, want: []string{"playlist-modify-public"}},
+		{
+			name:    "several required scopes",
+			methods: []string{"PlayerService.GetUsersQueue", "PlayerService.GetCurrentlyPlayingTrack", "PlayerService.GetPlaybackState"},
+			want:    []string{"user-read-currently-playing", "user-read-playback-state"},
+		},
+		{
+			name:    "required and any of",
+			methods: []string{"PlaylistService.AddCustomPlaylistCoverImage", "PlaylistService.RemovePlaylistItems"},
+			want:    []string{"ugc-image-upload", "playlist-modify-public"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MinimalScopes(tt.methods...)
+			if err != nil {
+				t.Fatalf("MinimalScopes(%v) error = %v", tt.methods, err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("MinimalScopes(%v) = %v, want %v", tt.methods, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMinimalScopesUnknownMethod(t *testing.T) {
+	for _, method := range []string{"TrackService.SaveTrackz", "NoSuchService.GetAlbum", "SaveTracks"} {
+		if _, err := MinimalScopes("TrackService.SaveTracks", method); err == nil {
+			t.Errorf("MinimalScopes(%s) error = nil, want an error", method)
+		}
+	}
+}
+
+func TestMethodScopesAreServiceMethods(t *testing.T) {
+	for method := range MethodScopes {
+		if !isServiceMethod(method) {
+			t.Errorf("MethodScopes has %s, which isn't a service method", method)
+		}
+	}
+}
+
+func TestScopeRequirementMissing(t *testing.T) {
+	requirement := ScopeRequirement{
+		Required: []string{"ugc-image-upload"},
+		AnyOf:    []string{"playlist-modify-public", "playlist-modify-private"},
+		Optional: []string{"playlist-read-private"},
+	}
+
+	tests := []struct {
+		name    string
+		granted []string
+		want    []string
+	}{
+		{name: "all granted", granted: []string{"ugc-image-upload", "playlist-modify-private"}, want: nil},
+		{name: "required missing", granted: []string{"playlist-modify-public"}, want: []string{"ugc-image-upload"}},
+		{name: "any of missing", granted: []string{"ugc-image-upload"}, want: []string{"playlist-modify-public", "playlist-modify-private"}},
+		{name: "nothing granted", granted: nil, want: []string{"ugc-image-upload", "playlist-modify-public", "playlist-modify-private"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := requirement.Missing(tt.granted); !slices.Equal(got, tt.want) {
+				t.Errorf("Missing(%v) = %v, want %v", tt.granted, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckScopes(t *testing.T) {
+	client := utils.NewHttpClient("https://api.spotify.com")
+
+	// Without a token, the granted scopes are unknown and nothing is checked
+	if err := checkScopes(client, "TrackService.SaveTracks"); err != nil {
+		t.Errorf("checkScopes() without a token error = %v, want nil", err)
+	}
+
+	client.SetTokenManager(utils.NewTokenManager(&models.AuthToken{AccessToken: "access", Scope: "user-library-read"}, nil))
+	if err := checkScopes(client, "TrackService.GetSavedTracks"); err != nil {
+		t.Errorf("checkScopes() of a granted scope error = %v, want nil", err)
+	}
+	err := checkScopes(client, "TrackService.SaveTracksCtx")
+	if !errors.Is(err, utils.ErrMissingScope) {
+		t.Fatalf("checkScopes() of a missing scope error = %v, want ErrMissingScope", err)
+	}
+	var e *utils.Error
+	if !errors.As(err, &e) || !slices.Equal(e.MissingScopes, []string{"user-library-modify"}) {
+		t.Errorf("checkScopes() error = %#v, want the missing user-library-modify scope", err)
+	}
+}
@@ -2,8 +2,7 @@ package apis
 
 import (
 	"context"
-	"encoding/json"
-	"io"
+	"iter"
 	"net/http"
 	"strconv"
 
@@ -17,6 +16,36 @@ type SearchService interface {
 	// Get Spotify catalog information about albums, artists, playlists, tracks, shows, episodes or audiobooks that match a keyword string.
 	// Audiobooks are only available within the US, UK, Canada, Ireland, New Zealand and Australia markets.
 	Search(input models.SearchRequest) (*models.SearchResponse, error)
+	// SearchCtx is like Search but carries the given context through to the API call.
+	SearchCtx(ctx context.Context, input models.SearchRequest) (*models.SearchResponse, error)
+
+	// SearchTracks searches for items of type track only and yields the matching tracks one by one, walking all the pages of Search starting at input.Offset.
+	// At most maxItems items are yielded, 0 means there is no cap.
+	SearchTracks(ctx context.Context, input models.SearchRequest, maxItems int) iter.Seq2[models.SimplifiedTrack, error]
+
+	// SearchArtists searches for items of type artist only and yields the matching artists one by one, walking all the pages of Search starting at input.Offset.
+	// At most maxItems items are yielded, 0 means there is no cap.
+	SearchArtists(ctx context.Context, input models.SearchRequest, maxItems int) iter.Seq2[models.Artist, error]
+
+	// SearchAlbums searches for items of type album only and yields the matching albums one by one, walking all the pages of Search starting at input.Offset.
+	// At most maxItems items are yielded, 0 means there is no cap.
+	SearchAlbums(ctx context.Context, input models.SearchRequest, maxItems int) iter.Seq2[models.Album, error]
+
+	// SearchPlaylists searches for items of type playlist only and yields the matching playlists one by one, walking all the pages of Search starting at input.Offset.
+	// At most maxItems items are yielded, 0 means there is no cap.
+	SearchPlaylists(ctx context.Context, input models.SearchRequest, maxItems int) iter.Seq2[models.SimplifiedPlaylist, error]
+
+	// SearchShows searches for items of type show only and yields the matching shows one by one, walking all the pages of Search starting at input.Offset.
+	// At most maxItems items are yielded, 0 means there is no cap.
+	SearchShows(ctx context.Context, input models.SearchRequest, maxItems int) iter.Seq2[models.SimplifiedShow, error]
+
+	// SearchEpisodes searches for items of type episode only and yields the matching episodes one by one, walking all the pages of Search starting at input.Offset.
+	// At most maxItems items are yielded, 0 means there is no cap.
+	SearchEpisodes(ctx context.Context, input models.SearchRequest, maxItems int) iter.Seq2[models.Episode, error]
+
+	// SearchAudiobooks searches for items of type audiobook only and yields the matching audiobooks one by one, walking all the pages of Search starting at input.Offset.
+	// At most maxItems items are yielded, 0 means there is no cap.
+	SearchAudiobooks(ctx context.Context, input models.SearchRequest, maxItems int) iter.Seq2[models.SimplifiedAudiobook, error]
 }
 
 // DefaultSearchService is a struct that implements SearchService interface.
@@ -31,41 +60,174 @@ func NewDefaultSearchService(client *utils.HttpClient) *DefaultSearchService {
 
 // Search implements the SearchService's interface Search method.
 func (service *DefaultSearchService) Search(input models.SearchRequest) (*models.SearchResponse, error) {
+	return service.SearchCtx(context.Background(), input)
+}
+
+// SearchCtx implements the SearchService's interface SearchCtx method.
+func (service *DefaultSearchService) SearchCtx(ctx context.Context, input models.SearchRequest) (*models.SearchResponse, error) {
 	// Validate the input
 	if input.Q == "" {
-		return nil, &utils.AppError{Status: http.StatusBadRequest, Message: consts.MsgSearchQueryRequired}
+		return nil, utils.NewError(http.StatusBadRequest, consts.MsgSearchQueryRequired, nil)
 	}
 	if len(input.Type) == 0 {
-		return nil, &utils.AppError{Status: http.StatusBadRequest, Message: consts.MsgSearchTypeRequired}
+		return nil, utils.NewError(http.StatusBadRequest, consts.MsgSearchTypeRequired, nil)
 	}
 
 	// Add inputs to the query parameters
 	params := map[string]string{"q": input.Q, "type": input.Type, "market": input.Market, "limit": strconv.Itoa(input.Limit), "offset": strconv.Itoa(input.Offset), "include_external": input.IncludeExternal}
 
-	// Make an API call
-	res, err := service.client.Get(context.Background(), consts.EndpointSearch, params)
-	if err != nil {
-		return nil, &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToGetSearchResults, Err: err}
-	}
+	// Make an API call and decode the response data into SearchResponse struct
+	return doJSON[models.SearchResponse](ctx, service.client, http.MethodGet, consts.EndpointSearch, params, nil, consts.MsgFailedToGetSearchResults)
+}
 
-	// Handle Spotify API error
-	if res.StatusCode != http.StatusOK {
-		return nil, utils.ParseSpotifyError(res, utils.RegErrorType)
-	}
+// SearchTracks implements the SearchService's interface SearchTracks method.
+func (service *DefaultSearchService) SearchTracks(ctx context.Context, input models.SearchRequest, maxItems int) iter.Seq2[models.SimplifiedTrack, error] {
+	// Search for the single type only
+	input.Type = "track"
 
-	// Read the response body
-	data, err := io.ReadAll(res.Body)
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
+	// Default to the maximum page size
+	input.Limit = pageLimit(input.Limit)
 
-	// Unmarshal the response data into SearchResponse struct
-	var searchResponse models.SearchResponse
-	if err := json.Unmarshal(data, &searchResponse); err != nil {
-		return nil, err
-	}
+	// Walk the pages, fetching each one from its offset
+	return Paginate(ctx, input.Offset, maxItems,
+		func(ctx context.Context, offset int) (*models.SearchResponse, error) {
+			input.Offset = offset
+			return service.SearchCtx(ctx, input)
+		},
+		func(page *models.SearchResponse) ([]models.SimplifiedTrack, string) {
+			return page.Tracks.Items, page.Tracks.Next
+		},
+	)
+}
+
+// SearchArtists implements the SearchService's interface SearchArtists method.
+func (service *DefaultSearchService) SearchArtists(ctx context.Context, input models.SearchRequest, maxItems int) iter.Seq2[models.Artist, error] {
+	// Search for the single type only
+	input.Type = "artist"
+
+	// Default to the maximum page size
+	input.Limit = pageLimit(input.Limit)
+
+	// Walk the pages, fetching each one from its offset
+	return Paginate(ctx, input.Offset, maxItems,
+		func(ctx context.Context, offset int) (*models.SearchResponse, error) {
+			input.Offset = offset
+			return service.SearchCtx(ctx, input)
+		},
+		func(page *models.SearchResponse) ([]models.Artist, string) {
+			items := make([]models.Artist, len(page.Artists.Items))
+			for i, item := range page.Artists.Items {
+				items[i] = item.Artist
+			}
+			return items, page.Artists.Next
+		},
+	)
+}
+
+// SearchAlbums implements the SearchService's interface SearchAlbums method.
+func (service *DefaultSearchService) SearchAlbums(ctx context.Context, input models.SearchRequest, maxItems int) iter.Seq2[models.Album, error] {
+	// Search for the single type only
+	input.Type = "album"
+
+	// Default to the maximum page size
+	input.Limit = pageLimit(input.Limit)
+
+	// Walk the pages, fetching each one from its offset
+	return Paginate(ctx, input.Offset, maxItems,
+		func(ctx context.Context, offset int) (*models.SearchResponse, error) {
+			input.Offset = offset
+			return service.SearchCtx(ctx, input)
+		},
+		func(page *models.SearchResponse) ([]models.Album, string) {
+			items := make([]models.Album, len(page.Albums.Items))
+			for i, item := range page.Albums.Items {
+				items[i] = item.Album
+			}
+			return items, page.Albums.Next
+		},
+	)
+}
+
+// SearchPlaylists implements the SearchService's interface SearchPlaylists method.
+func (service *DefaultSearchService) SearchPlaylists(ctx context.Context, input models.SearchRequest, maxItems int) iter.Seq2[models.SimplifiedPlaylist, error] {
+	// Search for the single type only
+	input.Type = "playlist"
+
+	// Default to the maximum page size
+	input.Limit = pageLimit(input.Limit)
+
+	// Walk the pages, fetching each one from its offset
+	return Paginate(ctx, input.Offset, maxItems,
+		func(ctx context.Context, offset int) (*models.SearchResponse, error) {
+			input.Offset = offset
+			return service.SearchCtx(ctx, input)
+		},
+		func(page *models.SearchResponse) ([]models.SimplifiedPlaylist, string) {
+			return page.Playlists.Items, page.Playlists.Next
+		},
+	)
+}
+
+// SearchShows implements the SearchService's interface SearchShows method.
+func (service *DefaultSearchService) SearchShows(ctx context.Context, input models.SearchRequest, maxItems int) iter.Seq2[models.SimplifiedShow, error] {
+	// Search for the single type only
+	input.Type = "show"
+
+	// Default to the maximum page size
+	input.Limit = pageLimit(input.Limit)
+
+	// Walk the pages, fetching each one from its offset
+	return Paginate(ctx, input.Offset, maxItems,
+		func(ctx context.Context, offset int) (*models.SearchResponse, error) {
+			input.Offset = offset
+			return service.SearchCtx(ctx, input)
+		},
+		func(page *models.SearchResponse) ([]models.SimplifiedShow, string) {
+			return page.Shows.Items, page.Shows.Next
+		},
+	)
+}
+
+// SearchEpisodes implements the SearchService's interface SearchEpisodes method.
+func (service *DefaultSearchService) SearchEpisodes(ctx context.Context, input models.SearchRequest, maxItems int) iter.Seq2[models.Episode, error] {
+	// Search for the single type only
+	input.Type = "episode"
+
+	// Default to the maximum page size
+	input.Limit = pageLimit(input.Limit)
+
+	// Walk the pages, fetching each one from its offset
+	return Paginate(ctx, input.Offset, maxItems,
+		func(ctx context.Context, offset int) (*models.SearchResponse, error) {
+			input.Offset = offset
+			return service.SearchCtx(ctx, input)
+		},
+		func(page *models.SearchResponse) ([]models.Episode, string) {
+			items := make([]models.Episode, len(page.Episodes.Items))
+			for i, item := range page.Episodes.Items {
+				items[i] = item.Episode
+			}
+			return items, page.Episodes.Next
+		},
+	)
+}
+
+// SearchAudiobooks implements the SearchService's interface SearchAudiobooks method.
+func (service *DefaultSearchService) SearchAudiobooks(ctx context.Context, input models.SearchRequest, maxItems int) iter.Seq2[models.SimplifiedAudiobook, error] {
+	// Search for the single type only
+	input.Type = "audiobook"
+
+	// Default to the maximum page size
+	input.Limit = pageLimit(input.Limit)
 
-	// Return the SearchResponse
-	return &searchResponse, nil
+	// Walk the pages, fetching each one from its offset
+	return Paginate(ctx, input.Offset, maxItems,
+		func(ctx context.Context, offset int) (*models.SearchResponse, error) {
+			input.Offset = offset
+			return service.SearchCtx(ctx, input)
+		},
+		func(page *models.SearchResponse) ([]models.SimplifiedAudiobook, string) {
+			return page.Audiobooks.Items, page.Audiobooks.Next
+		},
+	)
 }
@@ -2,14 +2,14 @@ package apis
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"io"
+	"iter"
 	"net/http"
 	"strconv"
 
 	"github.com/alicse3/gospotify/consts"
 	"github.com/alicse3/gospotify/models"
+	"github.com/alicse3/gospotify/spotifyid"
 	"github.com/alicse3/gospotify/utils"
 )
 
@@ -18,31 +18,58 @@ type ShowService interface {
 	// Get Spotify catalog information for a single show identified by its unique Spotify ID.
 	// Authorization scopes: user-read-playback-position
 	GetShow(input models.GetShowRequest) (*models.Show, error)
+	// GetShowCtx is like GetShow but carries the given context through to the API call.
+	GetShowCtx(ctx context.Context, input models.GetShowRequest) (*models.Show, error)
 
 	// Get Spotify catalog information for several shows based on their Spotify IDs.
 	GetShows(input models.GetShowsRequest) (*models.Shows, error)
+	// GetShowsCtx is like GetShows but carries the given context through to the API call.
+	GetShowsCtx(ctx context.Context, input models.GetShowsRequest) (*models.Shows, error)
+
+	// GetShowsBatch gets any number of shows, splitting the IDs into chunks of the maximum size of GetShows which are fetched concurrently,
+	// at most concurrency chunks at a time, 0 means a default of 4. The items are in the order of the IDs, nil for the unknown ones.
+	// The failed chunks are reported in the Failures of the result, along with an error joining theirs.
+	GetShowsBatch(ctx context.Context, input models.GetShowsRequest, concurrency int) (*BatchResult[models.SimplifiedShow], error)
 
 	// Get Spotify catalog information about an show’s episodes.
 	// Optional parameters can be used to limit the number of episodes returned.
 	// Authorization scopes: user-read-playback-position
 	GetShowEpisodes(input models.GetShowEpisodesRequest) (*models.ShowEpisodes, error)
+	// GetShowEpisodesCtx is like GetShowEpisodes but carries the given context through to the API call.
+	GetShowEpisodesCtx(ctx context.Context, input models.GetShowEpisodesRequest) (*models.ShowEpisodes, error)
+
+	// AllShowEpisodes yields the show's episodes one by one, walking all the pages of GetShowEpisodes starting at input.Offset.
+	// At most maxItems items are yielded, 0 means there is no cap.
+	AllShowEpisodes(ctx context.Context, input models.GetShowEpisodesRequest, maxItems int) iter.Seq2[models.SimplifiedEpisode, error]
 
 	// Get a list of shows saved in the current Spotify user's library.
 	// Optional parameters can be used to limit the number of shows returned.
 	// Authorization scopes: user-library-read
 	GetSavedShows(input models.GetSavedShowsRequest) (*models.SavedShows, error)
+	// GetSavedShowsCtx is like GetSavedShows but carries the given context through to the API call.
+	GetSavedShowsCtx(ctx context.Context, input models.GetSavedShowsRequest) (*models.SavedShows, error)
+
+	// AllSavedShows yields the saved shows one by one, walking all the pages of GetSavedShows starting at input.Offset.
+	// At most maxItems items are yielded, 0 means there is no cap.
+	AllSavedShows(ctx context.Context, input models.GetSavedShowsRequest, maxItems int) iter.Seq2[models.SavedShow, error]
 
 	// Save one or more shows to current Spotify user's library.
 	// Authorization scopes: user-library-modify
 	SaveShows(input models.SaveShowsRequest) error
+	// SaveShowsCtx is like SaveShows but carries the given context through to the API call.
+	SaveShowsCtx(ctx context.Context, input models.SaveShowsRequest) error
 
 	// Delete one or more shows from current Spotify user's library.
 	// Authorization scopes: user-library-modify
 	RemoveSavedShows(input models.RemoveShowsRequest) error
+	// RemoveSavedShowsCtx is like RemoveSavedShows but carries the given context through to the API call.
+	RemoveSavedShowsCtx(ctx context.Context, input models.RemoveShowsRequest) error
 
 	// Check if one or more shows is already saved in the current Spotify user's library.
 	// Authorization scopes: user-library-read
 	CheckSavedShows(input models.CheckSavedShowsRequest) (*models.CheckSavedShows, error)
+	// CheckSavedShowsCtx is like CheckSavedShows but carries the given context through to the API call.
+	CheckSavedShowsCtx(ctx context.Context, input models.CheckSavedShowsRequest) (*models.CheckSavedShows, error)
 }
 
 // DefaultShowService is a struct that implements ShowService interface.
@@ -57,241 +84,224 @@ func NewDefaultShowService(client *utils.HttpClient) *DefaultShowService {
 
 // GetShow implements the ShowService's interface GetShow method.
 func (service *DefaultShowService) GetShow(input models.GetShowRequest) (*models.Show, error) {
+	return service.GetShowCtx(context.Background(), input)
+}
+
+// GetShowCtx implements the ShowService's interface GetShowCtx method.
+func (service *DefaultShowService) GetShowCtx(ctx context.Context, input models.GetShowRequest) (*models.Show, error) {
 	// Validate the input
 	if input.Id == "" {
-		return nil, &utils.AppError{Status: http.StatusBadRequest, Message: consts.MsgIdRequired}
+		return nil, utils.NewError(http.StatusBadRequest, consts.MsgIdRequired, nil)
 	}
 
 	// Substitute id in the endpoint
 	endpoint := fmt.Sprintf(consts.EndpointShow, input.Id)
 
 	// Add inputs to the query parameters
-	params := map[string]string{"id": input.Id, "market": input.Market}
+	params := map[string]string{"id": string(input.Id), "market": input.Market}
 
-	// Make an API call
-	res, err := service.client.Get(context.Background(), endpoint, params)
-	if err != nil {
-		return nil, &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToGetShow, Err: err}
-	}
-
-	// Handle Spotify API error
-	if res.StatusCode != http.StatusOK {
-		return nil, utils.ParseSpotifyError(res, utils.RegErrorType)
-	}
-
-	// Read the response body
-	data, err := io.ReadAll(res.Body)
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
-
-	// Unmarshal the response data into Show struct
-	var show models.Show
-	if err := json.Unmarshal(data, &show); err != nil {
-		return nil, err
-	}
-
-	// Return the Show
-	return &show, nil
+	// Make an API call and decode the response data into Show struct
+	return doJSON[models.Show](ctx, service.client, http.MethodGet, endpoint, params, nil, consts.MsgFailedToGetShow)
 }
 
 // GetShows implements the ShowService's interface GetShows method.
 func (service *DefaultShowService) GetShows(input models.GetShowsRequest) (*models.Shows, error) {
-	// Validate the input
-	if input.Ids == "" {
-		return nil, &utils.AppError{Status: http.StatusBadRequest, Message: consts.MsgIdsRequired}
-	}
-
-	// Add inputs to the query parameters
-	params := map[string]string{"ids": input.Ids, "market": input.Market}
+	return service.GetShowsCtx(context.Background(), input)
+}
 
-	// Make an API call
-	res, err := service.client.Get(context.Background(), consts.EndpointShows, params)
-	if err != nil {
-		return nil, &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToGetShows, Err: err}
+// GetShowsCtx implements the ShowService's interface GetShowsCtx method.
+func (service *DefaultShowService) GetShowsCtx(ctx context.Context, input models.GetShowsRequest) (*models.Shows, error) {
+	// Validate the input
+	if err := validateIds(input.Ids); err != nil {
+		return nil, err
 	}
 
-	// Handle Spotify API error
-	if res.StatusCode != http.StatusOK {
-		return nil, utils.ParseSpotifyError(res, utils.RegErrorType)
-	}
+	// Make an API call per chunk of IDs and merge the responses
+	return getChunked(input.Ids, consts.MaxShowIds,
+		func(ids []spotifyid.ID) (*models.Shows, error) {
+			// Add inputs to the query parameters
+			params := map[string]string{"ids": join(ids), "market": input.Market}
+
+			// Make an API call and decode the response data into Shows struct
+			return doJSON[models.Shows](ctx, service.client, http.MethodGet, consts.EndpointShows, params, nil, consts.MsgFailedToGetShows)
+		},
+		func(result, chunk *models.Shows) {
+			result.Shows = append(result.Shows, chunk.Shows...)
+		},
+	)
+}
 
-	// Read the response body
-	data, err := io.ReadAll(res.Body)
-	if err != nil {
+// GetShowsBatch implements the ShowService's interface GetShowsBatch method.
+func (service *DefaultShowService) GetShowsBatch(ctx context.Context, input models.GetShowsRequest, concurrency int) (*BatchResult[models.SimplifiedShow], error) {
+	// Validate the input
+	if err := validateIds(input.Ids); err != nil {
 		return nil, err
 	}
-	defer res.Body.Close()
 
-	// Unmarshal the response data into Shows struct
-	var shows models.Shows
-	if err := json.Unmarshal(data, &shows); err != nil {
-		return nil, err
-	}
+	// Fetch the chunks of IDs concurrently
+	fetch := batchFetcher[models.SimplifiedShow](service.client, consts.EndpointShows, "shows", map[string]string{"market": input.Market}, consts.MsgFailedToGetShows)
+	result := FetchBatch(ctx, input.Ids, consts.MaxShowIds, concurrency, fetch)
 
-	// Return the Shows
-	return &shows, nil
+	return result, result.Err()
 }
 
 // GetShowEpisodes implements the ShowService's interface GetShowEpisodes method.
 func (service *DefaultShowService) GetShowEpisodes(input models.GetShowEpisodesRequest) (*models.ShowEpisodes, error) {
+	return service.GetShowEpisodesCtx(context.Background(), input)
+}
+
+// GetShowEpisodesCtx implements the ShowService's interface GetShowEpisodesCtx method.
+func (service *DefaultShowService) GetShowEpisodesCtx(ctx context.Context, input models.GetShowEpisodesRequest) (*models.ShowEpisodes, error) {
 	// Validate the input
 	if input.Id == "" {
-		return nil, &utils.AppError{Status: http.StatusBadRequest, Message: consts.MsgIdRequired}
+		return nil, utils.NewError(http.StatusBadRequest, consts.MsgIdRequired, nil)
 	}
 
 	// Substitute id in the endpoint
 	endpoint := fmt.Sprintf(consts.EndpointShowEpisodes, input.Id)
 
 	// Add inputs to the query parameters
-	params := map[string]string{"id": input.Id, "market": input.Market, "limit": strconv.Itoa(input.Limit), "offset": strconv.Itoa(input.Offset)}
+	params := map[string]string{"id": string(input.Id), "market": input.Market, "limit": strconv.Itoa(input.Limit), "offset": strconv.Itoa(input.Offset)}
 
-	// Make an API call
-	res, err := service.client.Get(context.Background(), endpoint, params)
-	if err != nil {
-		return nil, &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToGetShowEpisodes, Err: err}
-	}
-
-	// Handle Spotify API error
-	if res.StatusCode != http.StatusOK {
-		return nil, utils.ParseSpotifyError(res, utils.RegErrorType)
-	}
-
-	// Read the response body
-	data, err := io.ReadAll(res.Body)
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
-
-	// Unmarshal the response data into ShowEpisodes struct
-	var showEpisodes models.ShowEpisodes
-	if err := json.Unmarshal(data, &showEpisodes); err != nil {
-		return nil, err
-	}
+	// Make an API call and decode the response data into ShowEpisodes struct
+	return doJSON[models.ShowEpisodes](ctx, service.client, http.MethodGet, endpoint, params, nil, consts.MsgFailedToGetShowEpisodes)
+}
 
-	// Return the ShowEpisodes
-	return &showEpisodes, nil
+// AllShowEpisodes implements the ShowService's interface AllShowEpisodes method.
+func (service *DefaultShowService) AllShowEpisodes(ctx context.Context, input models.GetShowEpisodesRequest, maxItems int) iter.Seq2[models.SimplifiedEpisode, error] {
+	// Default to the maximum page size
+	input.Limit = pageLimit(input.Limit)
+
+	// Walk the pages, fetching each one from its offset
+	return Paginate(ctx, input.Offset, maxItems,
+		func(ctx context.Context, offset int) (*models.ShowEpisodes, error) {
+			input.Offset = offset
+			return service.GetShowEpisodesCtx(ctx, input)
+		},
+		func(page *models.ShowEpisodes) ([]models.SimplifiedEpisode, string) {
+			return page.Items, page.Next
+		},
+	)
 }
 
 // GetSavedShows implements the ShowService's interface GetSavedShows method.
 func (service *DefaultShowService) GetSavedShows(input models.GetSavedShowsRequest) (*models.SavedShows, error) {
-	// Add inputs to the query parameters
-	params := map[string]string{"limit": strconv.Itoa(input.Limit), "offset": strconv.Itoa(input.Offset)}
-
-	// Make an API call
-	res, err := service.client.Get(context.Background(), consts.EndpointSaveShows, params)
-	if err != nil {
-		return nil, &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToGetSavedShows, Err: err}
-	}
-
-	// Handle Spotify API error
-	if res.StatusCode != http.StatusOK {
-		return nil, utils.ParseSpotifyError(res, utils.RegErrorType)
-	}
+	return service.GetSavedShowsCtx(context.Background(), input)
+}
 
-	// Read the response body
-	data, err := io.ReadAll(res.Body)
-	if err != nil {
+// GetSavedShowsCtx implements the ShowService's interface GetSavedShowsCtx method.
+func (service *DefaultShowService) GetSavedShowsCtx(ctx context.Context, input models.GetSavedShowsRequest) (*models.SavedShows, error) {
+	// Check the granted scopes
+	if err := checkScopes(service.client, "ShowService.GetSavedShows"); err != nil {
 		return nil, err
 	}
-	defer res.Body.Close()
 
-	// Unmarshal the response data into SavedShows struct
-	var savedShows models.SavedShows
-	if err := json.Unmarshal(data, &savedShows); err != nil {
-		return nil, err
-	}
+	// Add inputs to the query parameters
+	params := map[string]string{"limit": strconv.Itoa(input.Limit), "offset": strconv.Itoa(input.Offset)}
 
-	// Return the SavedShows
-	return &savedShows, nil
+	// Make an API call and decode the response data into SavedShows struct
+	return doJSON[models.SavedShows](ctx, service.client, http.MethodGet, consts.EndpointSaveShows, params, nil, consts.MsgFailedToGetSavedShows)
+}
+
+// AllSavedShows implements the ShowService's interface AllSavedShows method.
+func (service *DefaultShowService) AllSavedShows(ctx context.Context, input models.GetSavedShowsRequest, maxItems int) iter.Seq2[models.SavedShow, error] {
+	// Default to the maximum page size
+	input.Limit = pageLimit(input.Limit)
+
+	// Walk the pages, fetching each one from its offset
+	return Paginate(ctx, input.Offset, maxItems,
+		func(ctx context.Context, offset int) (*models.SavedShows, error) {
+			input.Offset = offset
+			return service.GetSavedShowsCtx(ctx, input)
+		},
+		func(page *models.SavedShows) ([]models.SavedShow, string) {
+			return page.Items, page.Next
+		},
+	)
 }
 
 // SaveShows implements the ShowService's interface SaveShows method.
 func (service *DefaultShowService) SaveShows(input models.SaveShowsRequest) error {
-	// Validate the input
-	if input.Ids == "" {
-		return &utils.AppError{Status: http.StatusBadRequest, Message: consts.MsgIdsRequired}
-	}
-
-	// Add inputs to the query parameters
-	params := map[string]string{"ids": input.Ids}
+	return service.SaveShowsCtx(context.Background(), input)
+}
 
-	// Make an API call
-	res, err := service.client.Get(context.Background(), consts.EndpointSaveShows, params)
-	if err != nil {
-		return &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToSaveShows, Err: err}
+// SaveShowsCtx implements the ShowService's interface SaveShowsCtx method.
+func (service *DefaultShowService) SaveShowsCtx(ctx context.Context, input models.SaveShowsRequest) error {
+	// Check the granted scopes
+	if err := checkScopes(service.client, "ShowService.SaveShows"); err != nil {
+		return err
 	}
 
-	// Handle Spotify API error
-	if res.StatusCode != http.StatusOK {
-		return utils.ParseSpotifyError(res, utils.RegErrorType)
+	// Validate the input
+	if err := validateIds(input.Ids); err != nil {
+		return err
 	}
 
-	// Return the empty response
-	return nil
+	// Make an API call per chunk of IDs
+	return doChunked(input.Ids, consts.MaxShowIds, func(ids []spotifyid.ID) error {
+		// Add inputs to the query parameters
+		params := map[string]string{"ids": join(ids)}
+
+		// Make an API call and discard the response body
+		return doEmpty(ctx, service.client, http.MethodPut, consts.EndpointSaveShows, params, nil, consts.MsgFailedToSaveShows)
+	})
 }
 
 // RemoveSavedShows implements the ShowService's interface RemoveSavedShows method.
 func (service *DefaultShowService) RemoveSavedShows(input models.RemoveShowsRequest) error {
-	// Validate the input
-	if input.Ids == "" {
-		return &utils.AppError{Status: http.StatusBadRequest, Message: consts.MsgIdsRequired}
-	}
-
-	// Add inputs to the query parameters
-	params := map[string]string{"ids": input.Ids, "market": input.Market}
+	return service.RemoveSavedShowsCtx(context.Background(), input)
+}
 
-	// Make an API call
-	res, err := service.client.Get(context.Background(), consts.EndpointSaveShows, params)
-	if err != nil {
-		return &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToRemoveSavedShows, Err: err}
+// RemoveSavedShowsCtx implements the ShowService's interface RemoveSavedShowsCtx method.
+func (service *DefaultShowService) RemoveSavedShowsCtx(ctx context.Context, input models.RemoveShowsRequest) error {
+	// Check the granted scopes
+	if err := checkScopes(service.client, "ShowService.RemoveSavedShows"); err != nil {
+		return err
 	}
 
-	// Handle Spotify API error
-	if res.StatusCode != http.StatusOK {
-		return utils.ParseSpotifyError(res, utils.RegErrorType)
+	// Validate the input
+	if err := validateIds(input.Ids); err != nil {
+		return err
 	}
 
-	// Return the empty response
-	return nil
+	// Make an API call per chunk of IDs
+	return doChunked(input.Ids, consts.MaxShowIds, func(ids []spotifyid.ID) error {
+		// Add inputs to the query parameters
+		params := map[string]string{"ids": join(ids), "market": input.Market}
+
+		// Make an API call and discard the response body
+		return doEmpty(ctx, service.client, http.MethodDelete, consts.EndpointSaveShows, params, nil, consts.MsgFailedToRemoveSavedShows)
+	})
 }
 
 // CheckSavedShows implements the ShowService's interface CheckSavedShows method.
 func (service *DefaultShowService) CheckSavedShows(input models.CheckSavedShowsRequest) (*models.CheckSavedShows, error) {
-	// Validate the input
-	if input.Ids == "" {
-		return nil, &utils.AppError{Status: http.StatusBadRequest, Message: consts.MsgIdsRequired}
-	}
-
-	// Add inputs to the query parameters
-	params := map[string]string{"ids": input.Ids}
-
-	// Make an API call
-	res, err := service.client.Get(context.Background(), consts.EndpointCheckSavedShows, params)
-	if err != nil {
-		return nil, &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToCheckSavedShows, Err: err}
-	}
-
-	// Handle Spotify API error
-	if res.StatusCode != http.StatusOK {
-		return nil, utils.ParseSpotifyError(res, utils.RegErrorType)
-	}
+	return service.CheckSavedShowsCtx(context.Background(), input)
+}
 
-	// Read the response body
-	data, err := io.ReadAll(res.Body)
-	if err != nil {
+// CheckSavedShowsCtx implements the ShowService's interface CheckSavedShowsCtx method.
+func (service *DefaultShowService) CheckSavedShowsCtx(ctx context.Context, input models.CheckSavedShowsRequest) (*models.CheckSavedShows, error) {
+	// Check the granted scopes
+	if err := checkScopes(service.client, "ShowService.CheckSavedShows"); err != nil {
 		return nil, err
 	}
-	defer res.Body.Close()
 
-	// Unmarshal the response data into CheckSavedShows struct
-	var checkSavedShows models.CheckSavedShows
-	if err := json.Unmarshal(data, &checkSavedShows); err != nil {
+	// Validate the input
+	if err := validateIds(input.Ids); err != nil {
 		return nil, err
 	}
 
-	// Return the CheckSavedShows
-	return &checkSavedShows, nil
+	// Make an API call per chunk of IDs and merge the responses
+	return getChunked(input.Ids, consts.MaxShowIds,
+		func(ids []spotifyid.ID) (*models.CheckSavedShows, error) {
+			// Add inputs to the query parameters
+			params := map[string]string{"ids": join(ids)}
+
+			// Make an API call and decode the response data into CheckSavedShows struct
+			return doJSON[models.CheckSavedShows](ctx, service.client, http.MethodGet, consts.EndpointCheckSavedShows, params, nil, consts.MsgFailedToCheckSavedShows)
+		},
+		func(result, chunk *models.CheckSavedShows) {
+			*result = append(*result, *chunk...)
+		},
+	)
 }
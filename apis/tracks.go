@@ -2,14 +2,14 @@ package apis
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"io"
+	"iter"
 	"net/http"
 	"strconv"
 
 	"github.com/alicse3/gospotify/consts"
 	"github.com/alicse3/gospotify/models"
+	"github.com/alicse3/gospotify/spotifyid"
 	"github.com/alicse3/gospotify/utils"
 )
 
@@ -17,40 +17,69 @@ import (
 type TrackService interface {
 	// Get Spotify catalog information for a single track identified by its unique Spotify ID.
 	GetTrack(input models.GetTrackRequest) (*models.Track, error)
+	// GetTrackCtx is like GetTrack but carries the given context through to the API call.
+	GetTrackCtx(ctx context.Context, input models.GetTrackRequest) (*models.Track, error)
 
 	// Get Spotify catalog information for multiple tracks based on their Spotify IDs.
 	GetTracks(input models.GetTracksRequest) (*models.Tracks, error)
+	// GetTracksCtx is like GetTracks but carries the given context through to the API call.
+	GetTracksCtx(ctx context.Context, input models.GetTracksRequest) (*models.Tracks, error)
+
+	// GetTracksBatch gets any number of tracks, splitting the IDs into chunks of the maximum size of GetTracks which are fetched concurrently,
+	// at most concurrency chunks at a time, 0 means a default of 4. The items are in the order of the IDs, nil for the unknown ones.
+	// The failed chunks are reported in the Failures of the result, along with an error joining theirs.
+	GetTracksBatch(ctx context.Context, input models.GetTracksRequest, concurrency int) (*BatchResult[models.Track], error)
 
 	// Get a list of the songs saved in the current Spotify user's 'Your Music' library.
 	// Authorization scopes: user-library-read
 	GetSavedTracks(input models.GetSavedTracksRequest) (*models.SavedTracks, error)
+	// GetSavedTracksCtx is like GetSavedTracks but carries the given context through to the API call.
+	GetSavedTracksCtx(ctx context.Context, input models.GetSavedTracksRequest) (*models.SavedTracks, error)
+
+	// AllSavedTracks yields the saved tracks one by one, walking all the pages of GetSavedTracks starting at input.Offset.
+	// At most maxItems items are yielded, 0 means there is no cap.
+	AllSavedTracks(ctx context.Context, input models.GetSavedTracksRequest, maxItems int) iter.Seq2[models.SavedTrack, error]
 
 	// Save one or more tracks to the current user's 'Your Music' library.
 	// Authorization scopes: user-library-modify
 	SaveTracks(input models.SaveTracksRequest) error
+	// SaveTracksCtx is like SaveTracks but carries the given context through to the API call.
+	SaveTracksCtx(ctx context.Context, input models.SaveTracksRequest) error
 
 	// Remove one or more tracks from the current user's 'Your Music' library.
 	// Authorization scopes: user-library-modify
 	RemoveSavedTracks(input models.RemoveTracksRequest) error
+	// RemoveSavedTracksCtx is like RemoveSavedTracks but carries the given context through to the API call.
+	RemoveSavedTracksCtx(ctx context.Context, input models.RemoveTracksRequest) error
 
 	// Check if one or more tracks is already saved in the current Spotify user's 'Your Music' library.
 	// Authorization scopes: user-library-read
 	CheckSavedTracks(input models.CheckSavedTracksRequest) (*models.CheckSavedTracks, error)
+	// CheckSavedTracksCtx is like CheckSavedTracks but carries the given context through to the API call.
+	CheckSavedTracksCtx(ctx context.Context, input models.CheckSavedTracksRequest) (*models.CheckSavedTracks, error)
 
 	// Get audio features for multiple tracks based on their Spotify IDs.
 	CheckSeveralTracksAudioFeatures(input models.GetSeveralTracksAudioFeaturesRequest) (*models.SeveralTracksAudioFeatures, error)
+	// CheckSeveralTracksAudioFeaturesCtx is like CheckSeveralTracksAudioFeatures but carries the given context through to the API call.
+	CheckSeveralTracksAudioFeaturesCtx(ctx context.Context, input models.GetSeveralTracksAudioFeaturesRequest) (*models.SeveralTracksAudioFeatures, error)
 
 	// Get audio feature information for a single track identified by its unique Spotify ID.
 	CheckTracksAudioFeatures(input models.GetTracksAudioFeaturesRequest) (*models.TracksAudioFeatures, error)
+	// CheckTracksAudioFeaturesCtx is like CheckTracksAudioFeatures but carries the given context through to the API call.
+	CheckTracksAudioFeaturesCtx(ctx context.Context, input models.GetTracksAudioFeaturesRequest) (*models.TracksAudioFeatures, error)
 
 	// Get a low-level audio analysis for a track in the Spotify catalog.
 	// The audio analysis describes the track’s structure and musical content, including rhythm, pitch, and timbre.
 	CheckTracksAudioAnalysis(input models.GetTracksAudioAnalysisRequest) (*models.TracksAudioAnalysis, error)
+	// CheckTracksAudioAnalysisCtx is like CheckTracksAudioAnalysis but carries the given context through to the API call.
+	CheckTracksAudioAnalysisCtx(ctx context.Context, input models.GetTracksAudioAnalysisRequest) (*models.TracksAudioAnalysis, error)
 
 	// Recommendations are generated based on the available information for a given seed entity and matched against similar artists and tracks.
 	// If there is sufficient information about the provided seeds, a list of tracks will be returned together with pool size details.
 	// For artists and tracks that are very new or obscure there might not be enough data to generate a list of tracks.
 	GetRecommendations(input models.GetRecommendationsRequest) (*models.GetRecommendations, error)
+	// GetRecommendationsCtx is like GetRecommendations but carries the given context through to the API call.
+	GetRecommendationsCtx(ctx context.Context, input models.GetRecommendationsRequest) (*models.GetRecommendations, error)
 }
 
 // DefaultTrackService is a struct that implements TrackService interface.
@@ -65,335 +94,274 @@ func NewDefaultTrackService(client *utils.HttpClient) *DefaultTrackService {
 
 // GetTrack implements the TrackService's interface GetTrack method.
 func (service *DefaultTrackService) GetTrack(input models.GetTrackRequest) (*models.Track, error) {
+	return service.GetTrackCtx(context.Background(), input)
+}
+
+// GetTrackCtx implements the TrackService's interface GetTrackCtx method.
+func (service *DefaultTrackService) GetTrackCtx(ctx context.Context, input models.GetTrackRequest) (*models.Track, error) {
 	// Validate the input
 	if input.Id == "" {
-		return nil, &utils.AppError{Status: http.StatusBadRequest, Message: consts.MsgIdRequired}
+		return nil, utils.NewError(http.StatusBadRequest, consts.MsgIdRequired, nil)
 	}
 
 	// Substitute id in the endpoint
 	endpoint := fmt.Sprintf(consts.EndpointTrack, input.Id)
 
 	// Add inputs to the query parameters
-	params := map[string]string{"id": input.Id, "market": input.Market}
-
-	// Make an API call
-	res, err := service.client.Get(context.Background(), endpoint, params)
-	if err != nil {
-		return nil, &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToGetTrack, Err: err}
-	}
-
-	// Handle Spotify API error
-	if res.StatusCode != http.StatusOK {
-		return nil, utils.ParseSpotifyError(res, utils.RegErrorType)
-	}
+	params := map[string]string{"id": string(input.Id), "market": input.Market}
 
-	// Read the response body
-	data, err := io.ReadAll(res.Body)
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
-
-	// Unmarshal the response data into Track struct
-	var track models.Track
-	if err := json.Unmarshal(data, &track); err != nil {
-		return nil, err
-	}
-
-	// Return the Track
-	return &track, nil
+	// Make an API call and decode the response data into Track struct
+	return doJSON[models.Track](ctx, service.client, http.MethodGet, endpoint, params, nil, consts.MsgFailedToGetTrack)
 }
 
 // GetTracks implements the TrackService's interface GetTracks method.
 func (service *DefaultTrackService) GetTracks(input models.GetTracksRequest) (*models.Tracks, error) {
+	return service.GetTracksCtx(context.Background(), input)
+}
+
+// GetTracksCtx implements the TrackService's interface GetTracksCtx method.
+func (service *DefaultTrackService) GetTracksCtx(ctx context.Context, input models.GetTracksRequest) (*models.Tracks, error) {
 	// Validate the input
-	if input.Ids == "" {
-		return nil, &utils.AppError{Status: http.StatusBadRequest, Message: consts.MsgIdsRequired}
+	if err := validateIds(input.Ids); err != nil {
+		return nil, err
 	}
 
-	// Add inputs to the query parameters
-	params := map[string]string{"ids": input.Ids, "market": input.Market}
+	// Make an API call per chunk of IDs and merge the responses
+	return getChunked(input.Ids, consts.MaxTrackIds,
+		func(ids []spotifyid.ID) (*models.Tracks, error) {
+			// Add inputs to the query parameters
+			params := map[string]string{"ids": join(ids), "market": input.Market}
 
-	// Make an API call
-	res, err := service.client.Get(context.Background(), consts.EndpointTracks, params)
-	if err != nil {
-		return nil, &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToGetTracks, Err: err}
-	}
-
-	// Handle Spotify API error
-	if res.StatusCode != http.StatusOK {
-		return nil, utils.ParseSpotifyError(res, utils.RegErrorType)
-	}
+			// Make an API call and decode the response data into Tracks struct
+			return doJSON[models.Tracks](ctx, service.client, http.MethodGet, consts.EndpointTracks, params, nil, consts.MsgFailedToGetTracks)
+		},
+		func(result, chunk *models.Tracks) {
+			result.Tracks = append(result.Tracks, chunk.Tracks...)
+		},
+	)
+}
 
-	// Read the response body
-	data, err := io.ReadAll(res.Body)
-	if err != nil {
+// GetTracksBatch implements the TrackService's interface GetTracksBatch method.
+func (service *DefaultTrackService) GetTracksBatch(ctx context.Context, input models.GetTracksRequest, concurrency int) (*BatchResult[models.Track], error) {
+	// Validate the input
+	if err := validateIds(input.Ids); err != nil {
 		return nil, err
 	}
-	defer res.Body.Close()
 
-	// Unmarshal the response data into Tracks struct
-	var tracks models.Tracks
-	if err := json.Unmarshal(data, &tracks); err != nil {
-		return nil, err
-	}
+	// Fetch the chunks of IDs concurrently
+	fetch := batchFetcher[models.Track](service.client, consts.EndpointTracks, "tracks", map[string]string{"market": input.Market}, consts.MsgFailedToGetTracks)
+	result := FetchBatch(ctx, input.Ids, consts.MaxTrackIds, concurrency, fetch)
 
-	// Return the Tracks
-	return &tracks, nil
+	return result, result.Err()
 }
 
 // GetSavedTracks implements the TrackService's interface GetSavedTracks method.
 func (service *DefaultTrackService) GetSavedTracks(input models.GetSavedTracksRequest) (*models.SavedTracks, error) {
-	// Add inputs to the query parameters
-	params := map[string]string{"market": input.Market, "limit": strconv.Itoa(input.Limit), "offset": strconv.Itoa(input.Offset)}
-
-	// Make an API call
-	res, err := service.client.Get(context.Background(), consts.EndpointSaveTracks, params)
-	if err != nil {
-		return nil, &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToGetSavedTracks, Err: err}
-	}
-
-	// Handle Spotify API error
-	if res.StatusCode != http.StatusOK {
-		return nil, utils.ParseSpotifyError(res, utils.RegErrorType)
-	}
+	return service.GetSavedTracksCtx(context.Background(), input)
+}
 
-	// Read the response body
-	data, err := io.ReadAll(res.Body)
-	if err != nil {
+// GetSavedTracksCtx implements the TrackService's interface GetSavedTracksCtx method.
+func (service *DefaultTrackService) GetSavedTracksCtx(ctx context.Context, input models.GetSavedTracksRequest) (*models.SavedTracks, error) {
+	// Check the granted scopes
+	if err := checkScopes(service.client, "TrackService.GetSavedTracks"); err != nil {
 		return nil, err
 	}
-	defer res.Body.Close()
 
-	// Unmarshal the response data into SavedTracks struct
-	var savedTracks models.SavedTracks
-	if err := json.Unmarshal(data, &savedTracks); err != nil {
-		return nil, err
-	}
+	// Add inputs to the query parameters
+	params := map[string]string{"market": input.Market, "limit": strconv.Itoa(input.Limit), "offset": strconv.Itoa(input.Offset)}
 
-	// Return the SavedTracks
-	return &savedTracks, nil
+	// Make an API call and decode the response data into SavedTracks struct
+	return doJSON[models.SavedTracks](ctx, service.client, http.MethodGet, consts.EndpointSaveTracks, params, nil, consts.MsgFailedToGetSavedTracks)
+}
+
+// AllSavedTracks implements the TrackService's interface AllSavedTracks method.
+func (service *DefaultTrackService) AllSavedTracks(ctx context.Context, input models.GetSavedTracksRequest, maxItems int) iter.Seq2[models.SavedTrack, error] {
+	// Default to the maximum page size
+	input.Limit = pageLimit(input.Limit)
+
+	// Walk the pages, fetching each one from its offset
+	return Paginate(ctx, input.Offset, maxItems,
+		func(ctx context.Context, offset int) (*models.SavedTracks, error) {
+			input.Offset = offset
+			return service.GetSavedTracksCtx(ctx, input)
+		},
+		func(page *models.SavedTracks) ([]models.SavedTrack, string) {
+			return page.Items, page.Next
+		},
+	)
 }
 
 // SaveTracks implements the TrackService's interface SaveTracks method.
 func (service *DefaultTrackService) SaveTracks(input models.SaveTracksRequest) error {
-	// Validate the input
-	if input.Ids == "" {
-		return &utils.AppError{Status: http.StatusBadRequest, Message: consts.MsgIdsRequired}
-	}
-
-	// Add inputs to the query parameters
-	params := map[string]string{"ids": input.Ids}
+	return service.SaveTracksCtx(context.Background(), input)
+}
 
-	// Make an API call
-	res, err := service.client.Put(context.Background(), consts.EndpointSaveTracks, nil, params, input.Body)
-	if err != nil {
-		return &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToSaveTracks, Err: err}
+// SaveTracksCtx implements the TrackService's interface SaveTracksCtx method.
+func (service *DefaultTrackService) SaveTracksCtx(ctx context.Context, input models.SaveTracksRequest) error {
+	// Check the granted scopes
+	if err := checkScopes(service.client, "TrackService.SaveTracks"); err != nil {
+		return err
 	}
 
-	// Handle Spotify API error
-	if res.StatusCode != http.StatusOK {
-		return utils.ParseSpotifyError(res, utils.RegErrorType)
+	// Validate the input
+	if err := validateIds(input.Ids); err != nil {
+		return err
 	}
 
-	// Return the empty response
-	return nil
+	// Make an API call per chunk of IDs
+	return doChunked(input.Ids, consts.MaxTrackIds, func(ids []spotifyid.ID) error {
+		// Add inputs to the query parameters
+		params := map[string]string{"ids": join(ids)}
+
+		// Make an API call and discard the response body
+		return doEmpty(ctx, service.client, http.MethodPut, consts.EndpointSaveTracks, params, nil, consts.MsgFailedToSaveTracks)
+	})
 }
 
 // RemoveSavedTracks implements the TrackService's interface RemoveSavedTracks method.
 func (service *DefaultTrackService) RemoveSavedTracks(input models.RemoveTracksRequest) error {
-	// Validate the input
-	if input.Ids == "" {
-		return &utils.AppError{Status: http.StatusBadRequest, Message: consts.MsgIdsRequired}
-	}
-
-	// Add inputs to the query parameters
-	params := map[string]string{"ids": input.Ids}
+	return service.RemoveSavedTracksCtx(context.Background(), input)
+}
 
-	// Make an API call
-	res, err := service.client.Delete(context.Background(), consts.EndpointSaveTracks, nil, params, input.Body)
-	if err != nil {
-		return &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToRemoveSavedTracks, Err: err}
+// RemoveSavedTracksCtx implements the TrackService's interface RemoveSavedTracksCtx method.
+func (service *DefaultTrackService) RemoveSavedTracksCtx(ctx context.Context, input models.RemoveTracksRequest) error {
+	// Check the granted scopes
+	if err := checkScopes(service.client, "TrackService.RemoveSavedTracks"); err != nil {
+		return err
 	}
 
-	// Handle Spotify API error
-	if res.StatusCode != http.StatusOK {
-		return utils.ParseSpotifyError(res, utils.RegErrorType)
+	// Validate the input
+	if err := validateIds(input.Ids); err != nil {
+		return err
 	}
 
-	// Return the empty response
-	return nil
+	// Make an API call per chunk of IDs
+	return doChunked(input.Ids, consts.MaxTrackIds, func(ids []spotifyid.ID) error {
+		// Add inputs to the query parameters
+		params := map[string]string{"ids": join(ids)}
+
+		// Make an API call and discard the response body
+		return doEmpty(ctx, service.client, http.MethodDelete, consts.EndpointSaveTracks, params, nil, consts.MsgFailedToRemoveSavedTracks)
+	})
 }
 
 // CheckSavedTracks implements the TrackService's interface CheckSavedTracks method.
 func (service *DefaultTrackService) CheckSavedTracks(input models.CheckSavedTracksRequest) (*models.CheckSavedTracks, error) {
-	// Validate the input
-	if input.Ids == "" {
-		return nil, &utils.AppError{Status: http.StatusBadRequest, Message: consts.MsgIdsRequired}
-	}
-
-	// Add inputs to the query parameters
-	params := map[string]string{"ids": input.Ids}
-
-	// Make an API call
-	res, err := service.client.Get(context.Background(), consts.EndpointCheckSavedTracks, params)
-	if err != nil {
-		return nil, &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToCheckSavedTracks, Err: err}
-	}
-
-	// Handle Spotify API error
-	if res.StatusCode != http.StatusOK {
-		return nil, utils.ParseSpotifyError(res, utils.RegErrorType)
-	}
+	return service.CheckSavedTracksCtx(context.Background(), input)
+}
 
-	// Read the response body
-	data, err := io.ReadAll(res.Body)
-	if err != nil {
+// CheckSavedTracksCtx implements the TrackService's interface CheckSavedTracksCtx method.
+func (service *DefaultTrackService) CheckSavedTracksCtx(ctx context.Context, input models.CheckSavedTracksRequest) (*models.CheckSavedTracks, error) {
+	// Check the granted scopes
+	if err := checkScopes(service.client, "TrackService.CheckSavedTracks"); err != nil {
 		return nil, err
 	}
-	defer res.Body.Close()
 
-	// Unmarshal the response data into CheckSavedTracks struct
-	var checkSavedTracks models.CheckSavedTracks
-	if err := json.Unmarshal(data, &checkSavedTracks); err != nil {
+	// Validate the input
+	if err := validateIds(input.Ids); err != nil {
 		return nil, err
 	}
 
-	// Return the CheckSavedTracks
-	return &checkSavedTracks, nil
+	// Make an API call per chunk of IDs and merge the responses
+	return getChunked(input.Ids, consts.MaxTrackIds,
+		func(ids []spotifyid.ID) (*models.CheckSavedTracks, error) {
+			// Add inputs to the query parameters
+			params := map[string]string{"ids": join(ids)}
+
+			// Make an API call and decode the response data into CheckSavedTracks struct
+			return doJSON[models.CheckSavedTracks](ctx, service.client, http.MethodGet, consts.EndpointCheckSavedTracks, params, nil, consts.MsgFailedToCheckSavedTracks)
+		},
+		func(result, chunk *models.CheckSavedTracks) {
+			*result = append(*result, *chunk...)
+		},
+	)
 }
 
 // CheckSeveralTracksAudioFeatures implements the TrackService's interface CheckSeveralTracksAudioFeatures method.
 func (service *DefaultTrackService) CheckSeveralTracksAudioFeatures(input models.GetSeveralTracksAudioFeaturesRequest) (*models.SeveralTracksAudioFeatures, error) {
-	// Validate the input
-	if input.Ids == "" {
-		return nil, &utils.AppError{Status: http.StatusBadRequest, Message: consts.MsgIdsRequired}
-	}
-
-	// Add inputs to the query parameters
-	params := map[string]string{"ids": input.Ids}
-
-	// Make an API call
-	res, err := service.client.Get(context.Background(), consts.EndpointSeveralTracksAudioFeatures, params)
-	if err != nil {
-		return nil, &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToGetSeveralTracksAudioFeatures, Err: err}
-	}
-
-	// Handle Spotify API error
-	if res.StatusCode != http.StatusOK {
-		return nil, utils.ParseSpotifyError(res, utils.RegErrorType)
-	}
+	return service.CheckSeveralTracksAudioFeaturesCtx(context.Background(), input)
+}
 
-	// Read the response body
-	data, err := io.ReadAll(res.Body)
-	if err != nil {
+// CheckSeveralTracksAudioFeaturesCtx implements the TrackService's interface CheckSeveralTracksAudioFeaturesCtx method.
+func (service *DefaultTrackService) CheckSeveralTracksAudioFeaturesCtx(ctx context.Context, input models.GetSeveralTracksAudioFeaturesRequest) (*models.SeveralTracksAudioFeatures, error) {
+	// Validate the input
+	if err := validateIds(input.Ids); err != nil {
 		return nil, err
 	}
-	defer res.Body.Close()
 
-	// Unmarshal the response data into SeveralTracksAudioFeatures struct
-	var severalTracksAudioFeatures models.SeveralTracksAudioFeatures
-	if err := json.Unmarshal(data, &severalTracksAudioFeatures); err != nil {
-		return nil, err
-	}
+	// Make an API call per chunk of IDs and merge the responses
+	return getChunked(input.Ids, consts.MaxAudioFeaturesIds,
+		func(ids []spotifyid.ID) (*models.SeveralTracksAudioFeatures, error) {
+			// Add inputs to the query parameters
+			params := map[string]string{"ids": join(ids)}
 
-	// Return the SeveralTracksAudioFeatures
-	return &severalTracksAudioFeatures, nil
+			// Make an API call and decode the response data into SeveralTracksAudioFeatures struct
+			return doJSON[models.SeveralTracksAudioFeatures](ctx, service.client, http.MethodGet, consts.EndpointSeveralTracksAudioFeatures, params, nil, consts.MsgFailedToGetSeveralTracksAudioFeatures)
+		},
+		func(result, chunk *models.SeveralTracksAudioFeatures) {
+			result.AudioFeatures = append(result.AudioFeatures, chunk.AudioFeatures...)
+		},
+	)
 }
 
 // CheckTracksAudioFeatures implements the TrackService's interface CheckTracksAudioFeatures method.
 func (service *DefaultTrackService) CheckTracksAudioFeatures(input models.GetTracksAudioFeaturesRequest) (*models.TracksAudioFeatures, error) {
+	return service.CheckTracksAudioFeaturesCtx(context.Background(), input)
+}
+
+// CheckTracksAudioFeaturesCtx implements the TrackService's interface CheckTracksAudioFeaturesCtx method.
+func (service *DefaultTrackService) CheckTracksAudioFeaturesCtx(ctx context.Context, input models.GetTracksAudioFeaturesRequest) (*models.TracksAudioFeatures, error) {
 	// Validate the input
 	if input.Id == "" {
-		return nil, &utils.AppError{Status: http.StatusBadRequest, Message: consts.MsgIdRequired}
+		return nil, utils.NewError(http.StatusBadRequest, consts.MsgIdRequired, nil)
 	}
 
 	// Substitute id in the endpoint
 	endpoint := fmt.Sprintf(consts.EndpointTracksTracksAudioFeatures, input.Id)
 
 	// Add inputs to the query parameters
-	params := map[string]string{"id": input.Id}
-
-	// Make an API call
-	res, err := service.client.Get(context.Background(), endpoint, params)
-	if err != nil {
-		return nil, &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToGetTracksAudioFeatures, Err: err}
-	}
-
-	// Handle Spotify API error
-	if res.StatusCode != http.StatusOK {
-		return nil, utils.ParseSpotifyError(res, utils.RegErrorType)
-	}
-
-	// Read the response body
-	data, err := io.ReadAll(res.Body)
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
-
-	// Unmarshal the response data into TracksAudioFeatures struct
-	var tracksAudioFeatures models.TracksAudioFeatures
-	if err := json.Unmarshal(data, &tracksAudioFeatures); err != nil {
-		return nil, err
-	}
+	params := map[string]string{"id": string(input.Id)}
 
-	// Return the TracksAudioFeatures
-	return &tracksAudioFeatures, nil
+	// Make an API call and decode the response data into TracksAudioFeatures struct
+	return doJSON[models.TracksAudioFeatures](ctx, service.client, http.MethodGet, endpoint, params, nil, consts.MsgFailedToGetTracksAudioFeatures)
 }
 
 // CheckTracksAudioAnalysis implements the TrackService's interface CheckTracksAudioAnalysis method.
 func (service *DefaultTrackService) CheckTracksAudioAnalysis(input models.GetTracksAudioAnalysisRequest) (*models.TracksAudioAnalysis, error) {
+	return service.CheckTracksAudioAnalysisCtx(context.Background(), input)
+}
+
+// CheckTracksAudioAnalysisCtx implements the TrackService's interface CheckTracksAudioAnalysisCtx method.
+func (service *DefaultTrackService) CheckTracksAudioAnalysisCtx(ctx context.Context, input models.GetTracksAudioAnalysisRequest) (*models.TracksAudioAnalysis, error) {
 	// Validate the input
 	if input.Id == "" {
-		return nil, &utils.AppError{Status: http.StatusBadRequest, Message: consts.MsgIdRequired}
+		return nil, utils.NewError(http.StatusBadRequest, consts.MsgIdRequired, nil)
 	}
 
 	// Substitute id in the endpoint
 	endpoint := fmt.Sprintf(consts.EndpointTracksAudioAnalysis, input.Id)
 
 	// Add inputs to the query parameters
-	params := map[string]string{"id": input.Id}
-
-	// Make an API call
-	res, err := service.client.Get(context.Background(), endpoint, params)
-	if err != nil {
-		return nil, &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToGetTracksAudioAnalysis, Err: err}
-	}
-
-	// Handle Spotify API error
-	if res.StatusCode != http.StatusOK {
-		return nil, utils.ParseSpotifyError(res, utils.RegErrorType)
-	}
-
-	// Read the response body
-	data, err := io.ReadAll(res.Body)
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
-
-	// Unmarshal the response data into TracksAudioAnalysis struct
-	var tracksAudioAnalysis models.TracksAudioAnalysis
-	if err := json.Unmarshal(data, &tracksAudioAnalysis); err != nil {
-		return nil, err
-	}
+	params := map[string]string{"id": string(input.Id)}
 
-	// Return the TracksAudioAnalysis
-	return &tracksAudioAnalysis, nil
+	// Make an API call and decode the response data into TracksAudioAnalysis struct
+	return doJSON[models.TracksAudioAnalysis](ctx, service.client, http.MethodGet, endpoint, params, nil, consts.MsgFailedToGetTracksAudioAnalysis)
 }
 
 // GetRecommendations implements the TrackService's interface GetRecommendations method.
 func (service *DefaultTrackService) GetRecommendations(input models.GetRecommendationsRequest) (*models.GetRecommendations, error) {
+	return service.GetRecommendationsCtx(context.Background(), input)
+}
+
+// GetRecommendationsCtx implements the TrackService's interface GetRecommendationsCtx method.
+func (service *DefaultTrackService) GetRecommendationsCtx(ctx context.Context, input models.GetRecommendationsRequest) (*models.GetRecommendations, error) {
 	// Validate the input
-	if input.SeedArtists == "" {
-		return nil, &utils.AppError{Status: http.StatusBadRequest, Message: consts.MsgSeedArtistsRequired}
+	seeds := len(input.SeedArtists) + len(input.SeedGenres) + len(input.SeedTracks)
+	if seeds == 0 {
+		return nil, utils.NewError(http.StatusBadRequest, consts.MsgSeedsRequired, nil)
 	}
-	if input.SeedGenres == "" {
-		return nil, &utils.AppError{Status: http.StatusBadRequest, Message: consts.MsgSeedGenresRequired}
-	}
-	if input.SeedTracks == "" {
-		return nil, &utils.AppError{Status: http.StatusBadRequest, Message: consts.MsgSeedTracksRequired}
+	if err := validateMaxCount(seeds, consts.MaxRecommendationSeeds, consts.MsgTooManySeeds); err != nil {
+		return nil, err
 	}
 
 	// Add inputs to the query parameters
@@ -404,9 +372,9 @@ func (service *DefaultTrackService) GetRecommendations(input models.GetRecommend
 		"limit":  strconv.Itoa(input.Limit),
 		"market": input.Market,
 
-		"seed_artists": input.SeedArtists,
-		"seed_genres":  input.SeedGenres,
-		"seed_tracks":  input.SeedTracks,
+		"seed_artists": join(input.SeedArtists),
+		"seed_genres":  join(input.SeedGenres),
+		"seed_tracks":  join(input.SeedTracks),
 
 		"min_acousticness":    strconv.FormatFloat(input.MinAcousticness, format, precision, bitSize),
 		"max_acousticness":    strconv.FormatFloat(input.MaxAcousticness, format, precision, bitSize),
@@ -465,30 +433,6 @@ func (service *DefaultTrackService) GetRecommendations(input models.GetRecommend
 		"target_valence": strconv.FormatFloat(input.TargetValence, format, precision, bitSize),
 	}
 
-	// Make an API call
-	res, err := service.client.Get(context.Background(), consts.EndpointRecommendations, params)
-	if err != nil {
-		return nil, &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToGetRecommendations, Err: err}
-	}
-
-	// Handle Spotify API error
-	if res.StatusCode != http.StatusOK {
-		return nil, utils.ParseSpotifyError(res, utils.RegErrorType)
-	}
-
-	// Read the response body
-	data, err := io.ReadAll(res.Body)
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
-
-	// Unmarshal the response data into GetRecommendations struct
-	var getRecommendations models.GetRecommendations
-	if err := json.Unmarshal(data, &getRecommendations); err != nil {
-		return nil, err
-	}
-
-	// Return the GetRecommendations
-	return &getRecommendations, nil
+	// Make an API call and decode the response data into GetRecommendations struct
+	return doJSON[models.GetRecommendations](ctx, service.client, http.MethodGet, consts.EndpointRecommendations, params, nil, consts.MsgFailedToGetRecommendations)
 }
@@ -2,14 +2,14 @@ package apis
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"io"
+	"iter"
 	"net/http"
 	"strconv"
 
 	"github.com/alicse3/gospotify/consts"
 	"github.com/alicse3/gospotify/models"
+	"github.com/alicse3/gospotify/spotifyid"
 	"github.com/alicse3/gospotify/utils"
 )
 
@@ -18,40 +18,68 @@ type UserService interface {
 	// Get detailed profile information about the current user (including the current user's username).
 	// Authorization scopes: user-read-private, user-read-email
 	GetCurrentUserProfile() (*models.User, error)
+	// GetCurrentUserProfileCtx is like GetCurrentUserProfile but carries the given context through to the API call.
+	GetCurrentUserProfileCtx(ctx context.Context) (*models.User, error)
 
 	// Get the current user's top artists or tracks based on calculated affinity.
 	// Authorization scopes: user-top-read
 	GetUserTopItems(input models.GetUsersTopItemsRequest) (*models.UserTopItems, error)
+	// GetUserTopItemsCtx is like GetUserTopItems but carries the given context through to the API call.
+	GetUserTopItemsCtx(ctx context.Context, input models.GetUsersTopItemsRequest) (*models.UserTopItems, error)
+
+	// AllUserTopItems yields the current user's top artists or tracks one by one, walking all the pages of GetUserTopItems starting at input.Offset.
+	// At most maxItems items are yielded, 0 means there is no cap.
+	AllUserTopItems(ctx context.Context, input models.GetUsersTopItemsRequest, maxItems int) iter.Seq2[models.TopItem, error]
 
 	// Get public profile information about a Spotify user.
 	GetUsersProfile(input models.GetUsersProfileRequest) (*models.UserProfile, error)
+	// GetUsersProfileCtx is like GetUsersProfile but carries the given context through to the API call.
+	GetUsersProfileCtx(ctx context.Context, input models.GetUsersProfileRequest) (*models.UserProfile, error)
 
 	// Add the current user as a follower of a playlist.
 	// Authorization scopes: playlist-modify-public, playlist-modify-private
 	FollowPlaylist(input models.FollowPlaylistRequest) error
+	// FollowPlaylistCtx is like FollowPlaylist but carries the given context through to the API call.
+	FollowPlaylistCtx(ctx context.Context, input models.FollowPlaylistRequest) error
 
 	// Remove the current user as a follower of a playlist.
 	// Authorization scopes: playlist-modify-public, playlist-modify-private
 	UnfollowPlaylist(input models.UnfollowPlaylistRequest) error
+	// UnfollowPlaylistCtx is like UnfollowPlaylist but carries the given context through to the API call.
+	UnfollowPlaylistCtx(ctx context.Context, input models.UnfollowPlaylistRequest) error
 
 	// Get the current user's followed artists.
 	// Authorization scopes: user-follow-read
 	GetFollowedArtists(input models.GetFollowedArtistsRequest) (*models.FollowedArtists, error)
+	// GetFollowedArtistsCtx is like GetFollowedArtists but carries the given context through to the API call.
+	GetFollowedArtistsCtx(ctx context.Context, input models.GetFollowedArtistsRequest) (*models.FollowedArtists, error)
+
+	// AllFollowedArtists yields the artists followed by the current user one by one, walking all the pages of GetFollowedArtists forward from input.After.
+	// At most maxItems items are yielded, 0 means there is no cap.
+	AllFollowedArtists(ctx context.Context, input models.GetFollowedArtistsRequest, maxItems int) iter.Seq2[models.Artist, error]
 
 	// Add the current user as a follower of one or more artists or other Spotify users.
 	// Authorization scopes: user-follow-modify
 	FollowArtistsOrUsers(input models.FollowArtistsOrUsersRequest) error
+	// FollowArtistsOrUsersCtx is like FollowArtistsOrUsers but carries the given context through to the API call.
+	FollowArtistsOrUsersCtx(ctx context.Context, input models.FollowArtistsOrUsersRequest) error
 
 	// Remove the current user as a follower of one or more artists or other Spotify users.
 	// Authorization scopes: user-follow-modify
 	UnfollowArtistsOrUsers(input models.UnfollowArtistsOrUsersRequest) error
+	// UnfollowArtistsOrUsersCtx is like UnfollowArtistsOrUsers but carries the given context through to the API call.
+	UnfollowArtistsOrUsersCtx(ctx context.Context, input models.UnfollowArtistsOrUsersRequest) error
 
 	// Check to see if the current user is following one or more artists or other Spotify users.
 	// Authorization scopes: user-follow-read
 	CheckUserFollowsArtistsOrUsers(input models.UserFollowsArtistsOrUsersRequest) (*models.CheckUserFollowsArtistsOrUsers, error)
+	// CheckUserFollowsArtistsOrUsersCtx is like CheckUserFollowsArtistsOrUsers but carries the given context through to the API call.
+	CheckUserFollowsArtistsOrUsersCtx(ctx context.Context, input models.UserFollowsArtistsOrUsersRequest) (*models.CheckUserFollowsArtistsOrUsers, error)
 
 	// Check to see if the current user is following a specified playlist.
 	CheckCurrentUserFollowsPlaylist(input models.CurrentUserFollowsPlaylistRequest) (*models.CheckCurrentUserFollowsPlaylist, error)
+	// CheckCurrentUserFollowsPlaylistCtx is like CheckCurrentUserFollowsPlaylist but carries the given context through to the API call.
+	CheckCurrentUserFollowsPlaylistCtx(ctx context.Context, input models.CurrentUserFollowsPlaylistRequest) (*models.CheckCurrentUserFollowsPlaylist, error)
 }
 
 // DefaultUserService is a struct that implements UserService interface.
@@ -66,39 +94,30 @@ func NewDefultUserService(client *utils.HttpClient) *DefaultUserService {
 
 // GetCurrentUserProfile implements the UserService's interface GetCurrentUserProfile method.
 func (service *DefaultUserService) GetCurrentUserProfile() (*models.User, error) {
-	// Make a Get call
-	res, err := service.client.Get(context.Background(), consts.EndpointMe, nil)
-	if err != nil {
-		return nil, &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToGetCurrentUserProfile, Err: err}
-	}
+	return service.GetCurrentUserProfileCtx(context.Background())
+}
 
-	// Handle Spotify API error
-	if res.StatusCode != http.StatusOK {
-		return nil, utils.ParseSpotifyError(res, utils.RegErrorType)
-	}
+// GetCurrentUserProfileCtx implements the UserService's interface GetCurrentUserProfileCtx method.
+func (service *DefaultUserService) GetCurrentUserProfileCtx(ctx context.Context) (*models.User, error) {
+	// Make an API call and decode the response data into User struct
+	return doJSON[models.User](ctx, service.client, http.MethodGet, consts.EndpointMe, nil, nil, consts.MsgFailedToGetCurrentUserProfile)
+}
 
-	// Read the response body
-	data, err := io.ReadAll(res.Body)
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
+// GetUserTopItems implements the UserService's interface GetUserTopItems method.
+func (service *DefaultUserService) GetUserTopItems(input models.GetUsersTopItemsRequest) (*models.UserTopItems, error) {
+	return service.GetUserTopItemsCtx(context.Background(), input)
+}
 
-	// Unmarshal the response data into User struct
-	var user models.User
-	if err := json.Unmarshal(data, &user); err != nil {
+// GetUserTopItemsCtx implements the UserService's interface GetUserTopItemsCtx method.
+func (service *DefaultUserService) GetUserTopItemsCtx(ctx context.Context, input models.GetUsersTopItemsRequest) (*models.UserTopItems, error) {
+	// Check the granted scopes
+	if err := checkScopes(service.client, "UserService.GetUserTopItems"); err != nil {
 		return nil, err
 	}
 
-	// Return the User
-	return &user, nil
-}
-
-// GetUserTopItems implements the UserService's interface GetUserTopItems method.
-func (service *DefaultUserService) GetUserTopItems(input models.GetUsersTopItemsRequest) (*models.UserTopItems, error) {
 	// Validate the input
 	if input.Type == "" {
-		return nil, &utils.AppError{Status: http.StatusBadRequest, Message: consts.MsgTypeRequired}
+		return nil, utils.NewError(http.StatusBadRequest, consts.MsgTypeRequired, nil)
 	}
 
 	// Substitute id in the endpoint
@@ -107,136 +126,118 @@ func (service *DefaultUserService) GetUserTopItems(input models.GetUsersTopItems
 	// Add inputs to the query parameters
 	params := map[string]string{"type": input.Type, "time_range": input.TimeRange, "limit": strconv.Itoa(input.Limit), "offset": strconv.Itoa(input.Offset)}
 
-	// Make an API call
-	res, err := service.client.Get(context.Background(), endpoint, params)
-	if err != nil {
-		return nil, &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToGetUserTopItems, Err: err}
-	}
-
-	// Handle Spotify API error
-	if res.StatusCode != http.StatusOK {
-		return nil, utils.ParseSpotifyError(res, utils.RegErrorType)
-	}
-
-	// Read the response body
-	data, err := io.ReadAll(res.Body)
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
-
-	// Unmarshal the response data into UserTopItems struct
-	var userTopItems models.UserTopItems
-	if err := json.Unmarshal(data, &userTopItems); err != nil {
-		return nil, err
-	}
+	// Make an API call and decode the response data into UserTopItems struct
+	return doJSON[models.UserTopItems](ctx, service.client, http.MethodGet, endpoint, params, nil, consts.MsgFailedToGetUserTopItems)
+}
 
-	// Return the UserTopItems
-	return &userTopItems, nil
+// AllUserTopItems implements the UserService's interface AllUserTopItems method.
+func (service *DefaultUserService) AllUserTopItems(ctx context.Context, input models.GetUsersTopItemsRequest, maxItems int) iter.Seq2[models.TopItem, error] {
+	// Default to the maximum page size
+	input.Limit = pageLimit(input.Limit)
+
+	// Walk the pages, fetching each one from its offset
+	return Paginate(ctx, input.Offset, maxItems,
+		func(ctx context.Context, offset int) (*models.UserTopItems, error) {
+			input.Offset = offset
+			return service.GetUserTopItemsCtx(ctx, input)
+		},
+		func(page *models.UserTopItems) ([]models.TopItem, string) {
+			return page.Items, page.Next
+		},
+	)
 }
 
 // GetUsersProfile implements the UserService's interface GetUsersProfile method.
 func (service *DefaultUserService) GetUsersProfile(input models.GetUsersProfileRequest) (*models.UserProfile, error) {
+	return service.GetUsersProfileCtx(context.Background(), input)
+}
+
+// GetUsersProfileCtx implements the UserService's interface GetUsersProfileCtx method.
+func (service *DefaultUserService) GetUsersProfileCtx(ctx context.Context, input models.GetUsersProfileRequest) (*models.UserProfile, error) {
 	// Validate the input
 	if input.UserId == "" {
-		return nil, &utils.AppError{Status: http.StatusBadRequest, Message: consts.MsgUserIdRequired}
+		return nil, utils.NewError(http.StatusBadRequest, consts.MsgUserIdRequired, nil)
 	}
 
 	// Substitute id in the endpoint
 	endpoint := fmt.Sprintf(consts.EndpointUserProfile, input.UserId)
 
 	// Add inputs to the query parameters
-	params := map[string]string{"user_id": input.UserId}
-
-	// Make an API call
-	res, err := service.client.Get(context.Background(), endpoint, params)
-	if err != nil {
-		return nil, &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToGetUserProfile, Err: err}
-	}
+	params := map[string]string{"user_id": string(input.UserId)}
 
-	// Handle Spotify API error
-	if res.StatusCode != http.StatusOK {
-		return nil, utils.ParseSpotifyError(res, utils.RegErrorType)
-	}
-
-	// Read the response body
-	data, err := io.ReadAll(res.Body)
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
-
-	// Unmarshal the response data into UserProfile struct
-	var userProfile models.UserProfile
-	if err := json.Unmarshal(data, &userProfile); err != nil {
-		return nil, err
-	}
-
-	// Return the UserProfile
-	return &userProfile, nil
+	// Make an API call and decode the response data into UserProfile struct
+	return doJSON[models.UserProfile](ctx, service.client, http.MethodGet, endpoint, params, nil, consts.MsgFailedToGetUserProfile)
 }
 
 // FollowPlaylist implements the UserService's interface FollowPlaylist method.
 func (service *DefaultUserService) FollowPlaylist(input models.FollowPlaylistRequest) error {
+	return service.FollowPlaylistCtx(context.Background(), input)
+}
+
+// FollowPlaylistCtx implements the UserService's interface FollowPlaylistCtx method.
+func (service *DefaultUserService) FollowPlaylistCtx(ctx context.Context, input models.FollowPlaylistRequest) error {
+	// Check the granted scopes
+	if err := checkScopes(service.client, "UserService.FollowPlaylist"); err != nil {
+		return err
+	}
+
 	// Validate the input
 	if input.PlaylistId == "" {
-		return &utils.AppError{Status: http.StatusBadRequest, Message: consts.MsgPlaylistIdRequired}
+		return utils.NewError(http.StatusBadRequest, consts.MsgPlaylistIdRequired, nil)
 	}
 
 	// Substitute id in the endpoint
 	endpoint := fmt.Sprintf(consts.EndpointFollowers, input.PlaylistId)
 
 	// Add inputs to the query parameters
-	params := map[string]string{"playlist_id": input.PlaylistId}
-
-	// Make an API call
-	res, err := service.client.Put(context.Background(), endpoint, nil, params, input.Body)
-	if err != nil {
-		return &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToFollowPlaylist, Err: err}
-	}
+	params := map[string]string{"playlist_id": string(input.PlaylistId)}
 
-	// Handle Spotify API error
-	if res.StatusCode != http.StatusOK {
-		return utils.ParseSpotifyError(res, utils.RegErrorType)
-	}
-
-	// Return the empty response
-	return nil
+	// Make an API call and discard the response body
+	return doEmpty(ctx, service.client, http.MethodPut, endpoint, params, input.Body, consts.MsgFailedToFollowPlaylist)
 }
 
 // UnfollowPlaylist implements the UserService's interface UnfollowPlaylist method.
 func (service *DefaultUserService) UnfollowPlaylist(input models.UnfollowPlaylistRequest) error {
+	return service.UnfollowPlaylistCtx(context.Background(), input)
+}
+
+// UnfollowPlaylistCtx implements the UserService's interface UnfollowPlaylistCtx method.
+func (service *DefaultUserService) UnfollowPlaylistCtx(ctx context.Context, input models.UnfollowPlaylistRequest) error {
+	// Check the granted scopes
+	if err := checkScopes(service.client, "UserService.UnfollowPlaylist"); err != nil {
+		return err
+	}
+
 	// Validate the input
 	if input.PlaylistId == "" {
-		return &utils.AppError{Status: http.StatusBadRequest, Message: consts.MsgPlaylistIdRequired}
+		return utils.NewError(http.StatusBadRequest, consts.MsgPlaylistIdRequired, nil)
 	}
 
 	// Substitute id in the endpoint
 	endpoint := fmt.Sprintf(consts.EndpointFollowers, input.PlaylistId)
 
 	// Add inputs to the query parameters
-	params := map[string]string{"playlist_id": input.PlaylistId}
-
-	// Make an API call
-	res, err := service.client.Delete(context.Background(), endpoint, nil, params, nil)
-	if err != nil {
-		return &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToUnfollowPlaylist, Err: err}
-	}
-
-	// Handle Spotify API error
-	if res.StatusCode != http.StatusOK {
-		return utils.ParseSpotifyError(res, utils.RegErrorType)
-	}
+	params := map[string]string{"playlist_id": string(input.PlaylistId)}
 
-	// Return the empty response
-	return nil
+	// Make an API call and discard the response body
+	return doEmpty(ctx, service.client, http.MethodDelete, endpoint, params, nil, consts.MsgFailedToUnfollowPlaylist)
 }
 
 // GetFollowedArtists implements the UserService's interface GetFollowedArtists method.
 func (service *DefaultUserService) GetFollowedArtists(input models.GetFollowedArtistsRequest) (*models.FollowedArtists, error) {
+	return service.GetFollowedArtistsCtx(context.Background(), input)
+}
+
+// GetFollowedArtistsCtx implements the UserService's interface GetFollowedArtistsCtx method.
+func (service *DefaultUserService) GetFollowedArtistsCtx(ctx context.Context, input models.GetFollowedArtistsRequest) (*models.FollowedArtists, error) {
+	// Check the granted scopes
+	if err := checkScopes(service.client, "UserService.GetFollowedArtists"); err != nil {
+		return nil, err
+	}
+
 	// Validate the input
 	if input.Type == "" {
-		return nil, &utils.AppError{Status: http.StatusBadRequest, Message: consts.MsgTypeRequired}
+		return nil, utils.NewError(http.StatusBadRequest, consts.MsgTypeRequired, nil)
 	}
 
 	// Add inputs to the query parameters
@@ -245,168 +246,146 @@ func (service *DefaultUserService) GetFollowedArtists(input models.GetFollowedAr
 		params["after"] = input.After
 	}
 
-	// Make an API call
-	res, err := service.client.Get(context.Background(), consts.EndpointFollowing, params)
-	if err != nil {
-		return nil, &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToGetFollowedArtists, Err: err}
-	}
-
-	// Handle Spotify API error
-	if res.StatusCode != http.StatusOK {
-		return nil, utils.ParseSpotifyError(res, utils.RegErrorType)
-	}
-
-	// Read the response body
-	data, err := io.ReadAll(res.Body)
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
-
-	// Unmarshal the response data into FollowedArtists struct
-	var followedArtists models.FollowedArtists
-	if err := json.Unmarshal(data, &followedArtists); err != nil {
-		return nil, err
-	}
+	// Make an API call and decode the response data into FollowedArtists struct
+	return doJSON[models.FollowedArtists](ctx, service.client, http.MethodGet, consts.EndpointFollowing, params, nil, consts.MsgFailedToGetFollowedArtists)
+}
 
-	// Return the FollowedArtists
-	return &followedArtists, nil
+// AllFollowedArtists implements the UserService's interface AllFollowedArtists method.
+func (service *DefaultUserService) AllFollowedArtists(ctx context.Context, input models.GetFollowedArtistsRequest, maxItems int) iter.Seq2[models.Artist, error] {
+	// Default to the maximum page size
+	input.Limit = pageLimit(input.Limit)
+
+	// Walk the pages, fetching each one from its cursor
+	return PaginateCursor(ctx, input.After, maxItems,
+		func(ctx context.Context, cursor string) (*models.FollowedArtists, error) {
+			input.After = cursor
+			return service.GetFollowedArtistsCtx(ctx, input)
+		},
+		func(page *models.FollowedArtists) ([]models.Artist, string) {
+			if page.Next == "" {
+				return page.Items, ""
+			}
+			return page.Items, page.Cursors.After
+		},
+	)
 }
 
 // FollowArtistsOrUsers implements the UserService's interface FollowArtistsOrUsers method.
 func (service *DefaultUserService) FollowArtistsOrUsers(input models.FollowArtistsOrUsersRequest) error {
+	return service.FollowArtistsOrUsersCtx(context.Background(), input)
+}
+
+// FollowArtistsOrUsersCtx implements the UserService's interface FollowArtistsOrUsersCtx method.
+func (service *DefaultUserService) FollowArtistsOrUsersCtx(ctx context.Context, input models.FollowArtistsOrUsersRequest) error {
+	// Check the granted scopes
+	if err := checkScopes(service.client, "UserService.FollowArtistsOrUsers"); err != nil {
+		return err
+	}
+
 	// Validate the input
 	if input.Type == "" {
-		return &utils.AppError{Status: http.StatusBadRequest, Message: consts.MsgTypeRequired}
+		return utils.NewError(http.StatusBadRequest, consts.MsgTypeRequired, nil)
 	}
-	if input.Ids == "" {
-		return &utils.AppError{Status: http.StatusBadRequest, Message: consts.MsgIdsRequired}
+	if err := validateIds(input.Ids); err != nil {
+		return err
 	}
 
-	// Add inputs to the query parameters
-	params := map[string]string{"type": input.Type, "ids": input.Ids}
-
-	// Make an API call
-	res, err := service.client.Put(context.Background(), consts.EndpointFollowing, nil, params, input.Body)
-	if err != nil {
-		return &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToFollowArtistsOrUsers, Err: err}
-	}
+	// Make an API call per chunk of IDs
+	return doChunked(input.Ids, consts.MaxFollowIds, func(ids []spotifyid.ID) error {
+		// Add inputs to the query parameters
+		params := map[string]string{"type": input.Type, "ids": join(ids)}
 
-	// Handle Spotify API error
-	if res.StatusCode != http.StatusOK {
-		return utils.ParseSpotifyError(res, utils.RegErrorType)
-	}
-
-	// Return the empty response
-	return nil
+		// Make an API call and discard the response body
+		return doEmpty(ctx, service.client, http.MethodPut, consts.EndpointFollowing, params, nil, consts.MsgFailedToFollowArtistsOrUsers)
+	})
 }
 
 // UnfollowArtistsOrUsers implements the UserService's interface UnfollowArtistsOrUsers method.
 func (service *DefaultUserService) UnfollowArtistsOrUsers(input models.UnfollowArtistsOrUsersRequest) error {
+	return service.UnfollowArtistsOrUsersCtx(context.Background(), input)
+}
+
+// UnfollowArtistsOrUsersCtx implements the UserService's interface UnfollowArtistsOrUsersCtx method.
+func (service *DefaultUserService) UnfollowArtistsOrUsersCtx(ctx context.Context, input models.UnfollowArtistsOrUsersRequest) error {
+	// Check the granted scopes
+	if err := checkScopes(service.client, "UserService.UnfollowArtistsOrUsers"); err != nil {
+		return err
+	}
+
 	// Validate the input
 	if input.Type == "" {
-		return &utils.AppError{Status: http.StatusBadRequest, Message: consts.MsgTypeRequired}
-	}
-	if input.Ids == "" {
-		return &utils.AppError{Status: http.StatusBadRequest, Message: consts.MsgIdsRequired}
+		return utils.NewError(http.StatusBadRequest, consts.MsgTypeRequired, nil)
 	}
-
-	// Add inputs to the query parameters
-	params := map[string]string{"type": input.Type, "ids": input.Ids}
-
-	// Make an API call
-	res, err := service.client.Delete(context.Background(), consts.EndpointFollowing, nil, params, input.Body)
-	if err != nil {
-		return &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToUnfollowArtistsOrUsers, Err: err}
+	if err := validateIds(input.Ids); err != nil {
+		return err
 	}
 
-	// Handle Spotify API error
-	if res.StatusCode != http.StatusOK {
-		return utils.ParseSpotifyError(res, utils.RegErrorType)
-	}
+	// Make an API call per chunk of IDs
+	return doChunked(input.Ids, consts.MaxFollowIds, func(ids []spotifyid.ID) error {
+		// Add inputs to the query parameters
+		params := map[string]string{"type": input.Type, "ids": join(ids)}
 
-	// Return the empty response
-	return nil
+		// Make an API call and discard the response body
+		return doEmpty(ctx, service.client, http.MethodDelete, consts.EndpointFollowing, params, nil, consts.MsgFailedToUnfollowArtistsOrUsers)
+	})
 }
 
 // CheckUserFollowsArtistsOrUsers implements the UserService's interface CheckUserFollowsArtistsOrUsers method.
 func (service *DefaultUserService) CheckUserFollowsArtistsOrUsers(input models.UserFollowsArtistsOrUsersRequest) (*models.CheckUserFollowsArtistsOrUsers, error) {
-	// Validate the input
-	if input.Type == "" {
-		return nil, &utils.AppError{Status: http.StatusBadRequest, Message: consts.MsgTypeRequired}
-	}
-	if input.Ids == "" {
-		return nil, &utils.AppError{Status: http.StatusBadRequest, Message: consts.MsgIdsRequired}
-	}
-
-	// Add inputs to the query parameters
-	params := map[string]string{"type": input.Type, "ids": input.Ids}
+	return service.CheckUserFollowsArtistsOrUsersCtx(context.Background(), input)
+}
 
-	// Make an API call
-	res, err := service.client.Get(context.Background(), consts.EndpointUserFollowsArtistsOrUsers, params)
-	if err != nil {
-		return nil, &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToCheckIfUserFollowsArtistsOrUsers, Err: err}
+// CheckUserFollowsArtistsOrUsersCtx implements the UserService's interface CheckUserFollowsArtistsOrUsersCtx method.
+func (service *DefaultUserService) CheckUserFollowsArtistsOrUsersCtx(ctx context.Context, input models.UserFollowsArtistsOrUsersRequest) (*models.CheckUserFollowsArtistsOrUsers, error) {
+	// Check the granted scopes
+	if err := checkScopes(service.client, "UserService.CheckUserFollowsArtistsOrUsers"); err != nil {
+		return nil, err
 	}
 
-	// Handle Spotify API error
-	if res.StatusCode != http.StatusOK {
-		return nil, utils.ParseSpotifyError(res, utils.RegErrorType)
+	// Validate the input
+	if input.Type == "" {
+		return nil, utils.NewError(http.StatusBadRequest, consts.MsgTypeRequired, nil)
 	}
-
-	// Read the response body
-	data, err := io.ReadAll(res.Body)
-	if err != nil {
+	if err := validateIds(input.Ids); err != nil {
 		return nil, err
 	}
-	defer res.Body.Close()
 
-	// Unmarshal the response data into CheckUserFollowsArtistsOrUsers struct
-	var checkUserFollowsArtistsOrUsers models.CheckUserFollowsArtistsOrUsers
-	if err := json.Unmarshal(data, &checkUserFollowsArtistsOrUsers); err != nil {
-		return nil, err
-	}
+	// Make an API call per chunk of IDs and merge the responses
+	return getChunked(input.Ids, consts.MaxFollowIds,
+		func(ids []spotifyid.ID) (*models.CheckUserFollowsArtistsOrUsers, error) {
+			// Add inputs to the query parameters
+			params := map[string]string{"type": input.Type, "ids": join(ids)}
 
-	// Return the CheckUserFollowsArtistsOrUsers
-	return &checkUserFollowsArtistsOrUsers, nil
+			// Make an API call and decode the response data into CheckUserFollowsArtistsOrUsers struct
+			return doJSON[models.CheckUserFollowsArtistsOrUsers](ctx, service.client, http.MethodGet, consts.EndpointUserFollowsArtistsOrUsers, params, nil, consts.MsgFailedToCheckIfUserFollowsArtistsOrUsers)
+		},
+		func(result, chunk *models.CheckUserFollowsArtistsOrUsers) {
+			*result = append(*result, *chunk...)
+		},
+	)
 }
 
 // CheckCurrentUserFollowsPlaylist implements the UserService's interface CheckCurrentUserFollowsPlaylist method.
 func (service *DefaultUserService) CheckCurrentUserFollowsPlaylist(input models.CurrentUserFollowsPlaylistRequest) (*models.CheckCurrentUserFollowsPlaylist, error) {
+	return service.CheckCurrentUserFollowsPlaylistCtx(context.Background(), input)
+}
+
+// CheckCurrentUserFollowsPlaylistCtx implements the UserService's interface CheckCurrentUserFollowsPlaylistCtx method.
+func (service *DefaultUserService) CheckCurrentUserFollowsPlaylistCtx(ctx context.Context, input models.CurrentUserFollowsPlaylistRequest) (*models.CheckCurrentUserFollowsPlaylist, error) {
 	// Validate the input
 	if input.PlaylistId == "" {
-		return nil, &utils.AppError{Status: http.StatusBadRequest, Message: consts.MsgPlaylistIdRequired}
+		return nil, utils.NewError(http.StatusBadRequest, consts.MsgPlaylistIdRequired, nil)
+	}
+	if err := validateMaxCount(len(input.Ids), consts.MaxFollowsPlaylistIds, consts.MsgTooManyIds); err != nil {
+		return nil, err
 	}
 
 	// Substitute id in the endpoint
 	endpoint := fmt.Sprintf(consts.EndpointCurrentUserFollowsPlaylist, input.PlaylistId)
 
 	// Add inputs to the query parameters
-	params := map[string]string{"playlist_id": input.PlaylistId, "ids": input.Ids}
-
-	// Make an API call
-	res, err := service.client.Get(context.Background(), endpoint, params)
-	if err != nil {
-		return nil, &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToCheckIfCurrentUserFollowsPlaylist, Err: err}
-	}
-
-	// Handle Spotify API error
-	if res.StatusCode != http.StatusOK {
-		return nil, utils.ParseSpotifyError(res, utils.RegErrorType)
-	}
-
-	// Read the response body
-	data, err := io.ReadAll(res.Body)
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
-
-	// Unmarshal the response data into CheckCurrentUserFollowsPlaylist struct
-	var checkCurrentUserFollowsPlaylist models.CheckCurrentUserFollowsPlaylist
-	if err := json.Unmarshal(data, &checkCurrentUserFollowsPlaylist); err != nil {
-		return nil, err
-	}
+	params := map[string]string{"playlist_id": string(input.PlaylistId), "ids": join(input.Ids)}
 
-	// Return the CheckCurrentUserFollowsPlaylist
-	return &checkCurrentUserFollowsPlaylist, nil
+	// Make an API call and decode the response data into CheckCurrentUserFollowsPlaylist struct
+	return doJSON[models.CheckCurrentUserFollowsPlaylist](ctx, service.client, http.MethodGet, endpoint, params, nil, consts.MsgFailedToCheckIfCurrentUserFollowsPlaylist)
 }
@@ -2,8 +2,6 @@ package gospotify
 
 import (
 	"context"
-	"encoding/json"
-	"io"
 	"net/http"
 	"net/url"
 	"strings"
@@ -19,16 +17,41 @@ type CredentialsExchanger interface {
 	ExchangeCodeForTokens(httpClient *utils.HttpClient, code string) (*models.AuthToken, error)
 }
 
+// ContextCodeExchanger interface defines the method for token retrieval which can be cancelled with a context.
+// The login prefers it over ExchangeCodeForTokens, so the exchange is bounded by the login's context.
+// Credentials and PKCECredentials implement it.
+type ContextCodeExchanger interface {
+	ExchangeCodeForTokensCtx(ctx context.Context, httpClient *utils.HttpClient, code string) (*models.AuthToken, error)
+}
+
+// RedirectUrlConfigurer interface defines the methods for reading and replacing the redirect url of the credentials.
+// The callback server is derived from the redirect url, and the actual url is filled in when it's listening on an ephemeral port.
+// Credentials and PKCECredentials implement it.
+type RedirectUrlConfigurer interface {
+	GetRedirectUrl() string
+	SetRedirectUrl(redirectUrl string)
+}
+
+// accountsConfigurer interface defines the methods for pointing the credentials at a custom Accounts API, see WithAccountsBaseUrl.
+// Credentials and PKCECredentials implement it.
+type accountsConfigurer interface {
+	setAccounts(baseUrl string, httpClient *utils.HttpClient)
+}
+
 // Credentials struct holds the client ID, client secret and redirect url.
 type Credentials struct {
 	ClientId     string
 	ClientSecret string
 	RedirectUrl  string
+
+	// Accounts API to use instead of the default one
+	accountsBaseUrl string
+	accountsClient  *utils.HttpClient
 }
 
 // GetAuthorizationUrl generates the URL for initiating the authorization flow.
 func (c *Credentials) GetAuthorizationUrl(scopes []string, state string) (string, error) {
-	authUrl := consts.BaseUrlAccounts + consts.EndpointAuthorize
+	authUrl := accountsBaseUrl(c.accountsBaseUrl) + consts.EndpointAuthorize
 
 	// Create a new URL object with the base URL
 	u, err := url.Parse(authUrl)
@@ -39,7 +62,6 @@ func (c *Credentials) GetAuthorizationUrl(scopes []string, state string) (string
 	// Set the query parameters for the authorization URL
 	q := u.Query()
 	q.Set("client_id", c.ClientId)
-	q.Set("client_secret", c.ClientSecret)
 	q.Set("redirect_uri", c.RedirectUrl)
 	q.Set("response_type", "code")
 	q.Set("scope", strings.Join(scopes, " "))
@@ -50,8 +72,28 @@ func (c *Credentials) GetAuthorizationUrl(scopes []string, state string) (string
 	return u.String(), nil
 }
 
+// GetRedirectUrl implements the RedirectUrlConfigurer interface, returning the redirect url.
+func (c *Credentials) GetRedirectUrl() string {
+	return c.RedirectUrl
+}
+
+// SetRedirectUrl implements the RedirectUrlConfigurer interface, replacing the redirect url.
+func (c *Credentials) SetRedirectUrl(redirectUrl string) {
+	c.RedirectUrl = redirectUrl
+}
+
+// setAccounts implements the accountsConfigurer interface.
+func (c *Credentials) setAccounts(baseUrl string, httpClient *utils.HttpClient) {
+	c.accountsBaseUrl, c.accountsClient = baseUrl, httpClient
+}
+
 // ExchangeCodeForTokens method fetches an access token from the Accounts API.
 func (c *Credentials) ExchangeCodeForTokens(httpClient *utils.HttpClient, code string) (*models.AuthToken, error) {
+	return c.ExchangeCodeForTokensCtx(context.Background(), httpClient, code)
+}
+
+// ExchangeCodeForTokensCtx implements the ContextCodeExchanger interface, fetching an access token from the Accounts API.
+func (c *Credentials) ExchangeCodeForTokensCtx(ctx context.Context, httpClient *utils.HttpClient, code string) (*models.AuthToken, error) {
 	// Set the required headers
 	headers := map[string]string{
 		"Content-Type": "application/x-www-form-urlencoded",
@@ -66,31 +108,118 @@ func (c *Credentials) ExchangeCodeForTokens(httpClient *utils.HttpClient, code s
 		"code":          code,
 	}
 
-	// Make a POST request to the token endpoint
-	res, err := httpClient.Post(context.Background(), consts.EndpointToken, headers, nil, formValues, nil)
+	// Make a POST request to the token endpoint and return the AuthToken
+	return utils.RequestToken(ctx, httpClient, headers, formValues)
+}
+
+// RefreshToken implements the utils.TokenRefresher interface, refreshing the tokens using the client id and client secret.
+func (c *Credentials) RefreshToken(ctx context.Context, authToken *models.AuthToken) (*models.AuthToken, error) {
+	refresher := &utils.ClientSecretRefresher{ClientId: c.ClientId, ClientSecret: c.ClientSecret, HttpClient: c.accountsClient}
+	return refresher.RefreshToken(ctx, authToken)
+}
+
+// PKCECredentials struct holds the client ID and redirect url for the Authorization Code with PKCE flow.
+// This flow doesn't need a client secret, which makes it suitable for desktop, mobile and CLI apps that can't keep one.
+// For details, visit: https://developer.spotify.com/documentation/web-api/tutorials/code-pkce-flow
+type PKCECredentials struct {
+	ClientId    string
+	RedirectUrl string
+
+	// Code verifier generated for the last authorization url
+	codeVerifier string
+	// Accounts API to use instead of the default one
+	accountsBaseUrl string
+	accountsClient  *utils.HttpClient
+}
+
+// GetAuthorizationUrl generates a new code verifier and the URL for initiating the authorization flow with its S256 code challenge.
+func (c *PKCECredentials) GetAuthorizationUrl(scopes []string, state string) (string, error) {
+	// Generate the code verifier, which is sent later along with the code
+	codeVerifier, err := utils.GenerateCodeVerifier()
 	if err != nil {
-		return nil, &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgPostCallFailed, Err: err}
+		return "", utils.NewError(http.StatusInternalServerError, consts.MsgCodeVerifierGenerationFailure, err)
 	}
+	c.codeVerifier = codeVerifier
 
-	// Handle Spotify API error
-	if res.StatusCode != http.StatusOK {
-		return nil, utils.ParseSpotifyError(res, utils.AuthErrorType)
-	}
+	authUrl := accountsBaseUrl(c.accountsBaseUrl) + consts.EndpointAuthorize
 
-	// Read the response body
-	data, err := io.ReadAll(res.Body)
+	// Create a new URL object with the base URL
+	u, err := url.Parse(authUrl)
 	if err != nil {
-		return nil, &utils.Error{Type: utils.AppErrorType, AppError: &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToReadResponseBody, Err: err}}
+		return "", err
 	}
-	defer res.Body.Close()
 
-	// Unmarshal the response data into AuthToken struct
-	var authToken models.AuthToken
-	if err := json.Unmarshal(data, &authToken); err != nil {
-		return nil, &utils.Error{Type: utils.AppErrorType, AppError: &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToUnmarshalResponseData, Err: err}}
+	// Set the query parameters for the authorization URL
+	q := u.Query()
+	q.Set("client_id", c.ClientId)
+	q.Set("redirect_uri", c.RedirectUrl)
+	q.Set("response_type", "code")
+	q.Set("scope", strings.Join(scopes, " "))
+	q.Set("state", state)
+	q.Set("code_challenge_method", "S256")
+	q.Set("code_challenge", utils.CodeChallengeS256(codeVerifier))
+	u.RawQuery = q.Encode()
+
+	// Return the constructed authorization URL as a string
+	return u.String(), nil
+}
+
+// GetRedirectUrl implements the RedirectUrlConfigurer interface, returning the redirect url.
+func (c *PKCECredentials) GetRedirectUrl() string {
+	return c.RedirectUrl
+}
+
+// SetRedirectUrl implements the RedirectUrlConfigurer interface, replacing the redirect url.
+func (c *PKCECredentials) SetRedirectUrl(redirectUrl string) {
+	c.RedirectUrl = redirectUrl
+}
+
+// setAccounts implements the accountsConfigurer interface.
+func (c *PKCECredentials) setAccounts(baseUrl string, httpClient *utils.HttpClient) {
+	c.accountsBaseUrl, c.accountsClient = baseUrl, httpClient
+}
+
+// ExchangeCodeForTokens method fetches an access token from the Accounts API, proving the possession of the code verifier.
+func (c *PKCECredentials) ExchangeCodeForTokens(httpClient *utils.HttpClient, code string) (*models.AuthToken, error) {
+	return c.ExchangeCodeForTokensCtx(context.Background(), httpClient, code)
+}
+
+// ExchangeCodeForTokensCtx implements the ContextCodeExchanger interface,
+// fetching an access token from the Accounts API and proving the possession of the code verifier.
+func (c *PKCECredentials) ExchangeCodeForTokensCtx(ctx context.Context, httpClient *utils.HttpClient, code string) (*models.AuthToken, error) {
+	// The code verifier is generated along with the authorization url
+	if c.codeVerifier == "" {
+		return nil, utils.NewError(http.StatusInternalServerError, consts.MsgCodeVerifierNotFound, nil)
+	}
+
+	// Set the required headers
+	headers := map[string]string{
+		"Content-Type": "application/x-www-form-urlencoded",
+	}
+
+	// Set the form values for the request
+	formValues := map[string]string{
+		"grant_type":    "authorization_code",
+		"client_id":     c.ClientId,
+		"redirect_uri":  c.RedirectUrl,
+		"code":          code,
+		"code_verifier": c.codeVerifier,
 	}
-	authToken.SetExpiryTime()
 
-	// Return the AuthToken
-	return &authToken, nil
+	// Make a POST request to the token endpoint and return the AuthToken
+	return utils.RequestToken(ctx, httpClient, headers, formValues)
+}
+
+// RefreshToken implements the utils.TokenRefresher interface, refreshing the tokens using the client id only.
+func (c *PKCECredentials) RefreshToken(ctx context.Context, authToken *models.AuthToken) (*models.AuthToken, error) {
+	refresher := &utils.PKCERefresher{ClientId: c.ClientId, HttpClient: c.accountsClient}
+	return refresher.RefreshToken(ctx, authToken)
+}
+
+// accountsBaseUrl returns the given base url of the Accounts API, or the default one if it's empty.
+func accountsBaseUrl(baseUrl string) string {
+	if baseUrl == "" {
+		return consts.BaseUrlAccounts
+	}
+	return baseUrl
 }
@@ -4,6 +4,10 @@ import (
 	"context"
 	"net/http"
 	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/alicse3/gospotify/apis"
 	"github.com/alicse3/gospotify/consts"
@@ -11,6 +15,9 @@ import (
 	"github.com/alicse3/gospotify/utils"
 )
 
+// DefaultLoginTimeout is how long the login flow waits for the user to authorize the app in the browser.
+const DefaultLoginTimeout = 5 * time.Minute
+
 // Client represents the Spotify API client.
 type Client struct {
 	// Services to interact with Spotify api
@@ -28,6 +35,90 @@ type Client struct {
 	ShowService      apis.ShowService
 	TrackService     apis.TrackService
 	UserService      apis.UserService
+
+	// HTTP client shared by all the services
+	httpClient *utils.HttpClient
+	// Configuration and Accounts API client the client was created with, for Authorize
+	options        *options
+	accountsClient *utils.HttpClient
+	// For running one login flow at a time
+	authorizeMu sync.Mutex
+}
+
+// SetRetryPolicy replaces the policy used by all the services for retrying rate limited and failed requests.
+// Use utils.NoRetryPolicy() to send every request exactly once.
+func (c *Client) SetRetryPolicy(retryPolicy utils.RetryPolicy) {
+	c.httpClient.SetRetryPolicy(retryPolicy)
+}
+
+// Token returns a copy of the current token of the client, e.g. for saving it, or nil if there is none.
+// It's safe to call while requests are in flight, and never refreshes the token.
+func (c *Client) Token() *models.AuthToken {
+	if tokens := c.httpClient.TokenManager(); tokens != nil {
+		return tokens.Token()
+	}
+	return nil
+}
+
+// AccessToken returns the access token of the client, refreshing it first if it's about to expire, e.g. for sending it
+// with other HTTP code. The refresh is bound to the given context.
+func (c *Client) AccessToken(ctx context.Context) (string, error) {
+	tokens := c.httpClient.TokenManager()
+	if tokens == nil {
+		return "", utils.NewError(http.StatusInternalServerError, consts.MsgAuthTokenNotInitialised, nil)
+	}
+	return tokens.AccessToken(ctx)
+}
+
+// SetToken replaces the token of the client, e.g. with the one obtained by logging in again after ErrReauthRequired,
+// and saves it to the token store, if any.
+func (c *Client) SetToken(authToken *models.AuthToken) error {
+	tokens := c.httpClient.TokenManager()
+	if tokens == nil {
+		return utils.NewError(http.StatusInternalServerError, consts.MsgAuthTokenNotInitialised, nil)
+	}
+	return tokens.SetToken(authToken)
+}
+
+// Authorize runs the login flow again to add the given scopes to the ones granted to the client, e.g. once the app finds out
+// it needs playlist-modify-private. The user is asked for all the scopes, and the obtained token replaces the current one
+// without disturbing the requests in flight, and is saved to the token store, if any. Nothing happens if the scopes have all been granted already.
+// It's only supported by the clients created with WithCredentials, or one of the constructors using it, and uses the same login dependencies.
+// The login is bound to the given context, as well as to the login timeout.
+func (c *Client) Authorize(ctx context.Context, extraScopes []string) error {
+	tokens := c.httpClient.TokenManager()
+	if c.options == nil || c.options.credentials == nil || tokens == nil {
+		return utils.NewError(http.StatusInternalServerError, consts.MsgAuthorizeNotSupported, nil)
+	}
+
+	// Run one login flow at a time, a concurrent one may have granted the scopes already
+	c.authorizeMu.Lock()
+	defer c.authorizeMu.Unlock()
+
+	// Ask for the union of the granted, the initially requested and the extra scopes
+	current := tokens.Token()
+	scopes := slices.Concat(strings.Fields(current.Scope), c.options.scopes, extraScopes)
+	slices.Sort(scopes)
+	scopes = slices.Compact(scopes)
+	if current.HasScopes(scopes) {
+		return nil
+	}
+
+	// Bound the login by the login timeout
+	if c.options.loginTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.options.loginTimeout)
+		defer cancel()
+	}
+
+	// Log in and swap the token
+	o := c.options
+	authToken, err := authorize(ctx, o.credentials, o.stateGenerator, o.httpServer, o.browserOpener, c.accountsClient, scopes)
+	if err != nil {
+		return err
+	}
+
+	return tokens.SetToken(authToken)
 }
 
 // GetCredentialsFromEnv reads the credentials(SPOTIFY_CLIENT_ID, SPOTIFY_CLIENT_SECRET, SPOTIFY_REDIRECT_URL) from environment variables and returns them.
@@ -36,24 +127,85 @@ func GetCredentialsFromEnv() (*Credentials, error) {
 	// Get SPOTIFY_CLIENT_ID value from env
 	clientId := os.Getenv(consts.EnvClientId)
 	if clientId == "" {
-		return nil, &utils.Error{Type: utils.AppErrorType, AppError: &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgClientIdNotFound}}
+		return nil, utils.NewError(http.StatusInternalServerError, consts.MsgClientIdNotFound, nil)
 	}
 
 	// Get SPOTIFY_CLIENT_SECRET value from env
 	clientSecret := os.Getenv(consts.EnvClientSecret)
 	if clientSecret == "" {
-		return nil, &utils.Error{Type: utils.AppErrorType, AppError: &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgClientSecretNotFound}}
+		return nil, utils.NewError(http.StatusInternalServerError, consts.MsgClientSecretNotFound, nil)
 	}
 
 	// Get SPOTIFY_REDIRECT_URL value from env
 	redirectUrl := os.Getenv(consts.EnvRedirectUrl)
 	if redirectUrl == "" {
-		return nil, &utils.Error{Type: utils.AppErrorType, AppError: &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgRedirectUrlNotFound}}
+		return nil, utils.NewError(http.StatusInternalServerError, consts.MsgRedirectUrlNotFound, nil)
 	}
 
 	return &Credentials{ClientId: clientId, ClientSecret: clientSecret, RedirectUrl: redirectUrl}, nil
 }
 
+// New initializes and returns a new Spotify client configured with the given options.
+// One of WithCredentials, WithClientCredentials, WithAuthToken, WithTokenRefresher or WithToken is required for obtaining the token.
+// For example, to log in with PKCE and point the client at a local fake:
+//
+//	client, err := gospotify.New(
+//		gospotify.WithCredentials(&gospotify.PKCECredentials{ClientId: "your_client_id", RedirectUrl: "your_redirect_uri"}, scopes),
+//		gospotify.WithApiBaseUrl("http://127.0.0.1:9090"),
+//		gospotify.WithAccountsBaseUrl("http://127.0.0.1:9090"),
+//		gospotify.WithTimeout(30*time.Second),
+//	)
+func New(opts ...Option) (*Client, error) {
+	return NewCtx(context.Background(), opts...)
+}
+
+// NewCtx is like New but the login and the token requests are bound to the given context as well.
+func NewCtx(ctx context.Context, opts ...Option) (*Client, error) {
+	// Apply the options over the defaults
+	o := &options{
+		apiBaseUrl:      consts.BaseUrlApi,
+		accountsBaseUrl: consts.BaseUrlAccounts,
+		stateGenerator:  &utils.DefaultStateGenerator{},
+		httpServer:      &utils.DefaultHttpServer{},
+		browserOpener:   utils.NewDefaultBrowserOpener(&utils.DefaultCommandExectutor{}),
+		loginTimeout:    DefaultLoginTimeout,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	if o.tokenSource == nil {
+		return nil, utils.NewError(http.StatusInternalServerError, consts.MsgTokenSourceNotFound, nil)
+	}
+
+	// Create an HTTP client for the Accounts API and obtain the token
+	accountsClient := o.newHttpClient(o.accountsBaseUrl, nil, nil)
+	authToken, refresher, err := o.tokenSource(ctx, o, accountsClient)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create an HTTP client for the Web API with the token
+	httpClient := o.newHttpClient(o.apiBaseUrl, authToken, refresher)
+	if o.retryPolicy != nil {
+		httpClient.SetRetryPolicy(*o.retryPolicy)
+	}
+	httpClient.SetTokenStore(o.tokenStore)
+	if tokens := httpClient.TokenManager(); tokens != nil {
+		if o.refreshMargin != nil {
+			tokens.SetRefreshMargin(*o.refreshMargin)
+		}
+		tokens.SetOnReauthRequired(o.onReauthRequired)
+	}
+	httpClient.SetCache(o.cache)
+	httpClient.SetRateLimiter(o.rateLimiter)
+
+	// Init and return the Client instance
+	client := initClient(httpClient)
+	client.options, client.accountsClient = o, accountsClient
+	return client, nil
+}
+
 // DefaultClient initializes and returns a new Spotify client.
 func DefaultClient() (*Client, error) {
 	credentials, err := GetCredentialsFromEnv()
@@ -76,7 +228,7 @@ func DefaultClientWithCustomScopes(scopes []string) (*Client, error) {
 
 // NewClient initializes and returns a new Spotify client.
 func NewClient(credentials Credentials) (*Client, error) {
-	return NewClientWithDependencies(&credentials, &utils.DefaultStateGenerator{}, &utils.DefaultHttpServer{}, utils.NewDefaultBrowserOpener(&utils.DefaultCommandExectutor{}), []string{})
+	return New(WithCredentials(&credentials, []string{}))
 }
 
 // NewClientWithCustomScopes initializes the client with given custom scopes and returns a new Spotify client.
@@ -92,10 +244,66 @@ func NewClient(credentials Credentials) (*Client, error) {
 //	       gospotify.AllScopes, // Passing all scopes
 //		)
 func NewClientWithCustomScopes(credentials Credentials, scopes []string) (*Client, error) {
-	return NewClientWithDependencies(&credentials, &utils.DefaultStateGenerator{}, &utils.DefaultHttpServer{}, utils.NewDefaultBrowserOpener(&utils.DefaultCommandExectutor{}), scopes)
+	return New(WithCredentials(&credentials, scopes))
+}
+
+// NewClientWithPKCE initializes the client using the Authorization Code with PKCE flow and returns a new Spotify client.
+// No client secret is needed, which makes it suitable for desktop and CLI apps.
+// For example:
+//
+//	client, err := gospotify.NewClientWithPKCE(
+//		gospotify.PKCECredentials{
+//			ClientId:    "your_client_id",
+//			RedirectUrl: "your_redirect_uri",
+//		},
+//		[]string{gospotify.ScopeUserReadEmail},
+//	)
+func NewClientWithPKCE(credentials PKCECredentials, scopes []string) (*Client, error) {
+	return New(WithCredentials(&credentials, scopes))
+}
+
+// NewClientWithClientCredentials initializes the client using the Client Credentials flow and returns a new Spotify client.
+// There is no user, browser or callback server involved, the RedirectUrl of the credentials isn't used.
+// The client can only access the Spotify catalog (albums, artists, tracks, search etc.), not any user information.
+// A new app token is obtained transparently whenever the current one expires.
+func NewClientWithClientCredentials(credentials Credentials) (*Client, error) {
+	return New(WithClientCredentials(credentials))
+}
+
+// NewClientWithTokenStore initializes the client with the token from the given store and returns a new Spotify client.
+// The login flow only runs if the store has no token yet, or if the stored token lacks any of the given scopes.
+// The obtained token is saved to the store, and so is every refreshed token afterwards,
+// so the user only needs to log in once across the runs of the app.
+// For example, to keep the token in a file:
+//
+//	client, err := gospotify.NewClientWithTokenStore(
+//		&gospotify.PKCECredentials{
+//			ClientId:    "your_client_id",
+//			RedirectUrl: "your_redirect_uri",
+//		},
+//		utils.NewFileTokenStore("/home/user/.config/app/token.json"),
+//		[]string{gospotify.ScopeUserReadEmail},
+//	)
+func NewClientWithTokenStore(credentials CredentialsExchanger, tokenStore utils.TokenStore, scopes []string) (*Client, error) {
+	return New(WithCredentials(credentials, scopes), WithTokenStore(tokenStore))
+}
+
+// NewClientHeadless initializes the client without opening a browser and returns a new Spotify client.
+// It's meant for the machines without a browser, such as remote servers and CI runners.
+// The authorization url is printed to stdout for the user to open it on any device, and the login completes
+// with the callback on the local server or with the redirect url or the code pasted to stdin, whichever comes first.
+// Use NewClientWithDependencies with utils.HeadlessHttpServer and utils.HeadlessBrowserOpener for any other reader or writer.
+func NewClientHeadless(credentials CredentialsExchanger, scopes []string) (*Client, error) {
+	return New(
+		WithCredentials(credentials, scopes),
+		WithLoginDependencies(nil, utils.NewHeadlessHttpServer(&utils.DefaultHttpServer{}, os.Stdin), utils.NewHeadlessBrowserOpener(os.Stdout)),
+	)
 }
 
 // NewClientWithDependencies initializes and returns a new Spotify client.
+// If credentials also implements the utils.TokenRefresher interface, as Credentials and PKCECredentials do,
+// it's used for refreshing the tokens once they expire.
+// The login fails if the callback isn't received within DefaultLoginTimeout.
 func NewClientWithDependencies(
 	credentials CredentialsExchanger,
 	stateGenerator utils.StateGenerator,
@@ -103,52 +311,130 @@ func NewClientWithDependencies(
 	browserOpener utils.BrowserOpener,
 	scopes []string,
 ) (*Client, error) {
+	return New(WithCredentials(credentials, scopes), WithLoginDependencies(stateGenerator, httpServer, browserOpener))
+}
+
+// NewClientWithDependenciesCtx is like NewClientWithDependencies but the login is bound to the given context instead of DefaultLoginTimeout.
+// Cancelling the context or hitting its deadline before the callback is received aborts the login.
+func NewClientWithDependenciesCtx(
+	ctx context.Context,
+	credentials CredentialsExchanger,
+	stateGenerator utils.StateGenerator,
+	httpServer utils.HttpServer,
+	browserOpener utils.BrowserOpener,
+	scopes []string,
+) (*Client, error) {
+	return NewCtx(ctx, WithCredentials(credentials, scopes), WithLoginDependencies(stateGenerator, httpServer, browserOpener), WithLoginTimeout(0))
+}
+
+// loginWithStore returns the token from the store of the options if it has all the scopes,
+// otherwise it runs the login flow and saves the obtained token to the store.
+func loginWithStore(ctx context.Context, o *options, accountsClient *utils.HttpClient, credentials CredentialsExchanger, scopes []string) (*models.AuthToken, error) {
+	// Reuse the stored token if it has all the scopes
+	if o.tokenStore != nil {
+		storedToken, err := o.tokenStore.Load()
+		if err != nil {
+			return nil, utils.NewError(http.StatusInternalServerError, consts.MsgFailedToLoadToken, err)
+		}
+		if storedToken != nil && storedToken.HasScopes(scopes) {
+			return storedToken, nil
+		}
+	}
+
+	// Bound the login by the login timeout
+	if o.loginTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, o.loginTimeout)
+		defer cancel()
+	}
+
+	// Otherwise log in and store the obtained token
+	authToken, err := authorize(ctx, credentials, o.stateGenerator, o.httpServer, o.browserOpener, accountsClient, scopes)
+	if err != nil {
+		return nil, err
+	}
+
+	if o.tokenStore != nil {
+		if err := o.tokenStore.Save(authToken); err != nil {
+			return nil, utils.NewError(http.StatusInternalServerError, consts.MsgFailedToSaveToken, err)
+		}
+	}
+
+	return authToken, nil
+}
+
+// authorize runs the login flow with the given dependencies and returns the obtained token.
+// It fails if the user denies the access, or if the context is done before the callback is received.
+func authorize(
+	ctx context.Context,
+	credentials CredentialsExchanger,
+	stateGenerator utils.StateGenerator,
+	httpServer utils.HttpServer,
+	browserOpener utils.BrowserOpener,
+	accountsClient *utils.HttpClient,
+	scopes []string,
+) (*models.AuthToken, error) {
 	// Generate a random state string for security
 	state, err := stateGenerator.GetRandomState(16)
 	if err != nil {
-		return nil, &utils.Error{Type: utils.AppErrorType, AppError: &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgStateGenerationFailure, Err: err}}
+		return nil, utils.NewError(http.StatusInternalServerError, consts.MsgStateGenerationFailure, err)
 	}
 
 	// Use channel for obtaining the authorization code
-	ch := make(chan string)
-	ctx, cancel := context.WithCancel(context.Background())
+	ch := make(chan utils.CallbackResult)
+	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
+	// Derive the callback server from the redirect url, if the credentials expose it
+	configurer, _ := credentials.(RedirectUrlConfigurer)
+	redirectUrl := ""
+	if configurer != nil {
+		redirectUrl = configurer.GetRedirectUrl()
+	}
+
 	// Start an HTTP server to listen for the authentication callback
-	go httpServer.StartServer(ctx, ch)
+	actualRedirectUrl, err := httpServer.StartServer(ctx, redirectUrl, state, ch)
+	if err != nil {
+		return nil, utils.NewError(http.StatusInternalServerError, consts.MsgFailedToStartCallbackServer, err)
+	}
 
-	// TODO: Handle http server errors through channel?
+	// Use the actual redirect url for this login only, it differs from the configured one for an ephemeral port
+	if configurer != nil && actualRedirectUrl != "" && actualRedirectUrl != redirectUrl {
+		configurer.SetRedirectUrl(actualRedirectUrl)
+		defer configurer.SetRedirectUrl(redirectUrl)
+	}
 
 	// Generate authorization url with provided state and scopes
 	authUrl, err := credentials.GetAuthorizationUrl(scopes, state)
 	if err != nil {
-		return nil, &utils.Error{Type: utils.AppErrorType, AppError: &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgGettingAuthUrlFailure, Err: err}}
+		return nil, utils.NewError(http.StatusInternalServerError, consts.MsgGettingAuthUrlFailure, err)
 	}
 
 	// Open the authUrl in default browser
 	if err := browserOpener.Open(authUrl); err != nil {
-		return nil, &utils.Error{Type: utils.AppErrorType, AppError: &utils.AppError{Status: http.StatusInternalServerError, Message: consts.MsgOpeningBrowserFailure, Err: err}}
+		return nil, utils.NewError(http.StatusInternalServerError, consts.MsgOpeningBrowserFailure, err)
 	}
 
 	// Wait for the authentication callback and get the code
-	code := <-ch
-
-	// Create an HTTP client and get an access token
-	httpClient := utils.NewHttpClient(consts.BaseUrlAccounts)
-	authToken, err := credentials.ExchangeCodeForTokens(httpClient, code)
-	if err != nil {
-		return nil, err
+	var result utils.CallbackResult
+	select {
+	case result = <-ch:
+	case <-ctx.Done():
+		return nil, utils.NewError(http.StatusRequestTimeout, consts.MsgLoginTimedOut, ctx.Err())
+	}
+	if result.Err != nil {
+		return nil, result.Err
 	}
 
-	// Init and return the Client instance
-	return initClient(authToken, credentials.(*Credentials)), nil
+	// Get an access token, within the login's context if the credentials support it
+	if exchanger, ok := credentials.(ContextCodeExchanger); ok {
+		return exchanger.ExchangeCodeForTokensCtx(ctx, accountsClient, result.Code)
+	}
+	return credentials.ExchangeCodeForTokens(accountsClient, result.Code)
 }
 
 // initClient is a re-usable method to create a client with provided dependencies.
-func initClient(authToken *models.AuthToken, credentials *Credentials) *Client {
-	// Create an HTTP httpClient with access token
-	httpClient := utils.NewHttpClientWithToken(consts.BaseUrlApi, authToken, credentials.ClientId, credentials.ClientSecret)
-
+func initClient(httpClient *utils.HttpClient) *Client {
 	// Intialize services and return the Client instance
 	return &Client{
 		AlbumService:     apis.NewDefaultAlbumService(httpClient),
@@ -165,6 +451,7 @@ func initClient(authToken *models.AuthToken, credentials *Credentials) *Client {
 		ShowService:      apis.NewDefaultShowService(httpClient),
 		TrackService:     apis.NewDefaultTrackService(httpClient),
 		UserService:      apis.NewDefultUserService(httpClient),
+		httpClient:       httpClient,
 	}
 }
 
@@ -172,6 +459,13 @@ func initClient(authToken *models.AuthToken, credentials *Credentials) *Client {
 // This is useful when you have a valid token and want to create a client with that token.
 // For example, you can use this method when you want to set the permanent token.
 // It doesn't support the token refresh functionality. Error will be thrown when the access token is expired.
+// Use NewClientWithAuthToken for a token which should be refreshed.
 func NewClientWithToken(token string) (*Client, error) {
-	return initClient(&models.AuthToken{AccessToken: token}, nil), nil
+	return New(WithToken(token))
+}
+
+// NewClientWithAuthToken initializes and returns a new Spotify client with the provided token, e.g. one obtained earlier,
+// which is refreshed with the given refresher shortly before it expires. Credentials and PKCECredentials can be used as one.
+func NewClientWithAuthToken(authToken *models.AuthToken, refresher utils.TokenRefresher) (*Client, error) {
+	return New(WithAuthToken(authToken, refresher))
 }
@@ -0,0 +1,150 @@
+package gospotify
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicse3/gospotify/models"
+	"github.com/alicse3/gospotify/utils"
+)
+
+// fakeExchanger is a CredentialsExchanger recording the scopes asked for, and exchanging the code for the token access-1,
+// access-2 and so on, granted the asked scopes.
+type fakeExchanger struct {
+	scopes []string
+	logins int
+	// Returned instead of a token, if not nil
+	err error
+	mu  sync.Mutex
+}
+
+func (fe *fakeExchanger) GetAuthorizationUrl(scopes []string, state string) (string, error) {
+	fe.mu.Lock()
+	defer fe.mu.Unlock()
+
+	fe.scopes = scopes
+	return "https://accounts.spotify.com/authorize?scope=" + strings.Join(scopes, "+") + "&state=" + state, nil
+}
+
+func (fe *fakeExchanger) ExchangeCodeForTokens(_ *utils.HttpClient, code string) (*models.AuthToken, error) {
+	fe.mu.Lock()
+	defer fe.mu.Unlock()
+
+	if code != "code" {
+		return nil, errors.New("unexpected code " + code)
+	}
+	if fe.err != nil {
+		return nil, fe.err
+	}
+	fe.logins++
+	return &models.AuthToken{
+		AccessToken:  "access-" + string(rune('0'+fe.logins)),
+		RefreshToken: "refresh",
+		Scope:        strings.Join(fe.scopes, " "),
+		ExpiryTime:   time.Now().Add(time.Hour),
+	}, nil
+}
+
+// fakeLogin holds the login dependencies of a user who authorizes the app right away: the callback brings the code.
+type fakeLogin struct{}
+
+func (fakeLogin) GetRandomState(int) (string, error) {
+	return "state", nil
+}
+
+func (fakeLogin) StartServer(ctx context.Context, redirectUrl, state string, ch chan<- utils.CallbackResult) (string, error) {
+	go func() {
+		select {
+		case ch <- utils.CallbackResult{Code: "code"}:
+		case <-ctx.Done():
+		}
+	}()
+	return redirectUrl, nil
+}
+
+func (fakeLogin) Open(string) error {
+	return nil
+}
+
+// newAuthorizeClient creates a client logged in with the token access-0 granted user-read-email, which is in the token store.
+func newAuthorizeClient(t *testing.T, exchanger *fakeExchanger) (*Client, *utils.MemoryTokenStore) {
+	t.Helper()
+
+	store := utils.NewMemoryTokenStore()
+	store.Save(&models.AuthToken{AccessToken: "access-0", RefreshToken: "refresh", Scope: "user-read-email", ExpiryTime: time.Now().Add(time.Hour)})
+
+	client, err := New(
+		WithCredentials(exchanger, []string{"user-read-email"}),
+		WithTokenStore(store),
+		WithLoginDependencies(fakeLogin{}, fakeLogin{}, fakeLogin{}),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	return client, store
+}
+
+func TestClientAuthorize(t *testing.T) {
+	exchanger := &fakeExchanger{}
+	client, store := newAuthorizeClient(t, exchanger)
+
+	// The user is asked for the granted scopes along with the extra ones, sorted and listed once
+	if err := client.Authorize(context.Background(), []string{"user-read-email", "playlist-modify-private"}); err != nil {
+		t.Fatalf("Authorize() error = %v", err)
+	}
+	if want := []string{"playlist-modify-private", "user-read-email"}; !slices.Equal(exchanger.scopes, want) {
+		t.Errorf("scopes asked for = %v, want %v", exchanger.scopes, want)
+	}
+
+	// The new token replaces the current one and is saved
+	if authToken := client.Token(); authToken.AccessToken != "access-1" || authToken.Scope != "playlist-modify-private user-read-email" {
+		t.Errorf("Token() = %+v, want access-1 with both scopes", authToken)
+	}
+	if accessToken, _ := client.AccessToken(context.Background()); accessToken != "access-1" {
+		t.Errorf("AccessToken() = %q, want access-1", accessToken)
+	}
+	if stored, _ := store.Load(); stored == nil || stored.AccessToken != "access-1" {
+		t.Errorf("stored token = %+v, want access-1", stored)
+	}
+
+	// Nothing happens once the scopes have been granted
+	if err := client.Authorize(context.Background(), []string{"playlist-modify-private"}); err != nil {
+		t.Fatalf("Authorize() of granted scopes error = %v", err)
+	}
+	if exchanger.logins != 1 {
+		t.Errorf("%d logins, want 1", exchanger.logins)
+	}
+}
+
+func TestClientAuthorizeFailureKeepsToken(t *testing.T) {
+	exchanger := &fakeExchanger{err: errors.New("exchange failed")}
+	client, store := newAuthorizeClient(t, exchanger)
+
+	if err := client.Authorize(context.Background(), []string{"playlist-modify-private"}); !errors.Is(err, exchanger.err) {
+		t.Fatalf("Authorize() error = %v, want the error of the exchange", err)
+	}
+
+	// The current token is kept, both by the client and by the store
+	if authToken := client.Token(); authToken.AccessToken != "access-0" || authToken.Scope != "user-read-email" {
+		t.Errorf("Token() = %+v, want access-0", authToken)
+	}
+	if stored, _ := store.Load(); stored == nil || stored.AccessToken != "access-0" {
+		t.Errorf("stored token = %+v, want access-0", stored)
+	}
+}
+
+func TestClientAuthorizeNotSupported(t *testing.T) {
+	client, err := New(WithToken("access"))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	if err := client.Authorize(context.Background(), []string{"playlist-modify-private"}); err == nil {
+		t.Error("Authorize() error = nil for a client without credentials, want an error")
+	}
+}
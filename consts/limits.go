@@ -0,0 +1,22 @@
+package consts
+
+// Constants for the maximum number of IDs, URIs and seeds accepted by a single API call.
+// The services split longer lists into several API calls, except where noted.
+// For details, visit: https://developer.spotify.com/documentation/web-api/reference
+const (
+	MaxAlbumIds         = 20
+	MaxArtistIds        = 50
+	MaxAudiobookIds     = 50
+	MaxChapterIds       = 50
+	MaxEpisodeIds       = 50
+	MaxShowIds          = 50
+	MaxTrackIds         = 50
+	MaxAudioFeaturesIds = 100
+	MaxFollowIds        = 50
+	MaxPlaylistItemUris = 100 // Not split when given in a request body
+
+	// Not split, an API call gives recommendations for all the seeds together
+	MaxRecommendationSeeds = 5
+	// Not split, only the current user's ID can be checked
+	MaxFollowsPlaylistIds = 1
+)
@@ -19,9 +19,23 @@ const (
 	MsgStateGenerationFailure        = "State generation failure"
 	MsgGettingAuthUrlFailure         = "Getting auth url failure"
 	MsgOpeningBrowserFailure         = "Opening browser failure"
+	MsgStateMismatch                 = "State in the callback doesn't match the one sent with the authorization url"
+	MsgCodeNotFound                  = "Code not found in the callback"
+	MsgLoginTimedOut                 = "Login timed out or was cancelled before the callback was received"
+	MsgFailedToStartCallbackServer   = "Failed to start the callback server"
+	MsgInvalidRedirectUrl            = "Invalid redirect url, it must be an absolute http or https url"
+	MsgEphemeralPortRequiresLoopback = "Ephemeral port 0 is only supported for a loopback redirect url"
+	MsgCertificateRequired           = "Certificate and key files are required for an https redirect url"
 	MsgEmptyClientId                 = "Client Id is empty"
 	MsgEmptyClientSecret             = "Client Secret is empty"
 	MsgAuthTokenNotInitialised       = "Auth token is not initialised"
+	MsgTokenRefreshNotSupported      = "Token refresh is not supported by the client"
+	MsgTokenSourceNotFound           = "Token source not found, one of WithCredentials, WithClientCredentials, WithAuthToken, WithTokenRefresher or WithToken is required"
+	MsgAuthorizeNotSupported         = "Authorization is only supported by the clients created with WithCredentials"
+	MsgCodeVerifierGenerationFailure = "Code verifier generation failure"
+	MsgFailedToLoadToken             = "Failed to load token"
+	MsgFailedToSaveToken             = "Failed to save token"
+	MsgCodeVerifierNotFound          = "Code verifier not found, the authorization url must be generated first"
 	MsgFailedToReadResponseBody      = "Failed to read response body"
 	MsgFailedToRefreshTokens         = "Failed to refresh tokens"
 	MsgFailedToUnmarshalResponseData = "Failed to unmarshal response data"
@@ -32,6 +46,10 @@ const (
 	MsgFailedToCreatePutRequest      = "Failed to create put request"
 	MsgFailedToCreateDeleteRequest   = "Failed to create delete request"
 	MsgFailedToSendRequest           = "Failed to send request"
+	MsgUnsupportedMethod             = "Unsupported HTTP method"
+	MsgUnexpectedItemCount           = "Unexpected number of items in the response"
+	MsgMissingScopes                 = "Missing authorization scopes"
+	MsgUnknownMethod                 = "Unknown service method"
 
 	MsgFailedToGetAlbum         = "Failed to get an Album"
 	MsgFailedToGetAlbums        = "Failed to get Albums"
@@ -70,14 +88,20 @@ const (
 	MsgStateRequired                = "State is required"
 	MsgVolumePercentMustBeInclusive = "Volume percent be a value from 0 to 100 inclusive"
 	MsgUriRequired                  = "URI is required"
+	MsgTrackOrEpisodeUriRequired    = "URI of a track or an episode is required"
 	MsgPlaylistIdRequired           = "Playlist ID is required"
 	MsgUserIdRequired               = "User ID is required"
 	MsgSearchQueryRequired          = "Search query is required"
 	MsgSearchTypeRequired           = "Search type is/are required"
-	MsgSeedArtistsRequired          = "Seed Artists are required"
-	MsgSeedGenresRequired           = "Seed Genres are required"
-	MsgSeedTracksRequired           = "Seed Tracks are required"
+	MsgSeedsRequired                = "At least one of Seed Artists, Seed Genres or Seed Tracks is required"
+	MsgTooManySeeds                 = "Too many Seed Artists, Seed Genres and Seed Tracks in total"
+	MsgEmptyId                      = "IDs must not contain an empty ID"
+	MsgEmptyUri                     = "URIs must not contain an empty URI"
+	MsgTooManyIds                   = "Too many IDs"
+	MsgTooManyUris                  = "Too many URIs"
 	MsgTypeRequired                 = "Type is required"
+	MsgInvalidCursor                = "Invalid cursor"
+	MsgAfterAndBeforeExclusive      = "Only one of After and Before can be set"
 
 	MsgFailedToGetEpisode         = "Failed to get an Episode"
 	MsgFailedToGetEpisodes        = "Failed to get Episodes"
@@ -155,4 +179,8 @@ const (
 	MsgUknownErrorType     = "Unknown error type"
 
 	MsgCodeReceived = "Okay! You can close this window now."
+	MsgLoginFailed  = "Login failed, you can close this window now and try again."
+
+	MsgOpenUrlToLogIn   = "Open the following url in a browser on any device to log in:"
+	MsgPasteRedirectUrl = "Once logged in, paste the url of the page you're redirected to, or the code in it, here:"
 )
@@ -0,0 +1,26 @@
+package gospotify
+
+import "github.com/alicse3/gospotify/utils"
+
+// Error is the single error type returned by the client, see utils.Error for details.
+// It can be inspected with errors.As:
+//
+//	var spotifyErr *gospotify.Error
+//	if errors.As(err, &spotifyErr) && spotifyErr.Reason == utils.ReasonNoActiveDevice {
+//		...
+//	}
+type Error = utils.Error
+
+// Sentinel errors for checking the errors returned by the client with errors.Is:
+//
+//	if errors.Is(err, gospotify.ErrNotFound) {
+//		...
+//	}
+var (
+	ErrNotFound        = utils.ErrNotFound
+	ErrUnauthorized    = utils.ErrUnauthorized
+	ErrRateLimited     = utils.ErrRateLimited
+	ErrPremiumRequired = utils.ErrPremiumRequired
+	ErrReauthRequired  = utils.ErrReauthRequired
+	ErrMissingScope    = utils.ErrMissingScope
+)
@@ -1,3 +1,3 @@
 module github.com/alicse3/gospotify
 
-go 1.22.5
+go 1.24.0
@@ -1,20 +1,22 @@
 package models
 
+import "github.com/alicse3/gospotify/spotifyid"
+
 // GetAlbumRequest represents the get album's request information.
 type GetAlbumRequest struct {
-	Id     string // Required: The Spotify ID of the album.
+	Id     spotifyid.ID // Required: The Spotify ID of the album.
 	Market string
 }
 
 // GetAlbumsRequest represents the get albums request information.
 type GetAlbumsRequest struct {
-	Ids    string // Required: A comma-separated list of the Spotify IDs for the albums. Maximum: 20 IDs.
+	Ids    []spotifyid.ID // Required: The Spotify IDs for the albums. Maximum per API call: 20 IDs, more are split into several calls.
 	Market string
 }
 
 // GetAlbumTracksRequest represents the get album tracks request information.
 type GetAlbumTracksRequest struct {
-	Id     string // Required: The Spotify ID of the album.
+	Id     spotifyid.ID // Required: The Spotify ID of the album.
 	Market string
 	Limit  int
 	Offset int
@@ -29,23 +31,17 @@ type GetSavedAlbumsRequest struct {
 
 // SaveAlbumsRequest represents the save albums request information.
 type SaveAlbumsRequest struct {
-	Ids  string // Required: A comma-separated list of the Spotify IDs for the albums. Maximum: 20 IDs.
-	Body struct {
-		Ids []string `json:"ids"`
-	}
+	Ids []spotifyid.ID // Required: The Spotify IDs for the albums. Maximum per API call: 20 IDs, more are split into several calls.
 }
 
 // RemoveAlbumsRequest represents the remove albums request information.
 type RemoveAlbumsRequest struct {
-	Ids  string // Required: A comma-separated list of the Spotify IDs for the albums. Maximum: 20 IDs.
-	Body struct {
-		Ids []string `json:"ids"`
-	}
+	Ids []spotifyid.ID // Required: The Spotify IDs for the albums. Maximum per API call: 20 IDs, more are split into several calls.
 }
 
 // CheckSavedAlbumsRequest represents the check saved albums request information.
 type CheckSavedAlbumsRequest struct {
-	Ids string // Required: A comma-separated list of the Spotify IDs for the albums. Maximum: 20 IDs.
+	Ids []spotifyid.ID // Required: The Spotify IDs for the albums. Maximum per API call: 20 IDs, more are split into several calls.
 }
 
 // GetNewReleasesRequest represents the get new releases request information.
@@ -56,52 +52,55 @@ type GetNewReleasesRequest struct {
 
 // AlbumTracks represents the track's information retrieved from the Spotify API.
 type AlbumTracks struct {
-	Href     string `json:"href"`
-	Limit    int    `json:"limit"`
-	Next     string `json:"next"`
-	Offset   int    `json:"offset"`
-	Previous string `json:"previous"`
-	Total    int    `json:"total"`
-	Items    []struct {
-		Artists []struct {
-			ExternalUrls struct {
-				Spotify string `json:"spotify"`
-			} `json:"external_urls"`
-			Href string `json:"href"`
-			Id   string `json:"id"`
-			Name string `json:"name"`
-			Type string `json:"type"`
-			Uri  string `json:"uri"`
-		} `json:"artists"`
-		AvailableMarkets []string `json:"available_markets"`
-		DiscNumber       int      `json:"disc_number"`
-		DurationMs       int      `json:"duration_ms"`
-		Explicit         bool     `json:"explicit"`
-		ExternalUrls     struct {
+	Href     string            `json:"href"`
+	Limit    int               `json:"limit"`
+	Next     string            `json:"next"`
+	Offset   int               `json:"offset"`
+	Previous string            `json:"previous"`
+	Total    int               `json:"total"`
+	Items    []SimplifiedTrack `json:"items"`
+}
+
+// SimplifiedTrack represents the simplified track information retrieved from the Spotify API.
+type SimplifiedTrack struct {
+	Artists []struct {
+		ExternalUrls struct {
+			Spotify string `json:"spotify"`
+		} `json:"external_urls"`
+		Href string `json:"href"`
+		Id   string `json:"id"`
+		Name string `json:"name"`
+		Type string `json:"type"`
+		Uri  string `json:"uri"`
+	} `json:"artists"`
+	AvailableMarkets []string `json:"available_markets"`
+	DiscNumber       int      `json:"disc_number"`
+	DurationMs       int      `json:"duration_ms"`
+	Explicit         bool     `json:"explicit"`
+	ExternalUrls     struct {
+		Spotify string `json:"spotify"`
+	} `json:"external_urls"`
+	Href       string `json:"href"`
+	Id         string `json:"id"`
+	IsPlayable bool   `json:"is_playable"`
+	LinkedFrom struct {
+		ExternalUrls struct {
 			Spotify string `json:"spotify"`
 		} `json:"external_urls"`
-		Href       string `json:"href"`
-		Id         string `json:"id"`
-		IsPlayable bool   `json:"is_playable"`
-		LinkedFrom struct {
-			ExternalUrls struct {
-				Spotify string `json:"spotify"`
-			} `json:"external_urls"`
-			Href string `json:"href"`
-			Id   string `json:"id"`
-			Type string `json:"type"`
-			Uri  string `json:"uri"`
-		} `json:"linked_from"`
-		Restrictions struct {
-			Reason string `json:"reason"`
-		} `json:"restrictions"`
-		Name        string `json:"name"`
-		PreviewUrl  string `json:"preview_url"`
-		TrackNumber int    `json:"track_number"`
-		Type        string `json:"type"`
-		Uri         string `json:"uri"`
-		IsLocal     bool   `json:"is_local"`
-	} `json:"items"`
+		Href string `json:"href"`
+		Id   string `json:"id"`
+		Type string `json:"type"`
+		Uri  string `json:"uri"`
+	} `json:"linked_from"`
+	Restrictions struct {
+		Reason string `json:"reason"`
+	} `json:"restrictions"`
+	Name        string `json:"name"`
+	PreviewUrl  string `json:"preview_url"`
+	TrackNumber int    `json:"track_number"`
+	Type        string `json:"type"`
+	Uri         string `json:"uri"`
+	IsLocal     bool   `json:"is_local"`
 }
 
 // Album represents the album's information retrieved from the Spotify API.
@@ -159,16 +158,19 @@ type Albums struct {
 
 // SavedAlbums represents the saved albums information retrieved from the Spotify API.
 type SavedAlbums struct {
-	Href     string `json:"href"`
-	Limit    int    `json:"limit"`
-	Next     string `json:"next"`
-	Offset   int    `json:"offset"`
-	Previous string `json:"previous"`
-	Total    int    `json:"total"`
-	Items    []struct {
-		AddedAt string `json:"added_at"`
-		Album   Album  `json:"album"`
-	} `json:"items"`
+	Href     string       `json:"href"`
+	Limit    int          `json:"limit"`
+	Next     string       `json:"next"`
+	Offset   int          `json:"offset"`
+	Previous string       `json:"previous"`
+	Total    int          `json:"total"`
+	Items    []SavedAlbum `json:"items"`
+}
+
+// SavedAlbum represents the saved album information retrieved from the Spotify API.
+type SavedAlbum struct {
+	AddedAt string `json:"added_at"`
+	Album   Album  `json:"album"`
 }
 
 // CheckSavedAlbums represents the check saved albums information retrieved from the Spotify API.
@@ -1,18 +1,20 @@
 package models
 
+import "github.com/alicse3/gospotify/spotifyid"
+
 // GetArtistRequest represents the get artist's request information.
 type GetArtistRequest struct {
-	Id string // Required: The Spotify ID of the artist.
+	Id spotifyid.ID // Required: The Spotify ID of the artist.
 }
 
 // GetArtistsRequest represents the get artists request information.
 type GetArtistsRequest struct {
-	Ids string // Required: A comma-separated list of the Spotify IDs for the artists. Maximum: 50 IDs.
+	Ids []spotifyid.ID // Required: The Spotify IDs for the artists. Maximum per API call: 50 IDs, more are split into several calls.
 }
 
 // GetArtistAlbumsRequest represents the get artists albums request information.
 type GetArtistAlbumsRequest struct {
-	Id            string // Required: The Spotify ID of the artist.
+	Id            spotifyid.ID // Required: The Spotify ID of the artist.
 	IncludeGroups string
 	Market        string
 	Limit         int
@@ -21,13 +23,13 @@ type GetArtistAlbumsRequest struct {
 
 // GetArtistTopTracksRequest represents the get artists top tracks request information.
 type GetArtistTopTracksRequest struct {
-	Id     string // Required: The Spotify ID of the artist.
+	Id     spotifyid.ID // Required: The Spotify ID of the artist.
 	Market string
 }
 
 // GetRelatedArtistsRequest represents the get related artists request information.
 type GetRelatedArtistsRequest struct {
-	Id string // Required: The Spotify ID of the artist.
+	Id spotifyid.ID // Required: The Spotify ID of the artist.
 }
 
 // Artist represents the artist's information retrieved from the Spotify API.
@@ -103,7 +105,7 @@ type ArtistAlbums struct {
 	Previous string `json:"previous"`
 	Total    int    `json:"total"`
 	Items    []struct {
-		ArtistAlbum ArtistAlbum
+		ArtistAlbum
 	} `json:"items"`
 }
 
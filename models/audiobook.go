@@ -1,19 +1,21 @@
 package models
 
+import "github.com/alicse3/gospotify/spotifyid"
+
 // GetAudiobookRequest represents the get audio book's request information.
 type GetAudiobookRequest struct {
-	Id string // Required: The Spotify ID for the audiobook.
+	Id spotifyid.ID // Required: The Spotify ID for the audiobook.
 }
 
 // GetAudiobooksRequest represents the get audio books request information.
 type GetAudiobooksRequest struct {
-	Ids    string // Required: A comma-separated list of the Spotify IDs. Maximum: 50 IDs.
+	Ids    []spotifyid.ID // Required: The Spotify IDs. Maximum per API call: 50 IDs, more are split into several calls.
 	Market string
 }
 
 // GetAudiobookChaptersRequest represents the get audio book chapters request information.
 type GetAudiobookChaptersRequest struct {
-	Id     string // Required: The Spotify ID for the audiobook.
+	Id     spotifyid.ID // Required: The Spotify ID for the audiobook.
 	Market string
 	Limit  int
 	Offset int
@@ -27,17 +29,17 @@ type GetSavedAudiobooksRequest struct {
 
 // SaveAudiobooksRequest represents the save audio books request information.
 type SaveAudiobooksRequest struct {
-	Ids string // Required: A comma-separated list of the Spotify IDs. Maximum: 50 IDs.
+	Ids []spotifyid.ID // Required: The Spotify IDs. Maximum per API call: 50 IDs, more are split into several calls.
 }
 
 // RemoveAudiobooksRequest represents the remove audio books request information.
 type RemoveAudiobooksRequest struct {
-	Ids string // Required: A comma-separated list of the Spotify IDs. Maximum: 50 IDs.
+	Ids []spotifyid.ID // Required: The Spotify IDs. Maximum per API call: 50 IDs, more are split into several calls.
 }
 
 // CheckSavedAudiobooksRequest represents the remove audio books request information.
 type CheckSavedAudiobooksRequest struct {
-	Ids string // Required: A comma-separated list of the Spotify IDs. Maximum: 50 IDs.
+	Ids []spotifyid.ID // Required: The Spotify IDs. Maximum per API call: 50 IDs, more are split into several calls.
 }
 
 // Audiobook represents the audiobook's information retrieved from the Spotify API.
@@ -124,89 +126,95 @@ type Audiobooks struct {
 
 // AudiobookChapters represents the audiobook chapters information retrieved from the Spotify API.
 type AudiobookChapters struct {
-	Href     string `json:"href"`
-	Limit    int    `json:"limit"`
-	Next     string `json:"next"`
-	Offset   int    `json:"offset"`
-	Previous string `json:"previous"`
-	Total    int    `json:"total"`
-	Items    []struct {
-		AudioPreviewUrl  any      `json:"audio_preview_url"`
-		AvailableMarkets []string `json:"available_markets"`
-		ChapterNumber    int      `json:"chapter_number"`
-		Description      string   `json:"description"`
-		HtmlDescription  string   `json:"html_description"`
-		DurationMs       int      `json:"duration_ms"`
-		Explicit         bool     `json:"explicit"`
-		ExternalUrls     struct {
-			Spotify string `json:"spotify"`
-		} `json:"external_urls"`
-		Href   string `json:"href"`
-		Id     string `json:"id"`
-		Images []struct {
-			Url    string `json:"url"`
-			Height int    `json:"height"`
-			Width  int    `json:"width"`
-		} `json:"images"`
-		IsPlayable           bool     `json:"is_playable"`
-		Languages            []string `json:"languages"`
-		Name                 string   `json:"name"`
-		ReleaseDate          string   `json:"release_date"`
-		ReleaseDatePrecision string   `json:"release_date_precision"`
-		ResumePoint          struct {
-			FullyPlayed      bool `json:"fully_played"`
-			ResumePositionMs int  `json:"resume_position_ms"`
-		} `json:"resume_point"`
-		Type         string `json:"type"`
-		Uri          string `json:"uri"`
-		Restrictions struct {
-			Reason string `json:"reason"`
-		} `json:"restrictions"`
-	} `json:"items"`
+	Href     string              `json:"href"`
+	Limit    int                 `json:"limit"`
+	Next     string              `json:"next"`
+	Offset   int                 `json:"offset"`
+	Previous string              `json:"previous"`
+	Total    int                 `json:"total"`
+	Items    []SimplifiedChapter `json:"items"`
+}
+
+// SimplifiedChapter represents the simplified chapter information retrieved from the Spotify API.
+type SimplifiedChapter struct {
+	AudioPreviewUrl  any      `json:"audio_preview_url"`
+	AvailableMarkets []string `json:"available_markets"`
+	ChapterNumber    int      `json:"chapter_number"`
+	Description      string   `json:"description"`
+	HtmlDescription  string   `json:"html_description"`
+	DurationMs       int      `json:"duration_ms"`
+	Explicit         bool     `json:"explicit"`
+	ExternalUrls     struct {
+		Spotify string `json:"spotify"`
+	} `json:"external_urls"`
+	Href   string `json:"href"`
+	Id     string `json:"id"`
+	Images []struct {
+		Url    string `json:"url"`
+		Height int    `json:"height"`
+		Width  int    `json:"width"`
+	} `json:"images"`
+	IsPlayable           bool     `json:"is_playable"`
+	Languages            []string `json:"languages"`
+	Name                 string   `json:"name"`
+	ReleaseDate          string   `json:"release_date"`
+	ReleaseDatePrecision string   `json:"release_date_precision"`
+	ResumePoint          struct {
+		FullyPlayed      bool `json:"fully_played"`
+		ResumePositionMs int  `json:"resume_position_ms"`
+	} `json:"resume_point"`
+	Type         string `json:"type"`
+	Uri          string `json:"uri"`
+	Restrictions struct {
+		Reason string `json:"reason"`
+	} `json:"restrictions"`
 }
 
 // SavedAudiobooks represents the saved audiobook's information retrieved from the Spotify API.
 type SavedAudiobooks struct {
-	Href     string `json:"href"`
-	Limit    int    `json:"limit"`
-	Next     string `json:"next"`
-	Offset   int    `json:"offset"`
-	Previous string `json:"previous"`
-	Total    int    `json:"total"`
-	Items    []struct {
-		Authours []struct {
-			Name string `json:"name"`
-		} `json:"authors"`
-		AvailableMarkets []string `json:"available_markets"`
-		Copyrights       []struct {
-			Text string `json:"text"`
-			Type string `json:"type"`
-		} `json:"copyrights"`
-		Description     string `json:"description"`
-		HtmlDescription string `json:"html_description"`
-		Edition         string `json:"edition"`
-		Explicit        bool   `json:"explicit"`
-		ExternalUrls    struct {
-			Spotify string `json:"spotify"`
-		} `json:"external_urls"`
-		Href   string `json:"href"`
-		Id     string `json:"id"`
-		Images []struct {
-			Url    string `json:"url"`
-			Height int    `json:"height"`
-			Width  int    `json:"width"`
-		} `json:"images"`
-		Languages []string `json:"languages"`
-		MediaType string   `json:"media_type"`
-		Name      string   `json:"name"`
-		Narrators []struct {
-			Name string `json:"name"`
-		} `json:"narrators"`
-		Publisher     string `json:"publisher"`
-		Type          string `json:"type"`
-		Uri           string `json:"uri"`
-		TotalChapters int    `json:"total_chapters"`
-	} `json:"items"`
+	Href     string                `json:"href"`
+	Limit    int                   `json:"limit"`
+	Next     string                `json:"next"`
+	Offset   int                   `json:"offset"`
+	Previous string                `json:"previous"`
+	Total    int                   `json:"total"`
+	Items    []SimplifiedAudiobook `json:"items"`
+}
+
+// SimplifiedAudiobook represents the simplified audiobook information retrieved from the Spotify API.
+type SimplifiedAudiobook struct {
+	Authours []struct {
+		Name string `json:"name"`
+	} `json:"authors"`
+	AvailableMarkets []string `json:"available_markets"`
+	Copyrights       []struct {
+		Text string `json:"text"`
+		Type string `json:"type"`
+	} `json:"copyrights"`
+	Description     string `json:"description"`
+	HtmlDescription string `json:"html_description"`
+	Edition         string `json:"edition"`
+	Explicit        bool   `json:"explicit"`
+	ExternalUrls    struct {
+		Spotify string `json:"spotify"`
+	} `json:"external_urls"`
+	Href   string `json:"href"`
+	Id     string `json:"id"`
+	Images []struct {
+		Url    string `json:"url"`
+		Height int    `json:"height"`
+		Width  int    `json:"width"`
+	} `json:"images"`
+	Languages []string `json:"languages"`
+	MediaType string   `json:"media_type"`
+	Name      string   `json:"name"`
+	Narrators []struct {
+		Name string `json:"name"`
+	} `json:"narrators"`
+	Publisher     string `json:"publisher"`
+	Type          string `json:"type"`
+	Uri           string `json:"uri"`
+	TotalChapters int    `json:"total_chapters"`
 }
 
 // CheckSavedAudiobooks represents the check saved audiobooks information retrieved from the Spotify API.
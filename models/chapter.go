@@ -1,14 +1,16 @@
 package models
 
+import "github.com/alicse3/gospotify/spotifyid"
+
 // GetChapterRequest represents the get chapter request information.
 type GetChapterRequest struct {
-	Id     string // Required: The Spotify ID for the chapter.
+	Id     spotifyid.ID // Required: The Spotify ID for the chapter.
 	Market string
 }
 
 // GetChaptersRequest represents the get chapters request information.
 type GetChaptersRequest struct {
-	Ids    string // Required: A comma-separated list of the Spotify IDs. Maximum: 50 IDs.
+	Ids    []spotifyid.ID // Required: The Spotify IDs. Maximum per API call: 50 IDs, more are split into several calls.
 	Market string
 }
 
@@ -1,14 +1,16 @@
 package models
 
+import "github.com/alicse3/gospotify/spotifyid"
+
 // GetEpisodeRequest represents the get episode request information.
 type GetEpisodeRequest struct {
-	Id     string // Required: The Spotify ID for the episode.
+	Id     spotifyid.ID // Required: The Spotify ID for the episode.
 	Market string
 }
 
 // GetEpisodesRequest represents the get episode's request information.
 type GetEpisodesRequest struct {
-	Ids    string // Required: A comma-separated list of the Spotify IDs for the episodes. Maximum: 50 IDs.
+	Ids    []spotifyid.ID // Required: The Spotify IDs for the episodes. Maximum per API call: 50 IDs, more are split into several calls.
 	Market string
 }
 
@@ -21,23 +23,17 @@ type GetSavedEpisodesRequest struct {
 
 // SaveEpisodesRequest represents the save episode's request information.
 type SaveEpisodesRequest struct {
-	Ids  string // Required: A comma-separated list of the Spotify IDs. Maximum: 50 IDs.
-	Body struct {
-		Ids []string `json:"ids"`
-	}
+	Ids []spotifyid.ID // Required: The Spotify IDs. Maximum per API call: 50 IDs, more are split into several calls.
 }
 
 // RemoveEpisodesRequest represents the remove episode's request information.
 type RemoveEpisodesRequest struct {
-	Ids  string // Required: A comma-separated list of the Spotify IDs. Maximum: 50 IDs.
-	Body struct {
-		Ids []string `json:"ids"`
-	}
+	Ids []spotifyid.ID // Required: The Spotify IDs. Maximum per API call: 50 IDs, more are split into several calls.
 }
 
 // CheckSavedEpisodesRequest represents the check saved episode's request information.
 type CheckSavedEpisodesRequest struct {
-	Ids string // Required: A comma-separated list of the Spotify IDs for the episodes. Maximum: 50 IDs.
+	Ids []spotifyid.ID // Required: The Spotify IDs for the episodes. Maximum per API call: 50 IDs, more are split into several calls.
 }
 
 // Episode represents the episode's information retrieved from the Spotify API.
@@ -110,16 +106,19 @@ type Episodes struct {
 
 // SavedEpisodes represents the saved episodes information retrieved from the Spotify API.
 type SavedEpisodes struct {
-	Href     string `json:"href"`
-	Limit    int    `json:"limit"`
-	Next     string `json:"next"`
-	Offset   int    `json:"offset"`
-	Previous string `json:"previous"`
-	Total    int    `json:"total"`
-	Items    []struct {
-		AddedAt string  `json:"added_at"`
-		Episode Episode `json:"episode"`
-	} `json:"items"`
+	Href     string         `json:"href"`
+	Limit    int            `json:"limit"`
+	Next     string         `json:"next"`
+	Offset   int            `json:"offset"`
+	Previous string         `json:"previous"`
+	Total    int            `json:"total"`
+	Items    []SavedEpisode `json:"items"`
+}
+
+// SavedEpisode represents the saved episode information retrieved from the Spotify API.
+type SavedEpisode struct {
+	AddedAt string  `json:"added_at"`
+	Episode Episode `json:"episode"`
 }
 
 // CheckSavedEpisodes represents the check saved episodes information retrieved from the Spotify API.
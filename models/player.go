@@ -1,5 +1,11 @@
 package models
 
+import "github.com/alicse3/gospotify/spotifyid"
+
+import (
+	"time"
+)
+
 // GetPlaybackStateRequest represents the get playback state request information.
 type GetPlaybackStateRequest struct {
 	Market          string
@@ -30,8 +36,8 @@ type StartOrResumePlaybackRequestOffset struct {
 
 // StartOrResumePlaybackRequestBody represents the start or resume playback request's body information.
 type StartOrResumePlaybackRequestBody struct {
-	ContextUri string                             `json:"context_uri"`
-	Uris       []string                           `json:"uris"`
+	ContextUri spotifyid.URI                      `json:"context_uri"`
+	Uris       []spotifyid.URI                    `json:"uris"`
 	Offset     StartOrResumePlaybackRequestOffset `json:"offset"`
 	PositionMs int                                `json:"position_ms"`
 }
@@ -97,7 +103,7 @@ type GetRecentlyPlayedTracksRequest struct {
 
 // AddItemToPlaybackQueueRequest represents the add item to playback queue request information.
 type AddItemToPlaybackQueueRequest struct {
-	Uri      string // Required: The uri of the item to add to the queue. Must be a track or an episode uri.
+	Uri      spotifyid.URI // Required: The uri of the item to add to the queue. Must be a track or an episode uri.
 	DeviceId string
 }
 
@@ -275,42 +281,35 @@ type RecentlyPlayedTracks struct {
 		After  string `json:"after"`
 		Before string `json:"before"`
 	} `json:"cursors"`
-	Total int `json:"total"`
-	Items []struct {
-		Track struct {
-			Album struct {
-				AlbumType        string   `json:"album_type"`
-				TotalTracks      int      `json:"total_tracks"`
-				AvailableMarkets []string `json:"available_markets"`
-				ExternalUrls     struct {
-					Spotify string `json:"spotify"`
-				} `json:"external_urls"`
-				Href   string `json:"href"`
-				Id     string `json:"id"`
-				Images []struct {
-					Url    string `json:"url"`
-					Height int    `json:"height"`
-					Width  int    `json:"width"`
-				} `json:"images"`
-				Name                 string `json:"name"`
-				ReleaseDate          string `json:"release_date"`
-				ReleaseDatePrecision string `json:"release_date_precision"`
-				Restrictions         struct {
-					Reason string `json:"reason"`
-				} `json:"restrictions"`
-				Type    string `json:"type"`
-				Uri     string `json:"uri"`
-				Artists []struct {
-					ExternalUrls struct {
-						Spotify string `json:"spotify"`
-					} `json:"external_urls"`
-					Href string `json:"href"`
-					Id   string `json:"id"`
-					Name string `json:"name"`
-					Type string `json:"type"`
-					Uri  string `json:"uri"`
-				} `json:"artists"`
-			} `json:"album"`
+	Total int           `json:"total"`
+	Items []PlayHistory `json:"items"`
+}
+
+// PlayHistory represents the play history information retrieved from the Spotify API.
+type PlayHistory struct {
+	Track struct {
+		Album struct {
+			AlbumType        string   `json:"album_type"`
+			TotalTracks      int      `json:"total_tracks"`
+			AvailableMarkets []string `json:"available_markets"`
+			ExternalUrls     struct {
+				Spotify string `json:"spotify"`
+			} `json:"external_urls"`
+			Href   string `json:"href"`
+			Id     string `json:"id"`
+			Images []struct {
+				Url    string `json:"url"`
+				Height int    `json:"height"`
+				Width  int    `json:"width"`
+			} `json:"images"`
+			Name                 string `json:"name"`
+			ReleaseDate          string `json:"release_date"`
+			ReleaseDatePrecision string `json:"release_date_precision"`
+			Restrictions         struct {
+				Reason string `json:"reason"`
+			} `json:"restrictions"`
+			Type    string `json:"type"`
+			Uri     string `json:"uri"`
 			Artists []struct {
 				ExternalUrls struct {
 					Spotify string `json:"spotify"`
@@ -321,44 +320,59 @@ type RecentlyPlayedTracks struct {
 				Type string `json:"type"`
 				Uri  string `json:"uri"`
 			} `json:"artists"`
-			AvailableMarkets []string `json:"available_markets"`
-			DiscNumber       int      `json:"disc_number"`
-			DurationMs       int      `json:"duration_ms"`
-			Explicit         bool     `json:"explicit"`
-			ExternalIds      struct {
-				Isrc string `json:"isrc"`
-				Ean  string `json:"ean"`
-				Upc  string `json:"upc"`
-			} `json:"external_ids"`
-			ExternalUrls struct {
-				Spotify string `json:"spotify"`
-			} `json:"external_urls"`
-			Href       string `json:"href"`
-			Id         string `json:"id"`
-			IsPlayable bool   `json:"is_playable"`
-			LinkedFrom struct {
-			} `json:"linked_from"`
-			Restrictions struct {
-				Reason string `json:"reason"`
-			} `json:"restrictions"`
-			Name        string `json:"name"`
-			Popularity  int    `json:"popularity"`
-			PreviewUrl  string `json:"preview_url"`
-			TrackNumber int    `json:"track_number"`
-			Type        string `json:"type"`
-			Uri         string `json:"uri"`
-			IsLocal     bool   `json:"is_local"`
-		} `json:"track"`
-		PlayedAt string `json:"played_at"`
-		Context  struct {
-			Type         string `json:"type"`
-			Href         string `json:"href"`
+		} `json:"album"`
+		Artists []struct {
 			ExternalUrls struct {
 				Spotify string `json:"spotify"`
 			} `json:"external_urls"`
-			Uri string `json:"uri"`
-		} `json:"context"`
-	} `json:"items"`
+			Href string `json:"href"`
+			Id   string `json:"id"`
+			Name string `json:"name"`
+			Type string `json:"type"`
+			Uri  string `json:"uri"`
+		} `json:"artists"`
+		AvailableMarkets []string `json:"available_markets"`
+		DiscNumber       int      `json:"disc_number"`
+		DurationMs       int      `json:"duration_ms"`
+		Explicit         bool     `json:"explicit"`
+		ExternalIds      struct {
+			Isrc string `json:"isrc"`
+			Ean  string `json:"ean"`
+			Upc  string `json:"upc"`
+		} `json:"external_ids"`
+		ExternalUrls struct {
+			Spotify string `json:"spotify"`
+		} `json:"external_urls"`
+		Href       string `json:"href"`
+		Id         string `json:"id"`
+		IsPlayable bool   `json:"is_playable"`
+		LinkedFrom struct {
+		} `json:"linked_from"`
+		Restrictions struct {
+			Reason string `json:"reason"`
+		} `json:"restrictions"`
+		Name        string `json:"name"`
+		Popularity  int    `json:"popularity"`
+		PreviewUrl  string `json:"preview_url"`
+		TrackNumber int    `json:"track_number"`
+		Type        string `json:"type"`
+		Uri         string `json:"uri"`
+		IsLocal     bool   `json:"is_local"`
+	} `json:"track"`
+	PlayedAt string `json:"played_at"`
+	Context  struct {
+		Type         string `json:"type"`
+		Href         string `json:"href"`
+		ExternalUrls struct {
+			Spotify string `json:"spotify"`
+		} `json:"external_urls"`
+		Uri string `json:"uri"`
+	} `json:"context"`
+}
+
+// PlayedAtTime parses the PlayedAt field, which is an ISO 8601 timestamp in UTC.
+func (ph *PlayHistory) PlayedAtTime() (time.Time, error) {
+	return time.Parse(time.RFC3339, ph.PlayedAt)
 }
 
 // UsersQueue represents the users queue information retrieved from the Spotify API.
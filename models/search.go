@@ -55,40 +55,13 @@ type SearchResponse struct {
 	} `json:"albums"`
 	Playlists Playlists `json:"playlists"`
 	Shows     struct {
-		Href     string `json:"href"`
-		Limit    int    `json:"limit"`
-		Next     string `json:"next"`
-		Offset   int    `json:"offset"`
-		Previous string `json:"previous"`
-		Total    int    `json:"total"`
-		Items    []struct {
-			AvailableMarkets []string `json:"available_markets"`
-			Copyrights       []struct {
-				Text string `json:"text"`
-				Type string `json:"type"`
-			} `json:"copyrights"`
-			Description     string `json:"description"`
-			HtmlDescription string `json:"html_description"`
-			Explicit        bool   `json:"explicit"`
-			ExternalUrls    struct {
-				Spotify string `json:"spotify"`
-			} `json:"external_urls"`
-			Href   string `json:"href"`
-			Id     string `json:"id"`
-			Images []struct {
-				Url    string `json:"url"`
-				Height int    `json:"height"`
-				Width  int    `json:"width"`
-			} `json:"images"`
-			IsExternallyHosted bool     `json:"is_externally_hosted"`
-			Languages          []string `json:"languages"`
-			MediaType          string   `json:"media_type"`
-			Name               string   `json:"name"`
-			Publisher          string   `json:"publisher"`
-			Type               string   `json:"type"`
-			Uri                string   `json:"uri"`
-			TotalEpisodes      int      `json:"total_episodes"`
-		} `json:"items"`
+		Href     string           `json:"href"`
+		Limit    int              `json:"limit"`
+		Next     string           `json:"next"`
+		Offset   int              `json:"offset"`
+		Previous string           `json:"previous"`
+		Total    int              `json:"total"`
+		Items    []SimplifiedShow `json:"items"`
 	} `json:"shows"`
 	Episodes struct {
 		Href     string `json:"href"`
@@ -102,45 +75,12 @@ type SearchResponse struct {
 		} `json:"items"`
 	} `json:"episodes"`
 	Audiobooks struct {
-		Href     string `json:"href"`
-		Limit    int    `json:"limit"`
-		Next     string `json:"next"`
-		Offset   int    `json:"offset"`
-		Previous string `json:"previous"`
-		Total    int    `json:"total"`
-		Items    []struct {
-			Authours []struct {
-				Name string `json:"name"`
-			} `json:"authors"`
-			AvailableMarkets []string `json:"available_markets"`
-			Copyrights       []struct {
-				Text string `json:"text"`
-				Type string `json:"type"`
-			} `json:"copyrights"`
-			Description     string `json:"description"`
-			HtmlDescription string `json:"html_description"`
-			Edition         string `json:"edition"`
-			Explicit        bool   `json:"explicit"`
-			ExternalUrls    struct {
-				Spotify string `json:"spotify"`
-			} `json:"external_urls"`
-			Href   string `json:"href"`
-			Id     string `json:"id"`
-			Images []struct {
-				Url    string `json:"url"`
-				Height int    `json:"height"`
-				Width  int    `json:"width"`
-			} `json:"images"`
-			Languages []string `json:"languages"`
-			MediaType string   `json:"media_type"`
-			Name      string   `json:"name"`
-			Narrators []struct {
-				Name string `json:"name"`
-			} `json:"narrators"`
-			Publisher     string `json:"publisher"`
-			Type          string `json:"type"`
-			Uri           string `json:"uri"`
-			TotalChapters int    `json:"total_chapters"`
-		} `json:"items"`
+		Href     string                `json:"href"`
+		Limit    int                   `json:"limit"`
+		Next     string                `json:"next"`
+		Offset   int                   `json:"offset"`
+		Previous string                `json:"previous"`
+		Total    int                   `json:"total"`
+		Items    []SimplifiedAudiobook `json:"items"`
 	} `json:"audiobooks"`
 }
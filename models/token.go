@@ -1,17 +1,19 @@
 package models
 
 import (
+	"slices"
+	"strings"
 	"time"
 )
 
 // AuthToken represents the response from the Spotify API when requesting an access token.
 type AuthToken struct {
-	AccessToken  string `json:"access_token"`
-	RefreshToken string `json:"refresh_token"`
-	TokenType    string `json:"token_type"`
-	ExpiresIn    int    `json:"expires_in"`
-	Scope        string `json:"scope"`
-	ExpiryTime   time.Time
+	AccessToken  string    `json:"access_token"`
+	RefreshToken string    `json:"refresh_token"`
+	TokenType    string    `json:"token_type"`
+	ExpiresIn    int       `json:"expires_in"`
+	Scope        string    `json:"scope"`
+	ExpiryTime   time.Time `json:"expiry_time"`
 }
 
 // SetExpiryTime sets the ExpiryTime field of the AuthToken struct based on the ExpiresIn field.
@@ -23,3 +25,14 @@ func (at *AuthToken) SetExpiryTime() {
 func (at *AuthToken) IsExpired() bool {
 	return time.Now().After(at.ExpiryTime)
 }
+
+// HasScopes checks if all the given scopes have been granted to the AuthToken.
+func (at *AuthToken) HasScopes(scopes []string) bool {
+	granted := strings.Fields(at.Scope)
+	for _, scope := range scopes {
+		if !slices.Contains(granted, scope) {
+			return false
+		}
+	}
+	return true
+}
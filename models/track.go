@@ -1,14 +1,16 @@
 package models
 
+import "github.com/alicse3/gospotify/spotifyid"
+
 // GetTrackRequest represents the get track's request information.
 type GetTrackRequest struct {
-	Id     string // Required: The Spotify ID for the track.
+	Id     spotifyid.ID // Required: The Spotify ID for the track.
 	Market string
 }
 
 // GetTracksRequest represents the get tracks request information.
 type GetTracksRequest struct {
-	Ids    string // Required: A comma-separated list of the Spotify IDs. For example: ids=4iV5W9uYEdYUVa79Axb7Rh,1301WleyT98MSxVHPZCA6M. Maximum: 50 IDs.
+	Ids    []spotifyid.ID // Required: The Spotify IDs. Maximum per API call: 50 IDs, more are split into several calls.
 	Market string
 }
 
@@ -19,55 +21,43 @@ type GetSavedTracksRequest struct {
 	Offset int
 }
 
-// SaveTracksBody represents the save tracks body information.
-type SaveTracksBody struct {
-	Ids []string `json:"ids"`
-}
-
 // SaveTracksRequest represents the save tracks request information.
 type SaveTracksRequest struct {
-	Ids  string // Required: A comma-separated list of the Spotify IDs. For example: ids=4iV5W9uYEdYUVa79Axb7Rh,1301WleyT98MSxVHPZCA6M. Maximum: 50 IDs.
-	Body SaveTracksBody
-}
-
-// RemoveTracksBody represents the remove tracks body information.
-type RemoveTracksBody struct {
-	Ids []string `json:"ids"`
+	Ids []spotifyid.ID // Required: The Spotify IDs. Maximum per API call: 50 IDs, more are split into several calls.
 }
 
 // RemoveTracksRequest represents the remove tracks request information.
 type RemoveTracksRequest struct {
-	Ids  string // Required: A comma-separated list of the Spotify IDs. For example: ids=4iV5W9uYEdYUVa79Axb7Rh,1301WleyT98MSxVHPZCA6M. Maximum: 50 IDs.
-	Body RemoveTracksBody
+	Ids []spotifyid.ID // Required: The Spotify IDs. Maximum per API call: 50 IDs, more are split into several calls.
 }
 
 // CheckSavedTracksRequest represents the check saved tracks request information.
 type CheckSavedTracksRequest struct {
-	Ids string // Required: A comma-separated list of the Spotify IDs. For example: ids=4iV5W9uYEdYUVa79Axb7Rh,1301WleyT98MSxVHPZCA6M. Maximum: 50 IDs.
+	Ids []spotifyid.ID // Required: The Spotify IDs. Maximum per API call: 50 IDs, more are split into several calls.
 }
 
 // GetSeveralTracksAudioFeaturesRequest represents the several tracks audio features request information.
 type GetSeveralTracksAudioFeaturesRequest struct {
-	Ids string // Required: A comma-separated list of the Spotify IDs for the tracks. Maximum: 100 IDs.
+	Ids []spotifyid.ID // Required: The Spotify IDs for the tracks. Maximum per API call: 100 IDs, more are split into several calls.
 }
 
 // GetTracksAudioFeaturesRequest represents the tracks audio features request information.
 type GetTracksAudioFeaturesRequest struct {
-	Id string // Required: The Spotify ID for the track.
+	Id spotifyid.ID // Required: The Spotify ID for the track.
 }
 
 // GetTracksAudioAnalysisRequest represents the tracks audio analysis request information.
 type GetTracksAudioAnalysisRequest struct {
-	Id string // Required: The Spotify ID for the track.
+	Id spotifyid.ID // Required: The Spotify ID for the track.
 }
 
 // GetRecommendationsRequest represents the recommendations request information.
 type GetRecommendationsRequest struct {
 	Limit                  int
 	Market                 string
-	SeedArtists            string // Required: A comma separated list of Spotify IDs for seed artists. Up to 5 seed values may be provided in any combination of seed_artists, seed_tracks and seed_genres.
-	SeedGenres             string // Required: A comma separated list of any genres in the set of available genre seeds. Up to 5 seed values may be provided in any combination of seed_artists, seed_tracks and seed_genres.
-	SeedTracks             string // Required: A comma separated list of Spotify IDs for a seed track. Up to 5 seed values may be provided in any combination of seed_artists, seed_tracks and seed_genres.
+	SeedArtists            []spotifyid.ID // Spotify IDs for seed artists. At least 1 and up to 5 seed values may be provided in any combination of seed_artists, seed_tracks and seed_genres.
+	SeedGenres             []string       // Any genres in the set of available genre seeds. At least 1 and up to 5 seed values may be provided in any combination of seed_artists, seed_tracks and seed_genres.
+	SeedTracks             []spotifyid.ID // Spotify IDs for seed tracks. At least 1 and up to 5 seed values may be provided in any combination of seed_artists, seed_tracks and seed_genres.
 	MinAcousticness        float64
 	MaxAcousticness        float64
 	TargetAcousticness     float64
@@ -193,16 +183,19 @@ type Tracks struct {
 
 // SavedTracks represents the saved tracks information retrieved from the Spotify API.
 type SavedTracks struct {
-	Href     string `json:"href"`
-	Limit    int    `json:"limit"`
-	Next     string `json:"next"`
-	Offset   int    `json:"offset"`
-	Previous string `json:"previous"`
-	Total    int    `json:"total"`
-	Items    []struct {
-		AddedAt string `json:"added_at"`
-		Track   Track  `json:"track"`
-	} `json:"items"`
+	Href     string       `json:"href"`
+	Limit    int          `json:"limit"`
+	Next     string       `json:"next"`
+	Offset   int          `json:"offset"`
+	Previous string       `json:"previous"`
+	Total    int          `json:"total"`
+	Items    []SavedTrack `json:"items"`
+}
+
+// SavedTrack represents the saved track information retrieved from the Spotify API.
+type SavedTrack struct {
+	AddedAt string `json:"added_at"`
+	Track   Track  `json:"track"`
 }
 
 // CheckSavedTracks represents the check saved tracks information retrieved from the Spotify API.
@@ -0,0 +1,11 @@
+module github.com/alicse3/gospotify/oauth2adapter
+
+go 1.24.0
+
+require (
+	github.com/alicse3/gospotify v0.0.0
+	golang.org/x/oauth2 v0.35.0
+)
+
+// Build against the SDK of the same repository
+replace github.com/alicse3/gospotify => ../
@@ -0,0 +1,149 @@
+// Package oauth2adapter shares the tokens of the gospotify clients with golang.org/x/oauth2.
+// It's a module of its own, so the SDK itself doesn't depend on the oauth2 package.
+package oauth2adapter
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/alicse3/gospotify"
+	"github.com/alicse3/gospotify/consts"
+	"github.com/alicse3/gospotify/models"
+	"github.com/alicse3/gospotify/utils"
+	"golang.org/x/oauth2"
+)
+
+// Endpoint is the Spotify Accounts API endpoint for an oauth2.Config.
+var Endpoint = oauth2.Endpoint{
+	AuthURL:   consts.BaseUrlAccounts + consts.EndpointAuthorize,
+	TokenURL:  consts.BaseUrlAccounts + consts.EndpointToken,
+	AuthStyle: oauth2.AuthStyleInHeader,
+}
+
+// WithTokenSource obtains the token from the given oauth2.TokenSource, and asks it for a new one whenever the current one expires,
+// so the refresh is left to the source, e.g. one created by oauth2.Config.TokenSource or oauth2.ReuseTokenSource.
+// The oauth2.TokenSource interface takes no context, so a refresh isn't bound to the context of the request needing it,
+// nor to the one given to gospotify.NewCtx. Bound it with the source instead, e.g. with the context given to oauth2.Config.TokenSource
+// carrying an HTTP client with a timeout. The token is refreshed 5 seconds before its expiry, within the 10 seconds the source
+// refreshes it before, unless gospotify.WithTokenRefreshMargin is given.
+func WithTokenSource(tokenSource oauth2.TokenSource) gospotify.Option {
+	return gospotify.WithTokenRefresher(&oauth2Refresher{tokenSource})
+}
+
+// WithConfig uses the given token, e.g. one obtained with config.Exchange, and refreshes it with the given oauth2.Config.
+// The config's Endpoint should be Endpoint. The HTTP client for the refresh requests can be set on ctx
+// with the oauth2.HTTPClient key, as usual for the oauth2 package.
+func WithConfig(ctx context.Context, config *oauth2.Config, token *oauth2.Token) gospotify.Option {
+	return WithTokenSource(config.TokenSource(ctx, token))
+}
+
+// TokenSource returns an oauth2.TokenSource for the token of the client, e.g. for sharing it with other HTTP code.
+// The token is refreshed by the client, the same way as for its own requests, and the refreshes are bound to the given context.
+func TokenSource(ctx context.Context, client *gospotify.Client) oauth2.TokenSource {
+	return &clientTokenSource{ctx, client}
+}
+
+// clientTokenSource is a struct that implements oauth2.TokenSource interface for the token of a client.
+type clientTokenSource struct {
+	// Context of the refreshes, as Token takes none
+	ctx    context.Context
+	client *gospotify.Client
+}
+
+// Token returns the current token of the client, refreshing it first if it's about to expire.
+func (cts *clientTokenSource) Token() (*oauth2.Token, error) {
+	// Refresh the token if needed
+	if _, err := cts.client.AccessToken(cts.ctx); err != nil {
+		return nil, err
+	}
+
+	return toOAuth2Token(cts.client.Token()), nil
+}
+
+// oauth2RefreshMargin is how long before its expiry the token of an oauth2.TokenSource is refreshed. It's below the 10 seconds
+// before which an oauth2.Token deems itself expired, so the source has a new token by the time it's asked for one,
+// rather than handing back the current token on every request within the margin.
+const oauth2RefreshMargin = 5 * time.Second
+
+// oauth2Refresher is a struct that implements utils.TokenRefresher interface by asking an oauth2.TokenSource for the token.
+type oauth2Refresher struct {
+	tokenSource oauth2.TokenSource
+}
+
+// RefreshMargin returns oauth2RefreshMargin, as the utils.RefreshMarginer interface for the TokenManager of the client.
+func (otr *oauth2Refresher) RefreshMargin() time.Duration {
+	return oauth2RefreshMargin
+}
+
+// RefreshToken returns the token of the source. The given authToken is ignored and may be nil,
+// and so is the context, because the oauth2.TokenSource interface doesn't take one.
+// A source can't be forced to refresh a token it deems valid, e.g. one created by oauth2.ReuseTokenSource,
+// so a token rejected by the Spotify API may be returned again, in which case the request isn't replayed.
+func (otr *oauth2Refresher) RefreshToken(_ context.Context, _ *models.AuthToken) (*models.AuthToken, error) {
+	token, err := otr.tokenSource.Token()
+	if err != nil {
+		// Keep the error code of the token endpoint, so a revoked or expired refresh token matches ErrReauthRequired
+		var retrieveErr *oauth2.RetrieveError
+		if errors.As(err, &retrieveErr) && retrieveErr.ErrorCode != "" {
+			return nil, fromRetrieveError(retrieveErr)
+		}
+		return nil, utils.NewError(http.StatusInternalServerError, consts.MsgFailedToRefreshTokens, err)
+	}
+
+	return fromOAuth2Token(token), nil
+}
+
+// fromRetrieveError converts the error response of the token endpoint to an Error of AuthErrorType,
+// the same as the one of a refresh made by the SDK itself.
+func fromRetrieveError(retrieveErr *oauth2.RetrieveError) *utils.Error {
+	authError := &utils.AuthenticationError{Err: retrieveErr.ErrorCode, Description: retrieveErr.ErrorDescription}
+	e := &utils.Error{
+		Type:      utils.AuthErrorType,
+		Status:    http.StatusBadRequest,
+		Message:   consts.MsgFailedToRefreshTokens,
+		Reason:    retrieveErr.ErrorCode,
+		Err:       retrieveErr,
+		AuthError: authError,
+	}
+	if retrieveErr.ErrorDescription != "" {
+		e.Message = retrieveErr.ErrorDescription
+	}
+	if retrieveErr.Response != nil {
+		e.Status = retrieveErr.Response.StatusCode
+	}
+
+	return e
+}
+
+// toOAuth2Token converts an AuthToken to an oauth2.Token, the scope is kept as an extra field as in the token response.
+func toOAuth2Token(authToken *models.AuthToken) *oauth2.Token {
+	token := &oauth2.Token{
+		AccessToken:  authToken.AccessToken,
+		TokenType:    authToken.TokenType,
+		RefreshToken: authToken.RefreshToken,
+		Expiry:       authToken.ExpiryTime,
+	}
+
+	return token.WithExtra(map[string]any{"scope": authToken.Scope})
+}
+
+// fromOAuth2Token converts an oauth2.Token to an AuthToken. A token without an expiry is refreshed on every request,
+// which is cheap as the source returns it as is.
+func fromOAuth2Token(token *oauth2.Token) *models.AuthToken {
+	authToken := &models.AuthToken{
+		AccessToken:  token.AccessToken,
+		RefreshToken: token.RefreshToken,
+		TokenType:    token.TokenType,
+		ExpiryTime:   token.Expiry,
+	}
+	if scope, ok := token.Extra("scope").(string); ok {
+		authToken.Scope = scope
+	}
+	if !token.Expiry.IsZero() {
+		authToken.ExpiresIn = int(time.Until(token.Expiry).Seconds())
+	}
+
+	return authToken
+}
@@ -0,0 +1,72 @@
+package oauth2adapter
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alicse3/gospotify"
+	"golang.org/x/oauth2"
+)
+
+func TestWithTokenSource(t *testing.T) {
+	token := (&oauth2.Token{AccessToken: "access", TokenType: "Bearer", Expiry: time.Now().Add(time.Hour)}).
+		WithExtra(map[string]any{"scope": "user-read-email"})
+
+	client, err := gospotify.New(WithTokenSource(oauth2.StaticTokenSource(token)))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	authToken := client.Token()
+	if authToken.AccessToken != "access" || authToken.Scope != "user-read-email" || !authToken.ExpiryTime.Equal(token.Expiry) {
+		t.Errorf("Token() = %+v, want the token of the source", authToken)
+	}
+}
+
+func TestWithConfigRejectedRefreshRequiresReauth(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":"invalid_grant","error_description":"Refresh token revoked"}`))
+	}))
+	defer server.Close()
+
+	// The expired token makes the source refresh it right away
+	config := &oauth2.Config{ClientID: "id", Endpoint: oauth2.Endpoint{TokenURL: server.URL, AuthStyle: oauth2.AuthStyleInParams}}
+	expired := &oauth2.Token{AccessToken: "old", RefreshToken: "refresh", Expiry: time.Now().Add(-time.Minute)}
+
+	_, err := gospotify.New(WithConfig(context.Background(), config, expired))
+	if !errors.Is(err, gospotify.ErrReauthRequired) {
+		t.Fatalf("New() error = %v, want ErrReauthRequired", err)
+	}
+	var e *gospotify.Error
+	if !errors.As(err, &e) || e.Status != http.StatusBadRequest || e.Message != "Refresh token revoked" {
+		t.Errorf("New() error = %#v, want the error response of the token endpoint", err)
+	}
+}
+
+func TestRefresherMargin(t *testing.T) {
+	// Within the expiry delta of an oauth2.Token, so the source has a new token by then
+	if margin := (&oauth2Refresher{}).RefreshMargin(); margin <= 0 || margin >= 10*time.Second {
+		t.Errorf("RefreshMargin() = %v, want between 0 and 10 seconds", margin)
+	}
+}
+
+func TestTokenSource(t *testing.T) {
+	client, err := gospotify.New(gospotify.WithToken("access"))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	token, err := TokenSource(context.Background(), client).Token()
+	if err != nil {
+		t.Fatalf("Token() error = %v", err)
+	}
+	if token.AccessToken != "access" {
+		t.Errorf("AccessToken = %q, want access", token.AccessToken)
+	}
+}
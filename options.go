@@ -0,0 +1,280 @@
+package gospotify
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/alicse3/gospotify/models"
+	"github.com/alicse3/gospotify/utils"
+)
+
+// Option configures the Client created by New.
+type Option func(*options)
+
+// options holds the configuration collected from the Options.
+type options struct {
+	// HTTP layer
+	httpClient      *http.Client
+	transport       http.RoundTripper
+	timeout         time.Duration
+	userAgent       string
+	apiBaseUrl      string
+	accountsBaseUrl string
+	retryPolicy     *utils.RetryPolicy
+	logger          *slog.Logger
+	debug           bool
+	cache           utils.Cache
+	rateLimiter     *utils.RateLimiter
+
+	// Credentials and scopes of the login flow, set by WithCredentials for authorizing more scopes later
+	credentials CredentialsExchanger
+	scopes      []string
+
+	// Source of the first token and the refresher for it
+	tokenSource      tokenSource
+	tokenStore       utils.TokenStore
+	refreshMargin    *time.Duration
+	onReauthRequired func(err error)
+
+	// Login flow
+	stateGenerator utils.StateGenerator
+	httpServer     utils.HttpServer
+	browserOpener  utils.BrowserOpener
+	loginTimeout   time.Duration
+}
+
+// tokenSource obtains the first token of the client along with the refresher for it, which may be nil.
+type tokenSource func(ctx context.Context, o *options, accountsClient *utils.HttpClient) (*models.AuthToken, utils.TokenRefresher, error)
+
+// newHttpClient creates an HTTP client for the given base url with the transport level options applied.
+func (o *options) newHttpClient(baseUrl string, authToken *models.AuthToken, refresher utils.TokenRefresher) *utils.HttpClient {
+	httpClient := utils.NewHttpClientWithRefresher(baseUrl, authToken, refresher)
+
+	// Copy the given http.Client, so the other options don't modify it
+	if o.httpClient != nil {
+		client := *o.httpClient
+		httpClient.SetHttpClient(&client)
+	}
+	if o.transport != nil {
+		httpClient.SetTransport(o.transport)
+	}
+	if o.timeout > 0 {
+		httpClient.SetTimeout(o.timeout)
+	}
+	httpClient.SetUserAgent(o.userAgent)
+	httpClient.SetLogger(o.logger)
+	httpClient.SetDebug(o.debug)
+
+	return httpClient
+}
+
+// WithHttpClient sets the http.Client used for all the requests, e.g. to share a connection pool.
+// The client is copied, so WithTransport and WithTimeout don't modify it.
+func WithHttpClient(httpClient *http.Client) Option {
+	return func(o *options) {
+		o.httpClient = httpClient
+	}
+}
+
+// WithTransport sets the transport used for all the requests, e.g. for instrumenting them or for routing them to a local fake.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(o *options) {
+		o.transport = transport
+	}
+}
+
+// WithTimeout sets the timeout of every single attempt of a request, 10 seconds by default.
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.timeout = timeout
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with all the requests.
+func WithUserAgent(userAgent string) Option {
+	return func(o *options) {
+		o.userAgent = userAgent
+	}
+}
+
+// WithApiBaseUrl sets the base url of the Spotify Web API, e.g. to point the client at a local fake.
+func WithApiBaseUrl(baseUrl string) Option {
+	return func(o *options) {
+		o.apiBaseUrl = baseUrl
+	}
+}
+
+// WithAccountsBaseUrl sets the base url of the Spotify Accounts API, which is used for the authorization url and the token requests.
+func WithAccountsBaseUrl(baseUrl string) Option {
+	return func(o *options) {
+		o.accountsBaseUrl = baseUrl
+	}
+}
+
+// WithRetryPolicy sets the policy for retrying rate limited and failed requests, utils.DefaultRetryPolicy() by default.
+func WithRetryPolicy(retryPolicy utils.RetryPolicy) Option {
+	return func(o *options) {
+		o.retryPolicy = &retryPolicy
+	}
+}
+
+// WithLogger sets the logger for the requests. The client is silent by default.
+// Every request is logged at the info level with its method, url, status, latency, number of attempts and headers,
+// and every failure to send one at the error level. The query and the credential headers are never logged.
+func WithLogger(logger *slog.Logger) Option {
+	return func(o *options) {
+		o.logger = logger
+	}
+}
+
+// WithDebug enables dumping the whole requests and responses, bodies included, to the logger at the debug level.
+// The credential headers, tokens, secrets and codes are scrubbed from the dumps, but the other data of the user is not,
+// so it's meant for troubleshooting only.
+func WithDebug(debug bool) Option {
+	return func(o *options) {
+		o.debug = debug
+	}
+}
+
+// WithCache enables caching the responses of the Web API's GET requests in the given cache,
+// e.g. utils.NewMemoryCache(1000) or utils.NewDiskCache(dir). The responses are cached as told by their Cache-Control header,
+// the stale ones are revalidated with their ETag, and the responses of different users are kept apart.
+func WithCache(cache utils.Cache) Option {
+	return func(o *options) {
+		o.cache = cache
+	}
+}
+
+// WithRateLimiter paces all the requests of all the services with the given limiter, e.g. utils.NewRateLimiter(100, 30*time.Second, 10).
+// The limiter slows down after a 429 response and holds the requests back until its Retry-After delay has passed.
+// It may be shared by several clients, so they're paced together.
+func WithRateLimiter(rateLimiter *utils.RateLimiter) Option {
+	return func(o *options) {
+		o.rateLimiter = rateLimiter
+	}
+}
+
+// WithToken uses the given access token as is. It's never refreshed, so the client stops working once it expires.
+func WithToken(accessToken string) Option {
+	return WithAuthToken(&models.AuthToken{AccessToken: accessToken}, nil)
+}
+
+// WithAuthToken uses the given token, e.g. one obtained earlier, and refreshes it with the given refresher shortly before it expires.
+// The refresher may be nil, Credentials and PKCECredentials can be used as one.
+func WithAuthToken(authToken *models.AuthToken, refresher utils.TokenRefresher) Option {
+	return func(o *options) {
+		o.tokenSource = func(context.Context, *options, *utils.HttpClient) (*models.AuthToken, utils.TokenRefresher, error) {
+			return authToken, refresher, nil
+		}
+	}
+}
+
+// WithTokenRefresher obtains the first token from the given refresher, called with a nil token, and refreshes it with the same one,
+// e.g. for the adapter of an oauth2.TokenSource in the github.com/alicse3/gospotify/oauth2adapter module.
+func WithTokenRefresher(refresher utils.TokenRefresher) Option {
+	return func(o *options) {
+		o.tokenSource = func(ctx context.Context, _ *options, _ *utils.HttpClient) (*models.AuthToken, utils.TokenRefresher, error) {
+			// Obtain the first token
+			authToken, err := refresher.RefreshToken(ctx, nil)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			return authToken, refresher, nil
+		}
+	}
+}
+
+// WithCredentials obtains the token with the login flow of the given credentials, asking the user for the given scopes.
+// If the credentials also implement the utils.TokenRefresher interface, as Credentials and PKCECredentials do,
+// they're used for refreshing the token once it expires.
+// Combined with WithTokenStore, the login only runs if the store has no token with all the scopes yet.
+func WithCredentials(credentials CredentialsExchanger, scopes []string) Option {
+	return func(o *options) {
+		o.tokenSource = func(ctx context.Context, o *options, accountsClient *utils.HttpClient) (*models.AuthToken, utils.TokenRefresher, error) {
+			// Point the credentials at the configured Accounts API
+			if configurer, ok := credentials.(accountsConfigurer); ok {
+				configurer.setAccounts(o.accountsBaseUrl, accountsClient)
+			}
+
+			// Keep the credentials for Client.Authorize
+			o.credentials, o.scopes = credentials, scopes
+
+			authToken, err := loginWithStore(ctx, o, accountsClient, credentials, scopes)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			// Use the credentials for refreshing the tokens, if they support it
+			refresher, _ := credentials.(utils.TokenRefresher)
+			return authToken, refresher, nil
+		}
+	}
+}
+
+// WithClientCredentials obtains an app token with the Client Credentials flow, see NewClientWithClientCredentials.
+func WithClientCredentials(credentials Credentials) Option {
+	return func(o *options) {
+		o.tokenSource = func(ctx context.Context, _ *options, accountsClient *utils.HttpClient) (*models.AuthToken, utils.TokenRefresher, error) {
+			refresher := &utils.ClientCredentialsRefresher{ClientId: credentials.ClientId, ClientSecret: credentials.ClientSecret, HttpClient: accountsClient}
+
+			// Obtain the first app token
+			authToken, err := refresher.RefreshToken(ctx, nil)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			return authToken, refresher, nil
+		}
+	}
+}
+
+// WithTokenRefreshMargin sets how long before its expiry the access token is refreshed, utils.DefaultTokenRefreshMargin by default,
+// so the requests which are in flight at the boundary don't fail with a 401.
+func WithTokenRefreshMargin(margin time.Duration) Option {
+	return func(o *options) {
+		o.refreshMargin = &margin
+	}
+}
+
+// WithOnReauthRequired sets the function called when refreshing the token fails because the refresh token has been revoked
+// or has expired, so the user has to log in again. A long-running daemon may alert about it, or run the login flow again
+// and replace the token with Client.SetToken. The error matches ErrReauthRequired.
+func WithOnReauthRequired(onReauthRequired func(err error)) Option {
+	return func(o *options) {
+		o.onReauthRequired = onReauthRequired
+	}
+}
+
+// WithTokenStore sets the store the token is loaded from by WithCredentials, and saved to whenever it's obtained or refreshed.
+func WithTokenStore(tokenStore utils.TokenStore) Option {
+	return func(o *options) {
+		o.tokenStore = tokenStore
+	}
+}
+
+// WithLoginDependencies replaces the dependencies of the login flow of WithCredentials.
+// The nil ones keep their defaults.
+func WithLoginDependencies(stateGenerator utils.StateGenerator, httpServer utils.HttpServer, browserOpener utils.BrowserOpener) Option {
+	return func(o *options) {
+		if stateGenerator != nil {
+			o.stateGenerator = stateGenerator
+		}
+		if httpServer != nil {
+			o.httpServer = httpServer
+		}
+		if browserOpener != nil {
+			o.browserOpener = browserOpener
+		}
+	}
+}
+
+// WithLoginTimeout sets how long the login flow of WithCredentials waits for the user, DefaultLoginTimeout by default.
+// 0 disables the timeout, leaving only the context given to NewCtx.
+func WithLoginTimeout(loginTimeout time.Duration) Option {
+	return func(o *options) {
+		o.loginTimeout = loginTimeout
+	}
+}
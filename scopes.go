@@ -1,5 +1,7 @@
 package gospotify
 
+import "github.com/alicse3/gospotify/apis"
+
 // Scopes represent the permissions that a Spotify user grants to a third-party application.
 // These permissions determine the level of access the application has to the user's data.
 // For more details, visit https://developer.spotify.com/documentation/web-api/concepts/scopes
@@ -102,3 +104,12 @@ var (
 		// ScopeSoaCreatePartner,
 	}
 )
+
+// MinimalScopes returns the smallest set of scopes needed for calling all the given service methods, e.g.
+//
+//	scopes, err := gospotify.MinimalScopes("TrackService.SaveTracks", "PlaylistService.CreatePlaylist")
+//
+// The scopes which only add some data to the responses are left out. See apis.MethodScopes for the scopes of every method.
+func MinimalScopes(methods ...string) ([]string, error) {
+	return apis.MinimalScopes(methods...)
+}
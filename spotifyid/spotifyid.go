@@ -0,0 +1,227 @@
+// Package spotifyid parses and builds the identifiers of the Spotify resources:
+// base62 IDs (4iV5W9uYEdYUVa79Axb7Rh), URIs (spotify:track:4iV5W9uYEdYUVa79Axb7Rh)
+// and open.spotify.com links (https://open.spotify.com/intl-de/track/4iV5W9uYEdYUVa79Axb7Rh?si=...).
+// For details, visit: https://developer.spotify.com/documentation/web-api/concepts/spotify-uris-ids
+package spotifyid
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Errors returned by the parse functions, wrapped along with the offending input.
+var (
+	// The input is neither a base62 ID, nor a Spotify URI, nor an open.spotify.com link.
+	ErrInvalid = errors.New("spotifyid: invalid ID, URI or link")
+	// The input is valid, but it identifies a resource of an unknown type, e.g. spotify:genre:rock.
+	ErrUnknownType = errors.New("spotifyid: unknown type")
+	// The input identifies a resource of another type than the expected one.
+	ErrTypeMismatch = errors.New("spotifyid: type mismatch")
+)
+
+// Type is the type of a Spotify resource, as it appears in the URIs and links.
+type Type string
+
+// Types of the Spotify resources.
+const (
+	TypeTrack     Type = "track"
+	TypeEpisode   Type = "episode"
+	TypeAlbum     Type = "album"
+	TypePlaylist  Type = "playlist"
+	TypeShow      Type = "show"
+	TypeArtist    Type = "artist"
+	TypeUser      Type = "user"
+	TypeAudiobook Type = "audiobook"
+	TypeChapter   Type = "chapter"
+)
+
+// Valid reports whether the type is one of the known types.
+func (t Type) Valid() bool {
+	switch t {
+	case TypeTrack, TypeEpisode, TypeAlbum, TypePlaylist, TypeShow, TypeArtist, TypeUser, TypeAudiobook, TypeChapter:
+		return true
+	default:
+		return false
+	}
+}
+
+// ID is the Spotify ID of a resource, a base62 string of 22 characters, e.g. 4iV5W9uYEdYUVa79Axb7Rh.
+// The IDs of the users are their usernames instead.
+type ID string
+
+// String returns the ID as a string.
+func (id ID) String() string {
+	return string(id)
+}
+
+// URI is the Spotify URI of a resource, e.g. spotify:track:4iV5W9uYEdYUVa79Axb7Rh.
+type URI string
+
+// NewURI builds the URI of the resource with the given type and ID.
+func NewURI(t Type, id ID) URI {
+	return URI("spotify:" + string(t) + ":" + string(id))
+}
+
+// String returns the URI as a string.
+func (u URI) String() string {
+	return string(u)
+}
+
+// Type returns the type of the resource, or an empty Type if the URI is malformed.
+func (u URI) Type() Type {
+	t, _, _ := u.split()
+	return t
+}
+
+// ID returns the ID of the resource, or an empty ID if the URI is malformed.
+func (u URI) ID() ID {
+	_, id, _ := u.split()
+	return id
+}
+
+// URL returns the open.spotify.com link of the resource, or an empty string if the URI is malformed.
+func (u URI) URL() string {
+	t, id, ok := u.split()
+	if !ok {
+		return ""
+	}
+
+	return "https://open.spotify.com/" + string(t) + "/" + url.PathEscape(string(id))
+}
+
+// Valid reports whether the URI is well formed, with a known type and a valid ID.
+func (u URI) Valid() bool {
+	_, _, ok := u.split()
+	return ok
+}
+
+// split splits the URI into the type and the ID of the resource, reporting whether it's well formed.
+func (u URI) split() (Type, ID, bool) {
+	if !strings.HasPrefix(string(u), "spotify:") {
+		return "", "", false
+	}
+
+	t, id, err := parse(string(u))
+	return t, id, err == nil
+}
+
+// ParseURI parses a Spotify URI or an open.spotify.com link into a URI.
+// Base62 IDs are rejected, since they don't tell the type of the resource, see ParseID.
+func ParseURI(s string) (URI, error) {
+	t, id, err := parse(s)
+	if err != nil {
+		return "", err
+	}
+	if t == "" {
+		return "", fmt.Errorf("%w: %q has no type", ErrInvalid, s)
+	}
+
+	return NewURI(t, id), nil
+}
+
+// ParseID parses a base62 ID, a Spotify URI or an open.spotify.com link of a resource of the given type into an ID.
+// A URI or a link of another type is rejected with ErrTypeMismatch.
+func ParseID(t Type, s string) (ID, error) {
+	if !t.Valid() {
+		return "", fmt.Errorf("%w: %q", ErrUnknownType, t)
+	}
+
+	parsed, id, err := parse(s)
+	if err != nil {
+		return "", err
+	}
+
+	// A bare ID is of the expected type, but only the users have IDs which aren't base62
+	if parsed == "" && t == TypeUser {
+		parsed = TypeUser
+	}
+	if parsed == "" && !isBase62(string(id)) {
+		return "", fmt.Errorf("%w: %q", ErrInvalid, s)
+	}
+	if parsed != "" && parsed != t {
+		return "", fmt.Errorf("%w: %q is of type %s, not %s", ErrTypeMismatch, s, parsed, t)
+	}
+
+	return id, nil
+}
+
+// parse parses a base62 ID, a URI or a link into the type and the ID of the resource.
+// The type is empty for a bare ID, which is only checked for not being empty or malformed.
+func parse(s string) (Type, ID, error) {
+	s = strings.TrimSpace(s)
+
+	var segments []string
+	switch {
+	case strings.HasPrefix(s, "spotify:"):
+		segments = strings.Split(strings.TrimPrefix(s, "spotify:"), ":")
+	case strings.HasPrefix(s, "https://"), strings.HasPrefix(s, "http://"), strings.HasPrefix(s, "open.spotify.com/"):
+		var err error
+		if segments, err = linkSegments(s); err != nil {
+			return "", "", err
+		}
+	default:
+		// A bare ID
+		if s == "" || strings.ContainsAny(s, ":/?# ") {
+			return "", "", fmt.Errorf("%w: %q", ErrInvalid, s)
+		}
+		return "", ID(s), nil
+	}
+
+	// The legacy playlist URIs and links have the owner in them, e.g. spotify:user:owner:playlist:37i9dQZF1DXcBWIGoYBM5M
+	if len(segments) == 4 && segments[0] == string(TypeUser) && segments[2] == string(TypePlaylist) {
+		segments = segments[2:]
+	}
+	if len(segments) != 2 || segments[1] == "" {
+		return "", "", fmt.Errorf("%w: %q", ErrInvalid, s)
+	}
+
+	// Validate the type and the ID
+	t, id := Type(segments[0]), ID(segments[1])
+	if !t.Valid() {
+		return "", "", fmt.Errorf("%w: %q in %q", ErrUnknownType, t, s)
+	}
+	if t != TypeUser && !isBase62(string(id)) {
+		return "", "", fmt.Errorf("%w: %q", ErrInvalid, s)
+	}
+
+	return t, id, nil
+}
+
+// linkSegments returns the path segments of an open.spotify.com link which identify the resource,
+// without the locale and the embed prefixes. The query, e.g. ?si=..., is ignored.
+func linkSegments(s string) ([]string, error) {
+	if !strings.Contains(s, "://") {
+		s = "https://" + s
+	}
+
+	u, err := url.Parse(s)
+	if err != nil || (u.Hostname() != "open.spotify.com" && u.Hostname() != "play.spotify.com") {
+		return nil, fmt.Errorf("%w: %q", ErrInvalid, s)
+	}
+
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(segments) > 0 && strings.HasPrefix(segments[0], "intl-") {
+		segments = segments[1:]
+	}
+	if len(segments) > 0 && segments[0] == "embed" {
+		segments = segments[1:]
+	}
+
+	return segments, nil
+}
+
+// isBase62 reports whether s is a base62 ID of 22 characters.
+func isBase62(s string) bool {
+	if len(s) != 22 {
+		return false
+	}
+	for _, c := range s {
+		if !('0' <= c && c <= '9' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z') {
+			return false
+		}
+	}
+
+	return true
+}
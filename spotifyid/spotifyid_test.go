@@ -0,0 +1,112 @@
+package spotifyid
+
+import (
+	"errors"
+	"testing"
+)
+
+const trackId = "4iV5W9uYEdYUVa79Axb7Rh"
+
+func TestParseID(t *testing.T) {
+	tests := []struct {
+		name    string
+		t       Type
+		input   string
+		want    ID
+		wantErr error
+	}{
+		{name: "bare id", t: TypeTrack, input: trackId, want: trackId},
+		{name: "bare id with spaces", t: TypeTrack, input: "  " + trackId + "\n", want: trackId},
+		{name: "uri", t: TypeTrack, input: "spotify:track:" + trackId, want: trackId},
+		{name: "link", t: TypeTrack, input: "https://open.spotify.com/track/" + trackId, want: trackId},
+		{name: "link with si", t: TypeTrack, input: "https://open.spotify.com/track/" + trackId + "?si=abc123", want: trackId},
+		{name: "link with locale", t: TypeTrack, input: "https://open.spotify.com/intl-de/track/" + trackId, want: trackId},
+		{name: "embed link", t: TypeTrack, input: "https://open.spotify.com/embed/track/" + trackId, want: trackId},
+		{name: "link without scheme", t: TypeTrack, input: "open.spotify.com/track/" + trackId, want: trackId},
+		{name: "legacy playlist uri", t: TypePlaylist, input: "spotify:user:owner:playlist:37i9dQZF1DXcBWIGoYBM5M", want: "37i9dQZF1DXcBWIGoYBM5M"},
+		{name: "username", t: TypeUser, input: "smedjan", want: "smedjan"},
+		{name: "user uri", t: TypeUser, input: "spotify:user:smedjan", want: "smedjan"},
+		{name: "type mismatch", t: TypeTrack, input: "spotify:album:" + trackId, wantErr: ErrTypeMismatch},
+		{name: "link type mismatch", t: TypeTrack, input: "https://open.spotify.com/album/" + trackId, wantErr: ErrTypeMismatch},
+		{name: "unknown expected type", t: Type("genre"), input: trackId, wantErr: ErrUnknownType},
+		{name: "unknown type in uri", t: TypeTrack, input: "spotify:genre:" + trackId, wantErr: ErrUnknownType},
+		{name: "empty", t: TypeTrack, input: "", wantErr: ErrInvalid},
+		{name: "short id", t: TypeTrack, input: "4iV5W9uYEdYUVa79", wantErr: ErrInvalid},
+		{name: "non base62 id", t: TypeTrack, input: "4iV5W9uYEdYUVa79Axb7R-", wantErr: ErrInvalid},
+		{name: "other host", t: TypeTrack, input: "https://example.com/track/" + trackId, wantErr: ErrInvalid},
+		{name: "uri without id", t: TypeTrack, input: "spotify:track:", wantErr: ErrInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseID(tt.t, tt.input)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("ParseID(%s, %q) error = %v, want %v", tt.t, tt.input, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseID(%s, %q) error = %v", tt.t, tt.input, err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseID(%s, %q) = %q, want %q", tt.t, tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseURI(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    URI
+		wantErr error
+	}{
+		{name: "uri", input: "spotify:track:" + trackId, want: "spotify:track:" + trackId},
+		{name: "link", input: "https://open.spotify.com/intl-fr/album/" + trackId + "?si=x", want: "spotify:album:" + trackId},
+		{name: "legacy playlist link", input: "https://open.spotify.com/user/owner/playlist/" + trackId, want: "spotify:playlist:" + trackId},
+		{name: "bare id", input: trackId, wantErr: ErrInvalid},
+		{name: "unknown type", input: "spotify:genre:rock", wantErr: ErrUnknownType},
+		{name: "too many segments", input: "spotify:track:" + trackId + ":extra", wantErr: ErrInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseURI(tt.input)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("ParseURI(%q) error = %v, want %v", tt.input, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseURI(%q) error = %v", tt.input, err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseURI(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestURI(t *testing.T) {
+	uri := NewURI(TypeTrack, trackId)
+	if uri != "spotify:track:"+trackId {
+		t.Fatalf("NewURI() = %q", uri)
+	}
+	if !uri.Valid() || uri.Type() != TypeTrack || uri.ID() != trackId {
+		t.Errorf("URI %q: Valid() = %v, Type() = %q, ID() = %q", uri, uri.Valid(), uri.Type(), uri.ID())
+	}
+	if want := "https://open.spotify.com/track/" + trackId; uri.URL() != want {
+		t.Errorf("URL() = %q, want %q", uri.URL(), want)
+	}
+
+	// A link or a malformed URI isn't a URI
+	for _, malformed := range []URI{"https://open.spotify.com/track/" + trackId, "spotify:track", "spotify:genre:rock"} {
+		if malformed.Valid() || malformed.Type() != "" || malformed.ID() != "" || malformed.URL() != "" {
+			t.Errorf("malformed URI %q: Valid() = %v, Type() = %q, ID() = %q, URL() = %q",
+				malformed, malformed.Valid(), malformed.Type(), malformed.ID(), malformed.URL())
+		}
+	}
+}
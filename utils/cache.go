@@ -0,0 +1,333 @@
+package utils
+
+import (
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Cache interface defines the methods for storing the responses of the GET requests, see HttpClient.SetCache.
+// Get returns nil without an error if there is no response for the key.
+// The keys are derived from the user's token, so they never reveal it, and the responses of different users never mix.
+// Check MemoryCache and DiskCache structs for implementation details.
+type Cache interface {
+	Get(key string) (*CachedResponse, error)
+	Set(key string, response *CachedResponse) error
+}
+
+// CachedResponse is a successful response stored in a Cache.
+type CachedResponse struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+	// The response can be used without revalidating it until then, as told by its Cache-Control header
+	Expires time.Time
+}
+
+// Fresh reports whether the response can still be used without revalidating it.
+func (cr *CachedResponse) Fresh(now time.Time) bool {
+	return now.Before(cr.Expires)
+}
+
+// ETag returns the entity tag the response can be revalidated with, if any.
+func (cr *CachedResponse) ETag() string {
+	return cr.Header.Get("ETag")
+}
+
+// response creates an http.Response for the given request out of the cached response.
+func (cr *CachedResponse) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        strconv.Itoa(cr.StatusCode) + " " + http.StatusText(cr.StatusCode),
+		StatusCode:    cr.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        cr.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(cr.Body)),
+		ContentLength: int64(len(cr.Body)),
+		Request:       req,
+	}
+}
+
+// cacheControl holds the directives of a Cache-Control header which matter for a private cache.
+type cacheControl struct {
+	noStore bool
+	noCache bool
+	maxAge  time.Duration
+}
+
+// parseCacheControl parses the Cache-Control header, the unknown directives are ignored.
+func parseCacheControl(header http.Header) cacheControl {
+	var cc cacheControl
+	for _, directive := range strings.Split(header.Get("Cache-Control"), ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(directive), "=")
+		switch strings.ToLower(name) {
+		case "no-store":
+			cc.noStore = true
+		case "no-cache":
+			cc.noCache = true
+		case "max-age":
+			if seconds, err := strconv.Atoi(strings.Trim(value, `"`)); err == nil && seconds > 0 {
+				cc.maxAge = time.Duration(seconds) * time.Second
+			}
+		}
+	}
+
+	return cc
+}
+
+// expiry returns until when a response received at the given time is fresh, taking its Age header into account.
+func (cc cacheControl) expiry(header http.Header, received time.Time) time.Time {
+	if cc.noCache || cc.maxAge <= 0 {
+		return time.Time{}
+	}
+
+	age, _ := strconv.Atoi(header.Get("Age"))
+	return received.Add(cc.maxAge - time.Duration(age)*time.Second)
+}
+
+// MemoryCache is a struct that implements Cache interface by keeping the responses in memory.
+// Once full, the least recently used response is evicted. It's safe for concurrent use.
+type MemoryCache struct {
+	maxEntries int
+	entries    map[string]*list.Element
+	order      *list.List
+	mu         sync.Mutex
+}
+
+// memoryCacheEntry is an element of the MemoryCache's order list.
+type memoryCacheEntry struct {
+	key      string
+	response *CachedResponse
+}
+
+// NewMemoryCache creates a new empty MemoryCache holding at most maxEntries responses, 0 means there is no limit.
+func NewMemoryCache(maxEntries int) *MemoryCache {
+	return &MemoryCache{maxEntries: maxEntries, entries: make(map[string]*list.Element), order: list.New()}
+}
+
+// Get returns the response for the key, marking it as the most recently used one.
+func (mc *MemoryCache) Get(key string) (*CachedResponse, error) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	element, ok := mc.entries[key]
+	if !ok {
+		return nil, nil
+	}
+	mc.order.MoveToFront(element)
+
+	return element.Value.(*memoryCacheEntry).response, nil
+}
+
+// Set stores the response for the key, evicting the least recently used response if the cache is full.
+func (mc *MemoryCache) Set(key string, response *CachedResponse) error {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	if element, ok := mc.entries[key]; ok {
+		element.Value.(*memoryCacheEntry).response = response
+		mc.order.MoveToFront(element)
+		return nil
+	}
+
+	mc.entries[key] = mc.order.PushFront(&memoryCacheEntry{key, response})
+	if mc.maxEntries > 0 && mc.order.Len() > mc.maxEntries {
+		oldest := mc.order.Back()
+		mc.order.Remove(oldest)
+		delete(mc.entries, oldest.Value.(*memoryCacheEntry).key)
+	}
+
+	return nil
+}
+
+const (
+	// DefaultDiskCacheMaxAge is how long a DiskCache keeps a response which isn't used by default.
+	DefaultDiskCacheMaxAge = 7 * 24 * time.Hour
+	// diskCachePruneInterval is how often a DiskCache removes the responses over its limits at most.
+	diskCachePruneInterval = time.Minute
+)
+
+// DiskCache is a struct that implements Cache interface by keeping every response in a JSON file of a directory,
+// so the responses survive the restarts of an app. The files are only readable and writable by the current user,
+// since they hold the user's data. The responses which aren't used for longer than the max age are removed,
+// and so are the least recently used ones once there are more than the max entries. It's safe for concurrent use.
+type DiskCache struct {
+	dir        string
+	maxEntries int
+	maxAge     time.Duration
+	// When the files over the limits were last removed
+	pruned time.Time
+	mu     sync.Mutex
+}
+
+// NewDiskCache creates a new DiskCache which keeps the responses in the given directory, for DefaultDiskCacheMaxAge
+// and without a limit on their number. The directory is created on the first save if it doesn't exist.
+func NewDiskCache(dir string) *DiskCache {
+	return &DiskCache{dir: dir, maxAge: DefaultDiskCacheMaxAge}
+}
+
+// SetMaxEntries sets how many responses are kept at most, 0 means there is no limit.
+// The limit is enforced on saving a response, at most once a minute, so it may be exceeded meanwhile.
+func (dc *DiskCache) SetMaxEntries(maxEntries int) {
+	dc.mu.Lock()
+	defer dc.mu.Unlock()
+
+	dc.maxEntries = max(maxEntries, 0)
+}
+
+// SetMaxAge sets how long a response which isn't used is kept, 0 means it's kept for good.
+func (dc *DiskCache) SetMaxAge(maxAge time.Duration) {
+	dc.mu.Lock()
+	defer dc.mu.Unlock()
+
+	dc.maxAge = max(maxAge, 0)
+}
+
+// Get reads the response for the key from its file. It returns nil without an error if the file doesn't exist.
+func (dc *DiskCache) Get(key string) (*CachedResponse, error) {
+	path := dc.path(key)
+
+	// Read the file
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Ignore the response which is over the max age, it's removed on the next prune
+	now := time.Now()
+	if info, err := os.Stat(path); err == nil && dc.expired(info.ModTime(), now) {
+		return nil, nil
+	}
+
+	// Mark the response as used, its modification time is when it was last used
+	_ = os.Chtimes(path, now, now)
+
+	// Unmarshal the file data into CachedResponse struct
+	var response CachedResponse
+	if err := json.Unmarshal(data, &response); err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+// Set writes the response to a temporary file and then renames it, so the file never holds a partially written response.
+func (dc *DiskCache) Set(key string, response *CachedResponse) error {
+	// Marshal the response to JSON
+	data, err := json.Marshal(response)
+	if err != nil {
+		return err
+	}
+
+	// Make sure the directory exists
+	if err := os.MkdirAll(dc.dir, 0o700); err != nil {
+		return err
+	}
+
+	// Write the response to a temporary file, which is created with 0600 permissions
+	tmp, err := os.CreateTemp(dc.dir, "*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	// Replace the file of the key with the temporary file
+	if err := os.Rename(tmp.Name(), dc.path(key)); err != nil {
+		return err
+	}
+
+	// Remove the responses over the limits, if it's time to
+	return dc.prune(time.Now())
+}
+
+// expired reports whether a response last used at the given time is over the max age.
+func (dc *DiskCache) expired(used, now time.Time) bool {
+	dc.mu.Lock()
+	defer dc.mu.Unlock()
+
+	return dc.maxAge > 0 && now.Sub(used) > dc.maxAge
+}
+
+// prune removes the responses over the max age, and then the least recently used ones over the max entries.
+// It does nothing if it has run within diskCachePruneInterval.
+func (dc *DiskCache) prune(now time.Time) error {
+	dc.mu.Lock()
+	if now.Sub(dc.pruned) < diskCachePruneInterval {
+		dc.mu.Unlock()
+		return nil
+	}
+	dc.pruned = now
+	maxEntries, maxAge := dc.maxEntries, dc.maxAge
+	dc.mu.Unlock()
+
+	if maxEntries == 0 && maxAge == 0 {
+		return nil
+	}
+
+	// List the responses, skipping the temporary files
+	dirEntries, err := os.ReadDir(dc.dir)
+	if err != nil {
+		return err
+	}
+
+	type file struct {
+		path string
+		used time.Time
+	}
+	var files []file
+	for _, dirEntry := range dirEntries {
+		if dirEntry.IsDir() || filepath.Ext(dirEntry.Name()) != ".json" {
+			continue
+		}
+		info, err := dirEntry.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, file{filepath.Join(dc.dir, dirEntry.Name()), info.ModTime()})
+	}
+
+	// Sort them from the most recently used one
+	slices.SortFunc(files, func(a, b file) int { return b.used.Compare(a.used) })
+
+	var errs []error
+	for i, f := range files {
+		if (maxEntries > 0 && i >= maxEntries) || (maxAge > 0 && now.Sub(f.used) > maxAge) {
+			if err := os.Remove(f.path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// path returns the path of the file of the key, named after its hash so any key makes a valid file name.
+func (dc *DiskCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(dc.dir, hex.EncodeToString(sum[:])+".json")
+}
@@ -0,0 +1,222 @@
+package utils
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicse3/gospotify/models"
+)
+
+func TestMemoryCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	mc := NewMemoryCache(2)
+	mc.Set("a", &CachedResponse{Body: []byte("a")})
+	mc.Set("b", &CachedResponse{Body: []byte("b")})
+
+	// Using a makes b the least recently used one
+	if cached, _ := mc.Get("a"); cached == nil {
+		t.Fatal("Get(a) = nil, want the response")
+	}
+	mc.Set("c", &CachedResponse{Body: []byte("c")})
+
+	if cached, _ := mc.Get("b"); cached != nil {
+		t.Error("Get(b) returned the evicted response")
+	}
+	for _, key := range []string{"a", "c"} {
+		if cached, _ := mc.Get(key); cached == nil || string(cached.Body) != key {
+			t.Errorf("Get(%s) = %v, want the response", key, cached)
+		}
+	}
+}
+
+func TestDiskCacheRoundTrip(t *testing.T) {
+	dc := NewDiskCache(filepath.Join(t.TempDir(), "cache"))
+
+	if cached, err := dc.Get("missing"); cached != nil || err != nil {
+		t.Fatalf("Get(missing) = %v, %v, want nil without an error", cached, err)
+	}
+
+	response := &CachedResponse{StatusCode: http.StatusOK, Header: http.Header{"Etag": {`"v1"`}}, Body: []byte(`{"id":"1"}`)}
+	if err := dc.Set("key", response); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+
+	cached, err := dc.Get("key")
+	if err != nil || cached == nil {
+		t.Fatalf("Get() = %v, %v, want the response", cached, err)
+	}
+	if string(cached.Body) != string(response.Body) || cached.ETag() != `"v1"` {
+		t.Errorf("Get() = %+v, want %+v", cached, response)
+	}
+
+	// The file holds the user's data, so it's only readable by the current user
+	info, err := os.Stat(dc.path("key"))
+	if err != nil {
+		t.Fatalf("Stat() error = %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("file permissions = %o, want 600", perm)
+	}
+}
+
+func TestDiskCacheMaxAge(t *testing.T) {
+	dc := NewDiskCache(t.TempDir())
+	dc.SetMaxAge(time.Hour)
+	dc.Set("old", &CachedResponse{})
+	dc.Set("new", &CachedResponse{})
+
+	// The old response was last used two hours ago
+	now := time.Now()
+	old := now.Add(-2 * time.Hour)
+	if err := os.Chtimes(dc.path("old"), old, old); err != nil {
+		t.Fatalf("Chtimes() error = %v", err)
+	}
+
+	if cached, _ := dc.Get("old"); cached != nil {
+		t.Error("Get(old) returned the response over the max age")
+	}
+
+	// Pruning removes its file
+	if err := dc.prune(now.Add(diskCachePruneInterval)); err != nil {
+		t.Fatalf("prune() error = %v", err)
+	}
+	if _, err := os.Stat(dc.path("old")); !os.IsNotExist(err) {
+		t.Errorf("the file of the old response still exists: %v", err)
+	}
+	if cached, _ := dc.Get("new"); cached == nil {
+		t.Error("Get(new) = nil, want the response")
+	}
+}
+
+func TestDiskCacheMaxEntries(t *testing.T) {
+	dc := NewDiskCache(t.TempDir())
+	dc.SetMaxEntries(2)
+
+	// Store three responses used one after the other
+	now := time.Now()
+	for i, key := range []string{"a", "b", "c"} {
+		dc.Set(key, &CachedResponse{})
+		used := now.Add(time.Duration(i-3) * time.Minute)
+		if err := os.Chtimes(dc.path(key), used, used); err != nil {
+			t.Fatalf("Chtimes() error = %v", err)
+		}
+	}
+
+	// Pruning removes the least recently used one, the saves have just pruned already
+	if err := dc.prune(time.Now().Add(diskCachePruneInterval)); err != nil {
+		t.Fatalf("prune() error = %v", err)
+	}
+	if cached, _ := dc.Get("a"); cached != nil {
+		t.Error("Get(a) returned the least recently used response")
+	}
+	for _, key := range []string{"b", "c"} {
+		if cached, _ := dc.Get(key); cached == nil {
+			t.Errorf("Get(%s) = nil, want the response", key)
+		}
+	}
+}
+
+func TestHttpClientServesFreshResponsesFromCache(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Cache-Control", "private, max-age=60")
+		w.Write([]byte(`{"id":"1"}`))
+	}))
+	defer server.Close()
+
+	hc := NewHttpClient(server.URL)
+	hc.SetCache(NewMemoryCache(10))
+
+	for range 3 {
+		if body := get(t, hc, "/v1/albums/1"); body != `{"id":"1"}` {
+			t.Errorf("Get() body = %s, want the album", body)
+		}
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("%d requests, want 1", got)
+	}
+}
+
+func TestHttpClientRevalidatesStaleResponses(t *testing.T) {
+	var requests, notModified atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("ETag", `"v1"`)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Write([]byte(`{"id":"1"}`))
+	}))
+	defer server.Close()
+
+	hc := NewHttpClient(server.URL)
+	hc.SetCache(NewMemoryCache(10))
+
+	// The response has no max age, so it's revalidated every time and served from the cache on a 304
+	for range 2 {
+		if body := get(t, hc, "/v1/albums/1"); body != `{"id":"1"}` {
+			t.Errorf("Get() body = %s, want the album", body)
+		}
+	}
+	if requests.Load() != 2 || notModified.Load() != 1 {
+		t.Errorf("%d requests with %d 304s, want 2 with 1", requests.Load(), notModified.Load())
+	}
+}
+
+// refresherFunc is a TokenRefresher calling the function.
+type refresherFunc func(ctx context.Context, authToken *models.AuthToken) (*models.AuthToken, error)
+
+func (rf refresherFunc) RefreshToken(ctx context.Context, authToken *models.AuthToken) (*models.AuthToken, error) {
+	return rf(ctx, authToken)
+}
+
+func TestCacheKeyStableAcrossRefreshes(t *testing.T) {
+	// Every refresh rotates the refresh token
+	refresher := refresherFunc(func(_ context.Context, authToken *models.AuthToken) (*models.AuthToken, error) {
+		return &models.AuthToken{AccessToken: authToken.AccessToken + "+", RefreshToken: authToken.RefreshToken + "+", ExpiresIn: 3600}, nil
+	})
+	tm := NewTokenManager(&models.AuthToken{AccessToken: "access", RefreshToken: "refresh", ExpiryTime: time.Now().Add(time.Hour)}, refresher)
+	hc := NewHttpClient("https://api.spotify.com")
+	hc.SetTokenManager(tm)
+
+	req := httptest.NewRequest(http.MethodGet, "https://api.spotify.com/v1/me", nil)
+	key := hc.cacheKey(req)
+	if err := tm.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+	if got := hc.cacheKey(req); got != key {
+		t.Errorf("cacheKey() = %q after a refresh, want %q", got, key)
+	}
+
+	// Logging in again may be as another user
+	tm.SetToken(&models.AuthToken{AccessToken: "other", RefreshToken: "other", ExpiryTime: time.Now().Add(time.Hour)})
+	if got := hc.cacheKey(req); got == key {
+		t.Error("cacheKey() is the same for a new token, want a different one")
+	}
+}
+
+// get sends a GET request to the endpoint and returns the body of the response.
+func get(t *testing.T, hc *HttpClient, endpoint string) string {
+	t.Helper()
+
+	res, err := hc.Get(context.Background(), endpoint, nil)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		t.Fatalf("ReadAll() error = %v", err)
+	}
+	return string(body)
+}
@@ -1,15 +1,61 @@
 package utils
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/alicse3/gospotify/consts"
 )
 
+// Sentinel errors for checking an *Error with errors.Is, e.g. errors.Is(err, utils.ErrNotFound).
+var (
+	// The requested resource doesn't exist (404).
+	ErrNotFound = errors.New("not found")
+	// The access token is missing, invalid or expired (401).
+	ErrUnauthorized = errors.New("unauthorized")
+	// Too many requests have been sent (429).
+	ErrRateLimited = errors.New("rate limited")
+	// The request needs a Spotify Premium account (403 with the PREMIUM_REQUIRED reason).
+	ErrPremiumRequired = errors.New("premium required")
+	// The refresh token has been revoked or has expired, so the user has to log in again (invalid_grant).
+	ErrReauthRequired = errors.New("reauthorization required")
+	// The token hasn't been granted the scopes needed by the method, which is checked before calling the API.
+	ErrMissingScope = errors.New("missing scope")
+)
+
+// Reason codes of the player errors.
+// For details, visit: https://developer.spotify.com/documentation/web-api/concepts/api-calls#response-status-codes
+const (
+	ReasonNoPrevTrack           = "NO_PREV_TRACK"
+	ReasonNoNextTrack           = "NO_NEXT_TRACK"
+	ReasonNoSpecificTrack       = "NO_SPECIFIC_TRACK"
+	ReasonAlreadyPaused         = "ALREADY_PAUSED"
+	ReasonNotPaused             = "NOT_PAUSED"
+	ReasonNotPlayingLocally     = "NOT_PLAYING_LOCALLY"
+	ReasonNotPlayingTrack       = "NOT_PLAYING_TRACK"
+	ReasonNotPlayingContext     = "NOT_PLAYING_CONTEXT"
+	ReasonEndlessContext        = "ENDLESS_CONTEXT"
+	ReasonContextDisallow       = "CONTEXT_DISALLOW"
+	ReasonAlreadyPlaying        = "ALREADY_PLAYING"
+	ReasonRateLimited           = "RATE_LIMITED"
+	ReasonRemoteControlDisallow = "REMOTE_CONTROL_DISALLOW"
+	ReasonDeviceNotControllable = "DEVICE_NOT_CONTROLLABLE"
+	ReasonVolumeControlDisallow = "VOLUME_CONTROL_DISALLOW"
+	ReasonNoActiveDevice        = "NO_ACTIVE_DEVICE"
+	ReasonPremiumRequired       = "PREMIUM_REQUIRED"
+	ReasonUnknown               = "UNKNOWN"
+)
+
+// Error code of the Accounts API for a revoked or expired refresh token, or an invalid authorization code.
+// For details, visit: https://www.rfc-editor.org/rfc/rfc6749#section-5.2
+const ReasonInvalidGrant = "invalid_grant"
+
 // AuthenticationError represents the Spotify authentication error object.
 type AuthenticationError struct {
 	Err         string `json:"error"`
@@ -26,6 +72,7 @@ type RegularError struct {
 	Err struct {
 		Status  int    `json:"status"`
 		Message string `json:"message"`
+		Reason  string `json:"reason"`
 	} `json:"error"`
 }
 
@@ -34,85 +81,173 @@ func (re *RegularError) Error() string {
 	return fmt.Sprintf("Regular Error: %d - %s", re.Err.Status, re.Err.Message)
 }
 
-// AppError represents a custom error for the SDK.
-type AppError struct {
-	Status  int
-	Message string
-	Err     error
-}
-
-// Error returns the AppError message.
-func (ae *AppError) Error() string {
-	if ae.Err != nil {
-		return fmt.Sprintf("App Error: %d - %s - %v", ae.Status, ae.Message, ae.Err)
-	}
-	return fmt.Sprintf("App Error: %d - %s", ae.Status, ae.Message)
-}
-
 // ErrorType defines the different types of errors.
 type ErrorType int
 
 const (
+	// Error returned by the Accounts API
 	AuthErrorType ErrorType = iota
+	// Error returned by the Web API
 	RegErrorType
+	// Error raised by the SDK itself, e.g. an invalid input or a network failure
 	AppErrorType
 )
 
-// Error represents a unified error type.
+// Error is the single error type returned by the SDK.
+// It can be inspected with errors.As, and checked against ErrNotFound, ErrUnauthorized, ErrRateLimited,
+// ErrPremiumRequired, ErrReauthRequired and ErrMissingScope with errors.Is.
 type Error struct {
-	Type      ErrorType
+	Type ErrorType
+	// HTTP status of the response, or the status assigned by the SDK to its own errors
+	Status int
+	// Message of the Spotify error, or of the SDK
+	Message string
+	// Reason code of the Web API player errors, e.g. NO_ACTIVE_DEVICE, or the error code of the Accounts API errors, e.g. invalid_grant
+	Reason string
+	// Method and URL (without the query) of the request which failed, if any
+	Method string
+	URL    string
+	// Delay requested by the Retry-After header of a 429 response
+	RetryAfter time.Duration
+	// Number of attempts made before giving up, see RetryPolicy.
+	Attempts int
+	// Scopes needed by the method which haven't been granted to the token
+	MissingScopes []string
+	// Underlying cause, if any
+	Err error
+
+	// Raw error objects of the Spotify response
 	AuthError *AuthenticationError
 	RegError  *RegularError
-	AppError  *AppError
 }
 
-// Error returns the appropriate error message based on the ErrorType.
+// NewError creates an Error of AppErrorType with the given status, message and underlying cause, which may be nil.
+func NewError(status int, message string, err error) *Error {
+	return &Error{Type: AppErrorType, Status: status, Message: message, Err: err}
+}
+
+// WrapError is like NewError, but carries the request details of the underlying *Error over to the new one,
+// i.e. its Method, URL, RetryAfter and Attempts, so they're available on the Error found by errors.As.
+func WrapError(status int, message string, err error) *Error {
+	e := NewError(status, message, err)
+
+	var cause *Error
+	if errors.As(err, &cause) {
+		e.Method, e.URL, e.RetryAfter, e.Attempts = cause.Method, cause.URL, cause.RetryAfter, cause.Attempts
+	}
+
+	return e
+}
+
+// NewMissingScopeError creates an Error for a method whose needed scopes haven't been granted to the token, naming the missing ones.
+// It matches ErrMissingScope with errors.Is.
+func NewMissingScopeError(method string, missingScopes []string) *Error {
+	message := fmt.Sprintf("%s for %s: %s", consts.MsgMissingScopes, method, strings.Join(missingScopes, ", "))
+	return &Error{Type: AppErrorType, Status: http.StatusForbidden, Message: message, MissingScopes: missingScopes}
+}
+
+// Error returns the error message based on the ErrorType, along with the details which are set.
 func (e *Error) Error() string {
+	var sb strings.Builder
+
 	switch e.Type {
 	case AuthErrorType:
-		return e.AuthError.Error()
+		fmt.Fprintf(&sb, "Authentication Error: %d - %s - %s", e.Status, e.Reason, e.Message)
 	case RegErrorType:
-		return e.RegError.Error()
+		fmt.Fprintf(&sb, "Regular Error: %d - %s", e.Status, e.Message)
+		if e.Reason != "" {
+			fmt.Fprintf(&sb, " (%s)", e.Reason)
+		}
 	case AppErrorType:
-		return e.AppError.Error()
+		fmt.Fprintf(&sb, "App Error: %d - %s", e.Status, e.Message)
 	default:
 		return consts.MsgUknownErrorType
 	}
+
+	if e.Method != "" {
+		fmt.Fprintf(&sb, " - %s %s", e.Method, e.URL)
+	}
+	if e.Err != nil {
+		fmt.Fprintf(&sb, " - %v", e.Err)
+	}
+
+	return sb.String()
+}
+
+// Unwrap returns the underlying cause of the Error.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether the Error matches one of the sentinel errors.
+func (e *Error) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.Type != AppErrorType && e.Status == http.StatusNotFound
+	case ErrUnauthorized:
+		return e.Type != AppErrorType && e.Status == http.StatusUnauthorized
+	case ErrRateLimited:
+		return e.Type != AppErrorType && (e.Status == http.StatusTooManyRequests || e.Reason == ReasonRateLimited)
+	case ErrPremiumRequired:
+		return e.Reason == ReasonPremiumRequired
+	case ErrReauthRequired:
+		return e.Type == AuthErrorType && e.Reason == ReasonInvalidGrant
+	case ErrMissingScope:
+		return len(e.MissingScopes) > 0
+	default:
+		return false
+	}
 }
 
 // ParseSpotifyError parses the Spotify API error response into a unified Error type.
+// It always returns an error, the status text is used as the message if the body has no details.
 func ParseSpotifyError(res *http.Response, errorType ErrorType) error {
 	// Read response body
 	data, err := io.ReadAll(res.Body)
 	if err != nil {
-		return &Error{Type: AppErrorType, AppError: &AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToReadResponseBody, Err: err}}
+		return NewError(http.StatusInternalServerError, consts.MsgFailedToReadResponseBody, err)
 	}
 	defer res.Body.Close()
 
-	// Check Spotify API response
-	strData := string(data)
-	if strData == "" {
-		fmt.Println("Spotify API response is Empty")
-		// Ignore error parsing if response body is empty
-		return nil
-	} else {
-		// Print the response in case of any Spotify API errors
-		fmt.Printf("Spotify API response: %+v\n", strData)
+	// Describe the failed request, the status text is the message unless the body has a better one
+	e := &Error{Type: errorType, Status: res.StatusCode, Message: http.StatusText(res.StatusCode), Attempts: Attempts(res)}
+	if res.Request != nil {
+		u := *res.Request.URL
+		u.RawQuery = ""
+		e.Method, e.URL = res.Request.Method, u.String()
+	}
+	if res.StatusCode == http.StatusTooManyRequests {
+		e.RetryAfter, _ = parseRetryAfter(res.Header.Get("Retry-After"))
+	}
+
+	// An empty body or a non JSON one, e.g. from a gateway, has no details
+	if len(bytes.TrimSpace(data)) == 0 || !json.Valid(data) {
+		return e
 	}
 
 	// Handle Spotify errors
 	if errorType == AuthErrorType {
 		var authError AuthenticationError
 		if err := json.Unmarshal(data, &authError); err != nil {
-			return &Error{Type: AppErrorType, AppError: &AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToUnmarshalResponseData, Err: err}}
+			e.Err = err
+			return e
 		}
-		return &Error{Type: AuthErrorType, AuthError: &authError}
+		e.AuthError, e.Reason = &authError, authError.Err
+		if authError.Description != "" {
+			e.Message = authError.Description
+		}
+		return e
 	} else if errorType == RegErrorType {
 		var regError RegularError
 		if err := json.Unmarshal(data, &regError); err != nil {
-			return &Error{Type: AppErrorType, AppError: &AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToUnmarshalResponseData, Err: err}}
+			e.Err = err
+			return e
+		}
+		e.RegError, e.Reason = &regError, regError.Err.Reason
+		if regError.Err.Message != "" {
+			e.Message = regError.Err.Message
 		}
-		return &Error{Type: RegErrorType, RegError: &regError}
+		return e
 	} else {
 		return errors.ErrUnsupported
 	}
@@ -0,0 +1,223 @@
+package utils
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicse3/gospotify/consts"
+)
+
+// sentinels are all the sentinel errors an *Error may match.
+var sentinels = []error{ErrNotFound, ErrUnauthorized, ErrRateLimited, ErrPremiumRequired, ErrReauthRequired, ErrMissingScope}
+
+// spotifyResponse returns a response of the given status and body to a GET request of an album.
+func spotifyResponse(status int, header http.Header, body string) *http.Response {
+	return &http.Response{
+		StatusCode: status,
+		Header:     header,
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    httptest.NewRequest(http.MethodGet, "https://api.spotify.com/v1/albums/1?market=SE", nil),
+	}
+}
+
+func TestParseSpotifyError(t *testing.T) {
+	tests := []struct {
+		name           string
+		status         int
+		header         http.Header
+		body           string
+		errorType      ErrorType
+		wantIs         []error
+		wantReason     string
+		wantMessage    string
+		wantRetryAfter time.Duration
+	}{
+		{
+			name:        "not found",
+			status:      http.StatusNotFound,
+			body:        `{"error":{"status":404,"message":"Non existing id"}}`,
+			errorType:   RegErrorType,
+			wantIs:      []error{ErrNotFound},
+			wantMessage: "Non existing id",
+		},
+		{
+			name:        "unauthorized",
+			status:      http.StatusUnauthorized,
+			body:        `{"error":{"status":401,"message":"The access token expired"}}`,
+			errorType:   RegErrorType,
+			wantIs:      []error{ErrUnauthorized},
+			wantMessage: "The access token expired",
+		},
+		{
+			name:           "rate limited",
+			status:         http.StatusTooManyRequests,
+			header:         http.Header{"Retry-After": {"7"}},
+			errorType:      RegErrorType,
+			wantIs:         []error{ErrRateLimited},
+			wantMessage:    "Too Many Requests",
+			wantRetryAfter: 7 * time.Second,
+		},
+		{
+			name:        "rate limited reason",
+			status:      http.StatusForbidden,
+			body:        `{"error":{"status":403,"message":"Rate limited","reason":"RATE_LIMITED"}}`,
+			errorType:   RegErrorType,
+			wantIs:      []error{ErrRateLimited},
+			wantReason:  ReasonRateLimited,
+			wantMessage: "Rate limited",
+		},
+		{
+			name:        "premium required",
+			status:      http.StatusForbidden,
+			body:        `{"error":{"status":403,"message":"Player command failed: Premium required","reason":"PREMIUM_REQUIRED"}}`,
+			errorType:   RegErrorType,
+			wantIs:      []error{ErrPremiumRequired},
+			wantReason:  ReasonPremiumRequired,
+			wantMessage: "Player command failed: Premium required",
+		},
+		{
+			name:        "other player reason",
+			status:      http.StatusForbidden,
+			body:        `{"error":{"status":403,"message":"Player command failed: Restriction violated","reason":"UNKNOWN"}}`,
+			errorType:   RegErrorType,
+			wantReason:  ReasonUnknown,
+			wantMessage: "Player command failed: Restriction violated",
+		},
+		{
+			name:        "revoked refresh token",
+			status:      http.StatusBadRequest,
+			body:        `{"error":"invalid_grant","error_description":"Refresh token revoked"}`,
+			errorType:   AuthErrorType,
+			wantIs:      []error{ErrReauthRequired},
+			wantReason:  ReasonInvalidGrant,
+			wantMessage: "Refresh token revoked",
+		},
+		{
+			name:        "invalid grant from the Web API",
+			status:      http.StatusBadRequest,
+			body:        `{"error":{"status":400,"message":"Bad request","reason":"invalid_grant"}}`,
+			errorType:   RegErrorType,
+			wantReason:  ReasonInvalidGrant,
+			wantMessage: "Bad request",
+		},
+		{
+			name:        "gateway error without details",
+			status:      http.StatusBadGateway,
+			body:        `<html>Bad Gateway</html>`,
+			errorType:   RegErrorType,
+			wantMessage: "Bad Gateway",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ParseSpotifyError(spotifyResponse(tt.status, tt.header, tt.body), tt.errorType)
+
+			var e *Error
+			if !errors.As(err, &e) {
+				t.Fatalf("ParseSpotifyError() = %#v, want an *Error", err)
+			}
+			if e.Type != tt.errorType || e.Status != tt.status || e.Reason != tt.wantReason || e.Message != tt.wantMessage {
+				t.Errorf("ParseSpotifyError() = %+v, want the status %d, the reason %q and the message %q", e, tt.status, tt.wantReason, tt.wantMessage)
+			}
+			if e.RetryAfter != tt.wantRetryAfter {
+				t.Errorf("RetryAfter = %v, want %v", e.RetryAfter, tt.wantRetryAfter)
+			}
+			if e.Method != http.MethodGet || e.URL != "https://api.spotify.com/v1/albums/1" {
+				t.Errorf("request = %s %s, want the method and the url without the query", e.Method, e.URL)
+			}
+
+			for _, sentinel := range sentinels {
+				if want := slices.Contains(tt.wantIs, sentinel); errors.Is(err, sentinel) != want {
+					t.Errorf("errors.Is(%v) = %v, want %v", sentinel, !want, want)
+				}
+			}
+		})
+	}
+}
+
+func TestErrorIsForAppErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    *Error
+		wantIs []error
+	}{
+		// The status of the SDK's own errors says nothing about the API
+		{name: "not found status", err: NewError(http.StatusNotFound, consts.MsgFailedToGetAlbum, nil)},
+		{name: "unauthorized status", err: NewError(http.StatusUnauthorized, consts.MsgAuthTokenNotInitialised, nil)},
+		{name: "too many requests status", err: NewError(http.StatusTooManyRequests, consts.MsgFailedToGetAlbum, nil)},
+		{
+			name:   "missing scope",
+			err:    NewMissingScopeError("TrackService.SaveTracks", []string{"user-library-modify"}),
+			wantIs: []error{ErrMissingScope},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, sentinel := range sentinels {
+				if want := slices.Contains(tt.wantIs, sentinel); errors.Is(tt.err, sentinel) != want {
+					t.Errorf("errors.Is(%v) = %v, want %v", sentinel, !want, want)
+				}
+			}
+		})
+	}
+}
+
+func TestWrapError(t *testing.T) {
+	cause := ParseSpotifyError(spotifyResponse(http.StatusTooManyRequests, http.Header{"Retry-After": {"3"}}, ""), RegErrorType)
+	cause.(*Error).Attempts = 4
+
+	tests := []struct {
+		name  string
+		cause error
+		want  Error
+	}{
+		{
+			name:  "spotify error",
+			cause: cause,
+			want: Error{
+				Type: AppErrorType, Status: http.StatusInternalServerError, Message: consts.MsgFailedToGetAlbum,
+				Method: http.MethodGet, URL: "https://api.spotify.com/v1/albums/1", RetryAfter: 3 * time.Second, Attempts: 4,
+			},
+		},
+		{
+			name:  "other error",
+			cause: io.ErrUnexpectedEOF,
+			want:  Error{Type: AppErrorType, Status: http.StatusInternalServerError, Message: consts.MsgFailedToGetAlbum},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := error(WrapError(http.StatusInternalServerError, consts.MsgFailedToGetAlbum, tt.cause))
+
+			// errors.As finds the wrapping Error, with the request details of the cause
+			var e *Error
+			if !errors.As(err, &e) {
+				t.Fatalf("WrapError() = %#v, want an *Error", err)
+			}
+			if got := *e; got.Type != tt.want.Type || got.Status != tt.want.Status || got.Message != tt.want.Message || got.Method != tt.want.Method ||
+				got.URL != tt.want.URL || got.RetryAfter != tt.want.RetryAfter || got.Attempts != tt.want.Attempts {
+				t.Errorf("WrapError() = %+v, want %+v", got, tt.want)
+			}
+
+			// The cause is unwrapped, and so are the sentinel errors it matches
+			if errors.Unwrap(err) != tt.cause {
+				t.Errorf("Unwrap() = %v, want %v", errors.Unwrap(err), tt.cause)
+			}
+			if !errors.Is(err, tt.cause) {
+				t.Errorf("errors.Is(%v) = false, want true", tt.cause)
+			}
+			if want := tt.cause == cause; errors.Is(err, ErrRateLimited) != want {
+				t.Errorf("errors.Is(ErrRateLimited) = %v, want %v", !want, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package utils
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/alicse3/gospotify/consts"
+)
+
+// HeadlessBrowserOpener is a struct which implements BrowserOpener interface for the machines without a browser,
+// such as remote servers and CI runners. It prints the URL for the user to open it on any other device.
+type HeadlessBrowserOpener struct {
+	writer io.Writer
+}
+
+// NewHeadlessBrowserOpener creates a new HeadlessBrowserOpener which prints the URL to the given writer.
+func NewHeadlessBrowserOpener(writer io.Writer) *HeadlessBrowserOpener {
+	return &HeadlessBrowserOpener{writer}
+}
+
+// Open prints the URL along with the instructions for the user.
+func (hbo *HeadlessBrowserOpener) Open(url string) error {
+	_, err := fmt.Fprintf(hbo.writer, "%s\n\n%s\n\n%s\n", consts.MsgOpenUrlToLogIn, url, consts.MsgPasteRedirectUrl)
+	return err
+}
+
+// HeadlessHttpServer is a struct which implements HttpServer interface for the machines without a browser.
+// It accepts the callback on the wrapped server, if any, and the redirect url or the code pasted to the reader,
+// whichever comes first. The redirect url is the one the browser ends up on after the login,
+// even if its page fails to load because the local server isn't reachable from the browser.
+// A pasted bare code is accepted without a state check, see ParsePastedCallback.
+type HeadlessHttpServer struct {
+	server HttpServer
+	reader io.Reader
+	// For reporting that the wrapped server couldn't start
+	logger *slog.Logger
+	// Non-empty lines of the reader, read by a single goroutine shared by all the logins
+	lines      chan string
+	readerOnce sync.Once
+	// Lines taken from the lines channel by a login which got done meanwhile
+	pending []string
+	mu      sync.Mutex
+}
+
+// NewHeadlessHttpServer creates a new HeadlessHttpServer which reads the pasted input from the given reader, e.g. os.Stdin.
+// The server is used for accepting the callback as well, it may be nil to rely on the pasted input only.
+// Once the first login has started, the reader is claimed by the HeadlessHttpServer for good,
+// so it mustn't be read elsewhere, e.g. for prompting the user, as it would race with it for the lines.
+func NewHeadlessHttpServer(server HttpServer, reader io.Reader) *HeadlessHttpServer {
+	return &HeadlessHttpServer{server: server, reader: reader, logger: slog.Default(), lines: make(chan string)}
+}
+
+// SetLogger sets the logger which the failure to start the wrapped server is reported to, slog.Default() by default.
+func (hhs *HeadlessHttpServer) SetLogger(logger *slog.Logger) {
+	if logger == nil {
+		logger = slog.New(slog.DiscardHandler)
+	}
+	hhs.logger = logger
+}
+
+// StartServer starts the wrapped server, if any, and waits for the pasted input in the background.
+// If the wrapped server can't start, e.g. its port is in use or the redirect url's host isn't this machine,
+// the failure is logged and the login relies on the pasted input only.
+// The line pasted after a login is done, e.g. because it was completed by the callback, is kept for the next login.
+func (hhs *HeadlessHttpServer) StartServer(ctx context.Context, redirectUrl, state string, ch chan<- CallbackResult) (string, error) {
+	// Start the wrapped server to accept the callback as well
+	actualRedirectUrl := redirectUrl
+	if hhs.server != nil {
+		started, err := hhs.server.StartServer(ctx, redirectUrl, state, ch)
+		if err != nil {
+			hhs.logger.WarnContext(ctx, "callback server not started, waiting for the pasted redirect url only", "error", err)
+		} else {
+			actualRedirectUrl = started
+		}
+	}
+
+	// Reading from the reader can't be interrupted, so a single goroutine reads it for all the logins
+	hhs.readerOnce.Do(func() { go hhs.readLines() })
+
+	go func() {
+		// Take the next line, unless the login is done
+		line, ok := hhs.nextLine(ctx)
+		if !ok {
+			return
+		}
+
+		// Send the result through channel, unless nobody is waiting for it anymore
+		select {
+		case ch <- ParsePastedCallback(line, state):
+		case <-ctx.Done():
+		}
+	}()
+
+	return actualRedirectUrl, nil
+}
+
+// nextLine returns the line left over by a previous login, or waits for the next one until the context is done.
+func (hhs *HeadlessHttpServer) nextLine(ctx context.Context) (string, bool) {
+	hhs.mu.Lock()
+	if len(hhs.pending) > 0 {
+		line := hhs.pending[0]
+		hhs.pending = hhs.pending[1:]
+		hhs.mu.Unlock()
+		return line, true
+	}
+	hhs.mu.Unlock()
+
+	select {
+	case line, ok := <-hhs.lines:
+		if !ok {
+			return "", false
+		}
+
+		// Keep the line for the next login if this one got done meanwhile
+		if ctx.Err() != nil {
+			hhs.mu.Lock()
+			hhs.pending = append(hhs.pending, line)
+			hhs.mu.Unlock()
+			return "", false
+		}
+		return line, true
+	case <-ctx.Done():
+		return "", false
+	}
+}
+
+// readLines forwards the non-empty lines of the reader to the lines channel, until the reader is exhausted.
+func (hhs *HeadlessHttpServer) readLines() {
+	defer close(hhs.lines)
+
+	scanner := bufio.NewScanner(hhs.reader)
+	for scanner.Scan() {
+		// Skip the empty lines
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			hhs.lines <- line
+		}
+	}
+}
+
+// ParsePastedCallback extracts the authorization code from the input pasted by the user,
+// which is either the whole redirect url or the code alone.
+// The state of a redirect url is validated as for the callback request, see ParseCallback.
+// A bare code has no state to validate, so it skips the CSRF protection of the state: it's accepted on the grounds
+// that the user has copied it from their own browser. Paste the whole redirect url to have the state checked.
+func ParsePastedCallback(input, state string) CallbackResult {
+	// A bare code has no query
+	if !strings.Contains(input, "?") {
+		return CallbackResult{Code: input}
+	}
+
+	u, err := url.Parse(input)
+	if err != nil {
+		return CallbackResult{Err: NewError(http.StatusBadRequest, consts.MsgInvalidRedirectUrl, err)}
+	}
+
+	return ParseCallback(u.Query(), state)
+}
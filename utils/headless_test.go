@@ -0,0 +1,65 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// failingHttpServer is an HttpServer which can't start.
+type failingHttpServer struct{}
+
+func (failingHttpServer) StartServer(context.Context, string, string, chan<- CallbackResult) (string, error) {
+	return "", errors.New("address already in use")
+}
+
+func TestHeadlessHttpServerFallsBackToPastedInput(t *testing.T) {
+	hhs := NewHeadlessHttpServer(failingHttpServer{}, strings.NewReader("https://example.com/callback?code=code&state=state\n"))
+	hhs.SetLogger(nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ch := make(chan CallbackResult)
+	redirectUrl, err := hhs.StartServer(ctx, "https://example.com/callback", "state", ch)
+	if err != nil {
+		t.Fatalf("StartServer() error = %v, want the pasted input to be used", err)
+	}
+	if redirectUrl != "https://example.com/callback" {
+		t.Errorf("StartServer() = %q, want the given redirect url", redirectUrl)
+	}
+	if result := <-ch; result.Err != nil || result.Code != "code" {
+		t.Errorf("result = %+v, want the pasted code", result)
+	}
+}
+
+func TestDefaultHttpServerListenConfig(t *testing.T) {
+	tests := []struct {
+		redirectUrl string
+		wantAddr    string
+		wantPath    string
+		wantErr     bool
+	}{
+		{redirectUrl: "http://127.0.0.1:8888/callback", wantAddr: "127.0.0.1:8888", wantPath: "/callback"},
+		{redirectUrl: "http://localhost/cb", wantAddr: "localhost:80", wantPath: "/cb"},
+		{redirectUrl: "http://[::1]:0", wantAddr: "[::1]:0", wantPath: "/"},
+		{redirectUrl: "http://myhost.example.com:8080/callback", wantAddr: "myhost.example.com:8080", wantPath: "/callback"},
+		{redirectUrl: "http://myhost.example.com:0/callback", wantErr: true},
+		{redirectUrl: "ftp://127.0.0.1/callback", wantErr: true},
+		{redirectUrl: "", wantAddr: defaultCallbackAddr, wantPath: defaultCallbackPath},
+	}
+
+	for _, tt := range tests {
+		addr, path, _, err := (&DefaultHttpServer{}).listenConfig(tt.redirectUrl)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("listenConfig(%q) error = nil, want an error", tt.redirectUrl)
+			}
+			continue
+		}
+		if err != nil || addr != tt.wantAddr || path != tt.wantPath {
+			t.Errorf("listenConfig(%q) = %q, %q, %v, want %q, %q", tt.redirectUrl, addr, path, err, tt.wantAddr, tt.wantPath)
+		}
+	}
+}
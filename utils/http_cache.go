@@ -0,0 +1,113 @@
+package utils
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"time"
+)
+
+// SetCache sets the cache for the responses of the GET requests, which is disabled by default.
+// The responses are cached as told by their Cache-Control header, and the stale ones are revalidated with their ETag.
+func (hc *HttpClient) SetCache(cache Cache) {
+	hc.cache = cache
+}
+
+// doCached sends a GET request through the client's Cache, see SetCache.
+// A fresh cached response is returned without sending the request, and a stale one is revalidated with If-None-Match.
+// A failing cache is logged and treated as an empty one, it never fails the request.
+func (hc *HttpClient) doCached(req *http.Request) (*http.Response, error) {
+	if hc.cache == nil {
+		return hc.do(req)
+	}
+	ctx := req.Context()
+	key := hc.cacheKey(req)
+
+	// Look up the response
+	cached, err := hc.cache.Get(key)
+	if err != nil {
+		hc.logger.WarnContext(ctx, "failed to read spotify response from cache", "error", err)
+		cached = nil
+	}
+	if cached != nil && cached.Fresh(time.Now()) {
+		hc.logger.DebugContext(ctx, "spotify response served from cache", "method", req.Method, "url", req.URL.Path)
+		return cached.response(req), nil
+	}
+
+	// Revalidate the stale response, if it has an ETag
+	if cached != nil && cached.ETag() != "" {
+		req.Header.Set("If-None-Match", cached.ETag())
+	}
+
+	// Send the HTTP request
+	res, err := hc.do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	switch res.StatusCode {
+	case http.StatusNotModified:
+		// The cached response is still valid, only its freshness is updated
+		if cached == nil {
+			return res, nil
+		}
+		discard(res)
+
+		// Update a copy, since the cached one may be in use by the other requests
+		updated := *cached
+		updated.Header = cached.Header.Clone()
+		for _, name := range []string{"Cache-Control", "ETag"} {
+			if value := res.Header.Get(name); value != "" {
+				updated.Header.Set(name, value)
+			}
+		}
+		updated.Expires = parseCacheControl(res.Header).expiry(res.Header, time.Now())
+		hc.storeResponse(req, key, &updated)
+
+		return updated.response(req), nil
+	case http.StatusOK:
+		return hc.cacheResponse(req, key, res)
+	default:
+		return res, nil
+	}
+}
+
+// cacheResponse stores a successful response in the cache, unless its Cache-Control header forbids it
+// or it could never be used without revalidation and has no ETag to revalidate it with.
+func (hc *HttpClient) cacheResponse(req *http.Request, key string, res *http.Response) (*http.Response, error) {
+	cc := parseCacheControl(res.Header)
+	expires := cc.expiry(res.Header, time.Now())
+	if cc.noStore || (expires.IsZero() && res.Header.Get("ETag") == "") {
+		return res, nil
+	}
+
+	// Read the body, so it can be both stored and returned
+	body, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, sendError(req, Attempts(res), err)
+	}
+	res.Body = io.NopCloser(bytes.NewReader(body))
+
+	hc.storeResponse(req, key, &CachedResponse{StatusCode: res.StatusCode, Header: res.Header.Clone(), Body: body, Expires: expires})
+
+	return res, nil
+}
+
+// storeResponse stores the response in the cache, logging the failure to do so.
+func (hc *HttpClient) storeResponse(req *http.Request, key string, response *CachedResponse) {
+	if err := hc.cache.Set(key, response); err != nil {
+		hc.logger.WarnContext(req.Context(), "failed to write spotify response to cache", "error", err)
+	}
+}
+
+// cacheKey returns the cache key of a request, made of the hash identifying the user's token and the url of the request.
+// The hash stays the same across the refreshes of the token, even if Spotify rotates the refresh token.
+func (hc *HttpClient) cacheKey(req *http.Request) string {
+	var identity string
+	if hc.tokens != nil {
+		identity = hc.tokens.userIdentity()
+	}
+
+	return identity + " " + req.Method + " " + req.URL.String()
+}
@@ -3,13 +3,10 @@ package utils
 import (
 	"bytes"
 	"context"
-	"encoding/base64"
 	"encoding/json"
-	"fmt"
-	"io"
+	"log/slog"
 	"net/http"
 	"net/url"
-	"sync"
 	"time"
 
 	"github.com/alicse3/gospotify/consts"
@@ -28,12 +25,20 @@ type HttpClient struct {
 	client *http.Client
 	// Base url for api requests
 	baseUrl string
-	// Auth token for authenticating requests
-	authToken *models.AuthToken
-	// For refreshing the tokens
-	clientId, clientSecret string
-	// For synchronization
-	mu sync.Mutex
+	// Auth token for authenticating requests and the refresher for it, requests are sent without a token if it's nil
+	tokens *TokenManager
+	// Policy for retrying rate limited and failed requests
+	retryPolicy RetryPolicy
+	// User-Agent header sent with every request, the Go default is used if it's empty
+	userAgent string
+	// For logging the requests, silent by default
+	logger *slog.Logger
+	// Whether to dump the requests and responses at the debug level
+	debug bool
+	// For caching the responses of the GET requests, disabled if it's nil
+	cache Cache
+	// For pacing the requests, disabled if it's nil
+	rateLimiter *RateLimiter
 }
 
 // NewHttpClient returns a new HttpClient instance with a default timeout of 10 seconds.
@@ -42,113 +47,226 @@ func NewHttpClient(baseUrl string) *HttpClient {
 }
 
 // NewHttpClientWithToken creates an httpClient instance with the given dependencies.
+// The tokens are refreshed using the client id and client secret.
 func NewHttpClientWithToken(baseUrl string, authToken *models.AuthToken, clientId, clientSecret string) *HttpClient {
-	return &HttpClient{
-		client:       &http.Client{Timeout: defaultHttpClientTimeout},
-		baseUrl:      baseUrl,
-		authToken:    authToken,
-		clientId:     clientId,
-		clientSecret: clientSecret,
-	}
+	return NewHttpClientWithRefresher(baseUrl, authToken, &ClientSecretRefresher{ClientId: clientId, ClientSecret: clientSecret})
 }
 
-// refreshToken refreshes the access token using the refresh token.
-func (hc *HttpClient) refreshToken() error {
-	// To make sure the dependencies are not empty before refreshing the tokens
-	if hc.clientId == "" {
-		return &Error{Type: AppErrorType, AppError: &AppError{Status: http.StatusInternalServerError, Message: consts.MsgEmptyClientId}}
-	}
-	if hc.clientSecret == "" {
-		return &Error{Type: AppErrorType, AppError: &AppError{Status: http.StatusInternalServerError, Message: consts.MsgEmptyClientSecret}}
+// NewHttpClientWithRefresher creates an httpClient instance which refreshes the tokens using the given TokenRefresher.
+// If refresher is nil, the tokens are never refreshed.
+func NewHttpClientWithRefresher(baseUrl string, authToken *models.AuthToken, refresher TokenRefresher) *HttpClient {
+	hc := &HttpClient{
+		client:      &http.Client{Timeout: defaultHttpClientTimeout},
+		baseUrl:     baseUrl,
+		retryPolicy: DefaultRetryPolicy(),
+		logger:      slog.New(slog.DiscardHandler),
 	}
-	if hc.authToken == nil {
-		return &Error{Type: AppErrorType, AppError: &AppError{Status: http.StatusInternalServerError, Message: consts.MsgAuthTokenNotInitialised}}
+	if authToken != nil {
+		hc.tokens = NewTokenManager(authToken, refresher)
 	}
 
-	// Generating base64 endoded(client id and client secret) string for authorization.
-	// For details, visit: https://developer.spotify.com/documentation/web-api/tutorials/refreshing-tokens
-	base64Encoded := base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%s:%s", hc.clientId, hc.clientSecret)))
+	return hc
+}
 
-	// Set the required headers
-	headers := map[string]string{
-		"Content-Type":  "application/x-www-form-urlencoded",
-		"Authorization": "Basic " + base64Encoded, // As per the Spotify document, this is only required for the Authorization Code
-	}
+// SetHttpClient replaces the underlying http.Client, e.g. to share a connection pool or to use a custom transport.
+func (hc *HttpClient) SetHttpClient(client *http.Client) {
+	hc.client = client
+}
 
-	// Set the form values for the token refresh request
-	formValues := map[string]string{
-		"grant_type":    "refresh_token",
-		"refresh_token": hc.authToken.RefreshToken,
-	}
+// SetTransport replaces the transport of the underlying http.Client, e.g. to route the requests to a local fake.
+func (hc *HttpClient) SetTransport(transport http.RoundTripper) {
+	hc.client.Transport = transport
+}
 
-	// Make a POST request to the token endpoint
-	res, err := hc.Post(context.Background(), consts.EndpointToken, headers, nil, formValues, nil)
-	if err != nil {
-		return &AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToRefreshTokens, Err: err}
-	}
+// SetTimeout replaces the timeout of the underlying http.Client, which bounds every single attempt of a request.
+func (hc *HttpClient) SetTimeout(timeout time.Duration) {
+	hc.client.Timeout = timeout
+}
 
-	// Handle Spotify API error
-	if res.StatusCode != http.StatusOK {
-		return ParseSpotifyError(res, AuthErrorType)
-	}
+// SetUserAgent sets the User-Agent header sent with every request.
+func (hc *HttpClient) SetUserAgent(userAgent string) {
+	hc.userAgent = userAgent
+}
 
-	// Read the response body
-	data, err := io.ReadAll(res.Body)
-	if err != nil {
-		return &AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToReadResponseBody, Err: err}
-	}
-	defer res.Body.Close()
+// SetDebug enables or disables dumping the whole requests and responses, bodies included, to the logger at the debug level.
+// The credential headers, tokens, secrets and codes are scrubbed from the dumps, but the other data of the user is not.
+func (hc *HttpClient) SetDebug(debug bool) {
+	hc.debug = debug
+}
 
-	// Unmarshal the response data into AuthToken struct
-	var authToken models.AuthToken
-	if err := json.Unmarshal(data, &authToken); err != nil {
-		return &AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToUnmarshalResponseData, Err: err}
+// SetLogger sets the logger for the requests. A nil logger makes the client silent again.
+func (hc *HttpClient) SetLogger(logger *slog.Logger) {
+	if logger == nil {
+		logger = slog.New(slog.DiscardHandler)
 	}
+	hc.logger = logger
+}
 
-	// Update the client's authToken except the refreshToken
-	// Not updating the refresh token, because the Spotify's refresh tokens do not expire by default unless they are explicitly revoked by the user or by Spotify.
-	hc.authToken.AccessToken = authToken.AccessToken
-	hc.authToken.TokenType = authToken.TokenType
-	hc.authToken.ExpiresIn = authToken.ExpiresIn
-	hc.authToken.Scope = authToken.Scope
-	hc.authToken.SetExpiryTime()
-
-	return nil
+// SetRetryPolicy replaces the policy used for retrying rate limited and failed requests.
+func (hc *HttpClient) SetRetryPolicy(retryPolicy RetryPolicy) {
+	hc.retryPolicy = retryPolicy
 }
 
-// checkAndRefreshTokens checks for the AuthToken expiry and then triggers refresh tokens call if needed.
-func (hc *HttpClient) checkAndRefreshTokens() error {
-	hc.mu.Lock()
-	defer hc.mu.Unlock()
+// SetRateLimiter sets the limiter pacing every attempt of every request, which is disabled by default.
+// The same RateLimiter may be shared by several clients, so they're paced together.
+func (hc *HttpClient) SetRateLimiter(rateLimiter *RateLimiter) {
+	hc.rateLimiter = rateLimiter
+}
 
-	// Check if the token has expired
-	if hc.authToken.IsExpired() {
-		// Refresh the token
-		if err := hc.refreshToken(); err != nil {
-			return err
-		}
+// SetTokenStore sets the store which every refreshed token is saved to. It has no effect on a client without a token.
+func (hc *HttpClient) SetTokenStore(tokenStore TokenStore) {
+	if hc.tokens != nil {
+		hc.tokens.SetTokenStore(tokenStore)
 	}
+}
+
+// SetTokenManager replaces the manager of the token the requests are authenticated with, e.g. to share it between clients.
+// A nil manager makes the client send the requests without a token.
+func (hc *HttpClient) SetTokenManager(tokens *TokenManager) {
+	hc.tokens = tokens
+}
 
-	return nil
+// TokenManager returns the manager of the token the requests are authenticated with, or nil if there is none.
+func (hc *HttpClient) TokenManager() *TokenManager {
+	return hc.tokens
 }
 
-// do sends an HTTP request and automatically handles token expiration.
+// do sends an HTTP request and logs its outcome, see send.
 func (hc *HttpClient) do(req *http.Request) (*http.Response, error) {
-	// If auth token is set, check and refresh the token if needed
-	if hc.authToken != nil {
-		if err := hc.checkAndRefreshTokens(); err != nil {
-			return nil, err
+	start := time.Now()
+	res, err := hc.send(req)
+	hc.logRequest(req.Context(), req, res, err, time.Since(start))
+
+	return res, err
+}
+
+// send sends an HTTP request and automatically handles token expiration.
+// A request rejected with a 401 is replayed once with a refreshed token, unless refreshing hands back the same token.
+// The request's context is also used for refreshing the tokens.
+// Rate limited and failed requests are retried according to the client's RetryPolicy.
+// Returned errors are of type *Error, carrying the number of attempts made.
+func (hc *HttpClient) send(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	retryable := hc.retryPolicy.canRetry(req.Method)
+	sent, replayed := false, false
+
+	for attempt := 1; ; attempt++ {
+		// Tag the request with the attempt number, so it can be read back from the response
+		attemptReq := req.Clone(context.WithValue(ctx, attemptsKey{}, attempt))
+
+		// Rewind the request body for every retry and replay
+		if sent && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, sendError(req, attempt, err)
+			}
+			attemptReq.Body = body
+		}
+
+		// If auth token is set, refresh it if needed and add it to the Authorization header
+		var accessToken string
+		if hc.tokens != nil {
+			var err error
+			if accessToken, err = hc.tokens.AccessToken(ctx); err != nil {
+				return nil, sendError(req, attempt, err)
+			}
+			attemptReq.Header.Set("Authorization", "Bearer "+accessToken)
+		}
+
+		// Identify the app, if configured
+		if hc.userAgent != "" {
+			attemptReq.Header.Set("User-Agent", hc.userAgent)
+		}
+
+		// Wait for the rate limiter, if configured
+		if hc.rateLimiter != nil {
+			if err := hc.rateLimiter.Wait(ctx); err != nil {
+				return nil, sendError(req, attempt, err)
+			}
+		}
+
+		// Send the request
+		if hc.debug {
+			hc.dumpRequest(ctx, attemptReq)
+		}
+		res, err := hc.client.Do(attemptReq)
+		sent = true
+		if hc.debug && res != nil {
+			hc.dumpResponse(ctx, res)
+		}
+		if hc.rateLimiter != nil {
+			hc.rateLimiter.observe(res)
+		}
+
+		// The access token has expired or has been revoked, refresh it and replay the request once
+		if res != nil && res.StatusCode == http.StatusUnauthorized && !replayed && hc.tokens != nil && hc.tokens.canRefresh() {
+			replayed = true
+
+			hc.logger.DebugContext(ctx, "refreshing rejected access token", "method", req.Method, "url", req.URL.Redacted())
+			if err := hc.tokens.refreshRejected(ctx, accessToken); err != nil {
+				discard(res)
+				return nil, sendError(req, attempt, err)
+			}
+
+			// Replay the request, unless the refresher has handed back the rejected token, e.g. an oauth2.TokenSource
+			// which deems it still valid, so the 401 response is handled as any other one
+			// The replayed flag bounds the replays to one per request, so the replay doesn't count as an attempt
+			if hc.tokens.Token().AccessToken != accessToken {
+				discard(res)
+				attempt--
+				continue
+			}
+		}
+
+		// Decide whether to give up or to wait for the next attempt
+		wait, retry := hc.retryPolicy.delay(attempt, res, err)
+		if !retryable || !retry || ctx.Err() != nil {
+			if err != nil {
+				return nil, sendError(req, attempt, err)
+			}
+			return res, nil
+		}
+		if res != nil {
+			discard(res)
+		}
+
+		hc.logger.DebugContext(ctx, "retrying request", "method", req.Method, "url", req.URL.Redacted(), "attempt", attempt, "wait", wait)
+		if err := sleep(ctx, wait); err != nil {
+			return nil, sendError(req, attempt, err)
 		}
+	}
+}
 
-		// Add the access token to the Authorization header
-		req.Header.Set("Authorization", "Bearer "+hc.authToken.AccessToken)
+// sendError creates the Error for a request which couldn't be sent after the given number of attempts.
+// The query is stripped from the urls of the request and of the underlying *url.Error, as it may carry secrets.
+func sendError(req *http.Request, attempts int, err error) *Error {
+	u := *req.URL
+	u.RawQuery = ""
+
+	if urlErr, ok := err.(*url.Error); ok {
+		stripped := *urlErr
+		if parsed, parseErr := url.Parse(urlErr.URL); parseErr == nil {
+			parsed.RawQuery = ""
+			stripped.URL = parsed.String()
+		} else {
+			stripped.URL = u.String()
+		}
+		err = &stripped
 	}
 
-	// Send the request
-	return hc.client.Do(req)
+	return &Error{
+		Type:     AppErrorType,
+		Status:   http.StatusInternalServerError,
+		Message:  consts.MsgFailedToSendRequest,
+		Method:   req.Method,
+		URL:      u.String(),
+		Attempts: attempts,
+		Err:      err,
+	}
 }
 
 // Post makes an HTTP POST request to the specified endpoint with optional headers, query params, form values, and request body.
+// The form values are sent URL-encoded as the body in place of the request body, the Content-Type header is up to the caller.
 // It returns the HTTP response and any error that occurred during the request.
 func (hc *HttpClient) Post(ctx context.Context, endpoint string, headers, queryParams, formValues map[string]string, body any) (*http.Response, error) {
 	// Construct full url
@@ -157,7 +275,7 @@ func (hc *HttpClient) Post(ctx context.Context, endpoint string, headers, queryP
 	// Parse the URL and handle any errors
 	u, err := url.ParseRequestURI(fullUrl)
 	if err != nil {
-		return nil, &Error{Type: AppErrorType, AppError: &AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToParseUrl, Err: err}}
+		return nil, NewError(http.StatusInternalServerError, consts.MsgFailedToParseUrl, err)
 	}
 
 	// Marshal the request body (if provided) to JSON
@@ -165,24 +283,24 @@ func (hc *HttpClient) Post(ctx context.Context, endpoint string, headers, queryP
 	if body != nil {
 		data, err := json.Marshal(body)
 		if err != nil {
-			return nil, &Error{Type: AppErrorType, AppError: &AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToMarshalRequestData, Err: err}}
+			return nil, NewError(http.StatusInternalServerError, consts.MsgFailedToMarshalRequestData, err)
 		}
 		jsonData = data
 	}
 
-	// Encode the form values (if provided) and append them to the URL
+	// Encode the form values (if provided) as the body, so the tokens, secrets and codes never end up in the URL
 	if formValues != nil {
 		values := url.Values{}
 		for key, val := range formValues {
 			values.Add(key, val)
 		}
-		u.RawQuery = values.Encode()
+		jsonData = []byte(values.Encode())
 	}
 
 	// Create a new HTTP request with the provided context, method, URL, and request body
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), bytes.NewBuffer(jsonData))
 	if err != nil {
-		return nil, &Error{Type: AppErrorType, AppError: &AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToCreatePostRequest, Err: err}}
+		return nil, NewError(http.StatusInternalServerError, consts.MsgFailedToCreatePostRequest, err)
 	}
 
 	// Set the request headers (if provided)
@@ -202,7 +320,7 @@ func (hc *HttpClient) Post(ctx context.Context, endpoint string, headers, queryP
 	// Send the HTTP request and return the response and any error that occurred
 	res, err := hc.do(req)
 	if err != nil {
-		return nil, &Error{Type: AppErrorType, AppError: &AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToSendRequest, Err: err}}
+		return nil, err
 	}
 
 	// Return the Response
@@ -218,13 +336,13 @@ func (hc *HttpClient) Get(ctx context.Context, endpoint string, queryParams map[
 	// Parse the URL and handle any errors
 	u, err := url.ParseRequestURI(fullUrl)
 	if err != nil {
-		return nil, &Error{Type: AppErrorType, AppError: &AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToParseUrl, Err: err}}
+		return nil, NewError(http.StatusInternalServerError, consts.MsgFailedToParseUrl, err)
 	}
 
 	// Create a new HTTP request with the provided context, method, and request URL
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
 	if err != nil {
-		return nil, &Error{Type: AppErrorType, AppError: &AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToCreateGetRequest, Err: err}}
+		return nil, NewError(http.StatusInternalServerError, consts.MsgFailedToCreateGetRequest, err)
 	}
 
 	// Set the query params in the request
@@ -236,10 +354,10 @@ func (hc *HttpClient) Get(ctx context.Context, endpoint string, queryParams map[
 		req.URL.RawQuery = query.Encode()
 	}
 
-	// Send the HTTP request and return the response and any error that occurred
-	res, err := hc.do(req)
+	// Send the HTTP request through the cache and return the response and any error that occurred
+	res, err := hc.doCached(req)
 	if err != nil {
-		return nil, &Error{Type: AppErrorType, AppError: &AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToSendRequest, Err: err}}
+		return nil, err
 	}
 
 	// Return the Response
@@ -255,7 +373,7 @@ func (hc *HttpClient) Put(ctx context.Context, endpoint string, headers, queryPa
 	// Parse the URL and handle any errors
 	u, err := url.ParseRequestURI(fullUrl)
 	if err != nil {
-		return nil, &Error{Type: AppErrorType, AppError: &AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToParseUrl, Err: err}}
+		return nil, NewError(http.StatusInternalServerError, consts.MsgFailedToParseUrl, err)
 	}
 
 	// Marshal the request body (if provided) to JSON
@@ -263,7 +381,7 @@ func (hc *HttpClient) Put(ctx context.Context, endpoint string, headers, queryPa
 	if body != nil {
 		data, err := json.Marshal(body)
 		if err != nil {
-			return nil, &Error{Type: AppErrorType, AppError: &AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToMarshalRequestData, Err: err}}
+			return nil, NewError(http.StatusInternalServerError, consts.MsgFailedToMarshalRequestData, err)
 		}
 		jsonData = data
 	}
@@ -271,7 +389,7 @@ func (hc *HttpClient) Put(ctx context.Context, endpoint string, headers, queryPa
 	// Create a new HTTP request with the provided context, method, URL, and request body
 	req, err := http.NewRequestWithContext(ctx, http.MethodPut, u.String(), bytes.NewBuffer(jsonData))
 	if err != nil {
-		return nil, &Error{Type: AppErrorType, AppError: &AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToCreatePutRequest, Err: err}}
+		return nil, NewError(http.StatusInternalServerError, consts.MsgFailedToCreatePutRequest, err)
 	}
 
 	// Set the request headers (if provided)
@@ -291,7 +409,7 @@ func (hc *HttpClient) Put(ctx context.Context, endpoint string, headers, queryPa
 	// Send the HTTP request and return the response and any error that occurred
 	res, err := hc.do(req)
 	if err != nil {
-		return nil, &Error{Type: AppErrorType, AppError: &AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToSendRequest, Err: err}}
+		return nil, err
 	}
 
 	// Return the Response
@@ -307,7 +425,7 @@ func (hc *HttpClient) Delete(ctx context.Context, endpoint string, headers, quer
 	// Parse the URL and handle any errors
 	u, err := url.ParseRequestURI(fullUrl)
 	if err != nil {
-		return nil, &Error{Type: AppErrorType, AppError: &AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToParseUrl, Err: err}}
+		return nil, NewError(http.StatusInternalServerError, consts.MsgFailedToParseUrl, err)
 	}
 
 	// Marshal the request body (if provided) to JSON
@@ -315,7 +433,7 @@ func (hc *HttpClient) Delete(ctx context.Context, endpoint string, headers, quer
 	if body != nil {
 		data, err := json.Marshal(body)
 		if err != nil {
-			return nil, &Error{Type: AppErrorType, AppError: &AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToMarshalRequestData, Err: err}}
+			return nil, NewError(http.StatusInternalServerError, consts.MsgFailedToMarshalRequestData, err)
 		}
 		jsonData = data
 	}
@@ -323,7 +441,7 @@ func (hc *HttpClient) Delete(ctx context.Context, endpoint string, headers, quer
 	// Create a new HTTP request with the provided context, method, URL, and request body
 	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, u.String(), bytes.NewBuffer(jsonData))
 	if err != nil {
-		return nil, &Error{Type: AppErrorType, AppError: &AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToCreateDeleteRequest, Err: err}}
+		return nil, NewError(http.StatusInternalServerError, consts.MsgFailedToCreateDeleteRequest, err)
 	}
 
 	// Set the request headers (if provided)
@@ -343,7 +461,7 @@ func (hc *HttpClient) Delete(ctx context.Context, endpoint string, headers, quer
 	// Send the HTTP request and return the response and any error that occurred
 	res, err := hc.do(req)
 	if err != nil {
-		return nil, &Error{Type: AppErrorType, AppError: &AppError{Status: http.StatusInternalServerError, Message: consts.MsgFailedToSendRequest, Err: err}}
+		return nil, err
 	}
 
 	// Return the Response
@@ -0,0 +1,148 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicse3/gospotify/models"
+)
+
+// newAuthServer returns a server which accepts the requests carrying one of the given access tokens and rejects the others with a 401.
+// It records the number of requests and fails the test if a request has lost its body.
+func newAuthServer(t *testing.T, accepted ...string) (*httptest.Server, *atomic.Int32) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if body, _ := io.ReadAll(r.Body); r.Method == http.MethodPut && string(body) != `{"name":"body"}` {
+			t.Errorf("request body = %q, want the original one", body)
+		}
+
+		for _, accessToken := range accepted {
+			if r.Header.Get("Authorization") == "Bearer "+accessToken {
+				w.Write([]byte(`{}`))
+				return
+			}
+		}
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"error":{"status":401,"message":"The access token expired"}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &requests
+}
+
+// newAuthClient returns a client for the server with a valid access token "old", which is refreshed by the given function.
+func newAuthClient(server *httptest.Server, refresh func(n int32) (*models.AuthToken, error)) (*HttpClient, *atomic.Int32) {
+	var refreshes atomic.Int32
+	refresher := refresherFunc(func(context.Context, *models.AuthToken) (*models.AuthToken, error) {
+		return refresh(refreshes.Add(1))
+	})
+
+	authToken := &models.AuthToken{AccessToken: "old", RefreshToken: "refresh", ExpiryTime: time.Now().Add(time.Hour)}
+	hc := NewHttpClientWithRefresher(server.URL, authToken, refresher)
+	hc.SetRetryPolicy(NoRetryPolicy())
+
+	return hc, &refreshes
+}
+
+func TestHttpClientReplaysRejectedRequest(t *testing.T) {
+	server, requests := newAuthServer(t, "new")
+	hc, refreshes := newAuthClient(server, func(int32) (*models.AuthToken, error) {
+		return &models.AuthToken{AccessToken: "new", RefreshToken: "rotated", ExpiresIn: 3600}, nil
+	})
+
+	res, err := hc.Put(context.Background(), "/", nil, nil, map[string]string{"name": "body"})
+	if err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		t.Errorf("Put() status = %d, want 200", res.StatusCode)
+	}
+	if requests.Load() != 2 || refreshes.Load() != 1 {
+		t.Errorf("%d requests and %d refreshes, want 2 and 1", requests.Load(), refreshes.Load())
+	}
+	if got := Attempts(res); got != 1 {
+		t.Errorf("Attempts() = %d, want 1 as the replay isn't a retry", got)
+	}
+
+	// The rotated refresh token replaces the old one
+	if authToken := hc.TokenManager().Token(); authToken.AccessToken != "new" || authToken.RefreshToken != "rotated" {
+		t.Errorf("token = %q, %q after the refresh, want new, rotated", authToken.AccessToken, authToken.RefreshToken)
+	}
+}
+
+func TestHttpClientDoesNotReplayWithSameToken(t *testing.T) {
+	server, requests := newAuthServer(t)
+	hc, refreshes := newAuthClient(server, func(int32) (*models.AuthToken, error) {
+		return &models.AuthToken{AccessToken: "old", ExpiresIn: 3600}, nil
+	})
+
+	res, err := hc.Get(context.Background(), "/", nil)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Get() status = %d, want 401", res.StatusCode)
+	}
+	if requests.Load() != 1 || refreshes.Load() != 1 {
+		t.Errorf("%d requests and %d refreshes, want 1 and 1", requests.Load(), refreshes.Load())
+	}
+}
+
+func TestHttpClientReplaysOnlyOnce(t *testing.T) {
+	// Every refresh hands out a new token, which the server rejects as well
+	server, requests := newAuthServer(t)
+	hc, refreshes := newAuthClient(server, func(n int32) (*models.AuthToken, error) {
+		return &models.AuthToken{AccessToken: fmt.Sprintf("new-%d", n), ExpiresIn: 3600}, nil
+	})
+
+	res, err := hc.Get(context.Background(), "/", nil)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Get() status = %d, want 401", res.StatusCode)
+	}
+	if requests.Load() != 2 || refreshes.Load() != 1 {
+		t.Errorf("%d requests and %d refreshes, want 2 and 1", requests.Load(), refreshes.Load())
+	}
+}
+
+func TestHttpClientRejectedRefreshRequiresReauth(t *testing.T) {
+	server, requests := newAuthServer(t)
+	rejected := &Error{Type: AuthErrorType, Status: http.StatusBadRequest, Reason: ReasonInvalidGrant, Message: "Refresh token revoked"}
+	hc, _ := newAuthClient(server, func(int32) (*models.AuthToken, error) {
+		return nil, rejected
+	})
+	notified := make(chan error, 1)
+	hc.TokenManager().SetOnReauthRequired(func(err error) { notified <- err })
+
+	_, err := hc.Get(context.Background(), "/", nil)
+	if !errors.Is(err, ErrReauthRequired) {
+		t.Fatalf("Get() error = %v, want ErrReauthRequired", err)
+	}
+	if err := <-notified; !errors.Is(err, ErrReauthRequired) {
+		t.Errorf("OnReauthRequired got %v, want ErrReauthRequired", err)
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("%d requests, want 1", got)
+	}
+
+	// The rejected token is kept, it's up to the app to log in again
+	if authToken := hc.TokenManager().Token(); authToken.AccessToken != "old" {
+		t.Errorf("AccessToken = %q, want old", authToken.AccessToken)
+	}
+}
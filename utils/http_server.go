@@ -2,57 +2,252 @@ package utils
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net"
 	"net/http"
+	"net/url"
+	"strconv"
 
 	"github.com/alicse3/gospotify/consts"
 )
 
-// HttpServer interface defines the methods for starting the http server and listening for the /callback request.
-// Code received from the callback request can be communicated using a channel.
+const (
+	// address the callback server listens on if neither Addr nor a redirect url is given, on the loopback interface only
+	defaultCallbackAddr = "127.0.0.1:8080"
+	// path the callback server handles if neither Path nor a redirect url is given
+	defaultCallbackPath = "/callback"
+)
+
+// CallbackResult is the outcome of the authentication callback, either the authorization code or the error.
+type CallbackResult struct {
+	Code string
+	Err  error
+}
+
+// HttpServer interface defines the methods for starting the http server and listening for the callback request.
+// The server is derived from the redirect url, which may be empty if the credentials don't expose it.
+// StartServer returns once the server is listening, or with the error if it can't listen,
+// and serves in the background until the context is done.
+// It returns the redirect url the server actually listens on, which differs from the given one for an ephemeral port.
+// The state sent with the authorization url must be validated against the one in the callback request.
+// Code or error received from the callback request can be communicated using a channel.
 // Check DefaultHttpServer struct for implementation details.
 type HttpServer interface {
-	StartServer(ctx context.Context, ch chan string) error
+	StartServer(ctx context.Context, redirectUrl, state string, ch chan<- CallbackResult) (string, error)
 }
 
 // DefaultHttpServer is a struct that implements HttpServer interface.
-type DefaultHttpServer struct{}
+//
+// The listen address and the callback path are derived from the redirect url, unless they are set explicitly.
+// The server listens on the host of the redirect url only, never on all the interfaces, so it fails to listen
+// if the host isn't an address of this machine; set Addr to listen elsewhere, e.g. behind a reverse proxy.
+// Port 0 in a loopback redirect url, e.g. http://127.0.0.1:0/callback, picks a free ephemeral port.
+// A redirect url with the https scheme is served over HTTPS with the given certificate.
+type DefaultHttpServer struct {
+	// Address to listen on, e.g. "127.0.0.1:8080"
+	Addr string
+	// Path of the callback request, e.g. "/callback"
+	Path string
+	// Certificate and matching private key files in PEM format, required for an https redirect url
+	CertFile string
+	KeyFile  string
+}
 
 // StartServer starts an HTTP server to listen for the authentication callback.
-func (dhs *DefaultHttpServer) StartServer(ctx context.Context, ch chan string) error {
+// It runs until the context is done. The requests whose state doesn't match are answered with a 400
+// and ignored, so only the callback carrying the state of this login, with a code or an error, completes it.
+func (dhs *DefaultHttpServer) StartServer(ctx context.Context, redirectUrl, state string, ch chan<- CallbackResult) (string, error) {
+	// Derive the listener from the redirect url
+	addr, path, useTLS, err := dhs.listenConfig(redirectUrl)
+	if err != nil {
+		return "", err
+	}
+
+	// Bind the listener first, so the errors like a port clash are returned to the caller
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return "", err
+	}
+
+	// Fill in the actual port if an ephemeral one was requested
+	actualRedirectUrl, err := resolveRedirectUrl(redirectUrl, listener.Addr())
+	if err != nil {
+		listener.Close()
+		return "", err
+	}
+
 	// Create new serve mux
 	mux := http.NewServeMux()
 
-	// GET /callback to handle callback requests
-	mux.HandleFunc("/callback", func(w http.ResponseWriter, r *http.Request) {
-		// Get the code from the URL parameters
-		code := r.URL.Query().Get("code")
+	// GET <path> to handle callback requests
+	mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+		// Reject a request with another state without ending the login, e.g. a reload or a forged request,
+		// only the callback of the authorization url of this login completes it
+		query := r.URL.Query()
+		if query.Get("state") != state {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, consts.MsgStateMismatch)
+			return
+		}
+		result := ParseCallback(query, state)
 
-		// Send the code through channel
-		ch <- code
+		// Send the result through channel, unless nobody is waiting for it anymore
+		select {
+		case ch <- result:
+		case <-ctx.Done():
+		}
 
-		// Show success response
+		// Show the response
+		if result.Err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, consts.MsgLoginFailed)
+			return
+		}
 		w.WriteHeader(http.StatusOK)
 		fmt.Fprint(w, consts.MsgCodeReceived)
 	})
 
 	// Create a new server and pass the mux as the handler
-	server := http.Server{Addr: ":8080", Handler: mux}
+	server := &http.Server{Handler: mux}
 
 	go func() {
 		// Wait for the context to be done
 		<-ctx.Done()
 
 		// Shutdown the server gracefully
-		if err := server.Shutdown(context.Background()); err != nil {
-			panic(err)
+		server.Shutdown(context.Background())
+	}()
+
+	go func() {
+		// Serve the incoming requests
+		var err error
+		if useTLS {
+			err = server.ServeTLS(listener, dhs.CertFile, dhs.KeyFile)
+		} else {
+			err = server.Serve(listener)
+		}
+
+		// Report the errors other than the shutdown, e.g. an invalid certificate
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			select {
+			case ch <- CallbackResult{Err: err}:
+			case <-ctx.Done():
+			}
 		}
 	}()
 
-	// Start the server and listen for incoming requests
-	if err := server.ListenAndServe(); err != nil {
-		return err
+	return actualRedirectUrl, nil
+}
+
+// listenConfig returns the address to listen on, the callback path and whether to serve over HTTPS.
+func (dhs *DefaultHttpServer) listenConfig(redirectUrl string) (addr, path string, useTLS bool, err error) {
+	addr, path = defaultCallbackAddr, defaultCallbackPath
+
+	if redirectUrl != "" {
+		u, err := url.Parse(redirectUrl)
+		if err != nil {
+			return "", "", false, err
+		}
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return "", "", false, errors.New(consts.MsgInvalidRedirectUrl)
+		}
+		useTLS = u.Scheme == "https"
+
+		// Use the default port of the scheme if the redirect url has none
+		port := u.Port()
+		if port == "" {
+			port = "80"
+			if useTLS {
+				port = "443"
+			}
+		}
+
+		// Listen on the host of the redirect url only, an ephemeral port is only supported on the loopback interface
+		host := u.Hostname()
+		if !isLoopback(host) && port == "0" {
+			return "", "", false, errors.New(consts.MsgEphemeralPortRequiresLoopback)
+		}
+		addr = net.JoinHostPort(host, port)
+
+		path = u.Path
+		if path == "" {
+			path = "/"
+		}
+	}
+
+	// The explicitly configured values take precedence
+	if dhs.Addr != "" {
+		addr = dhs.Addr
+	}
+	if dhs.Path != "" {
+		path = dhs.Path
+	}
+
+	if useTLS && (dhs.CertFile == "" || dhs.KeyFile == "") {
+		return "", "", false, errors.New(consts.MsgCertificateRequired)
+	}
+
+	return addr, path, useTLS, nil
+}
+
+// resolveRedirectUrl replaces the ephemeral port 0 of the redirect url with the port of the listener.
+// Any other redirect url is returned as is, so it still matches the one registered for the app.
+func resolveRedirectUrl(redirectUrl string, addr net.Addr) (string, error) {
+	if redirectUrl == "" {
+		return "", nil
+	}
+
+	u, err := url.Parse(redirectUrl)
+	if err != nil {
+		return "", err
+	}
+	if u.Port() != "0" {
+		return redirectUrl, nil
+	}
+
+	tcpAddr, ok := addr.(*net.TCPAddr)
+	if !ok {
+		return "", errors.New(consts.MsgInvalidRedirectUrl)
+	}
+	u.Host = net.JoinHostPort(u.Hostname(), strconv.Itoa(tcpAddr.Port))
+
+	return u.String(), nil
+}
+
+// isLoopback reports whether the host refers to the loopback interface.
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// ParseCallback extracts the authorization code from the query parameters of the callback request.
+// For details, visit: https://developer.spotify.com/documentation/web-api/tutorials/code-flow
+//
+// If the user denied the access or the authorization failed, the error and error_description parameters
+// are returned as an Error of AuthErrorType. A state which doesn't match the expected one is rejected,
+// as the request didn't originate from the authorization url of this app.
+func ParseCallback(query url.Values, state string) CallbackResult {
+	// Validate the state first, so a forged request can't inject an error or a code
+	if query.Get("state") != state {
+		return CallbackResult{Err: NewError(http.StatusBadRequest, consts.MsgStateMismatch, nil)}
+	}
+
+	// Handle the authorization error, e.g. access_denied
+	if authErr := query.Get("error"); authErr != "" {
+		authError := &AuthenticationError{Err: authErr, Description: query.Get("error_description")}
+		return CallbackResult{Err: &Error{Type: AuthErrorType, Status: http.StatusBadRequest, Reason: authError.Err, Message: authError.Description, AuthError: authError}}
+	}
+
+	// Get the code from the URL parameters
+	code := query.Get("code")
+	if code == "" {
+		return CallbackResult{Err: NewError(http.StatusBadRequest, consts.MsgCodeNotFound, nil)}
 	}
 
-	return nil
+	return CallbackResult{Code: code}
 }
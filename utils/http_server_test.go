@@ -0,0 +1,91 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// startCallbackServer starts a DefaultHttpServer on an ephemeral loopback port and returns its redirect url.
+func startCallbackServer(t *testing.T, state string) (string, chan CallbackResult) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	ch := make(chan CallbackResult)
+	redirectUrl, err := (&DefaultHttpServer{}).StartServer(ctx, "http://127.0.0.1:0/callback", state, ch)
+	if err != nil {
+		t.Fatalf("StartServer() error = %v", err)
+	}
+
+	return redirectUrl, ch
+}
+
+// callback sends the callback request with the given query and returns the status of the response.
+// The result sent by the server, if any, is received concurrently, as the server waits for it to be received.
+func callback(t *testing.T, redirectUrl string, query url.Values, ch <-chan CallbackResult) (int, *CallbackResult) {
+	t.Helper()
+
+	status := make(chan int, 1)
+	go func() {
+		res, err := http.Get(redirectUrl + "?" + query.Encode())
+		if err != nil {
+			t.Errorf("GET callback error = %v", err)
+			status <- 0
+			return
+		}
+		res.Body.Close()
+		status <- res.StatusCode
+	}()
+
+	select {
+	case result := <-ch:
+		return <-status, &result
+	case s := <-status:
+		return s, nil
+	case <-time.After(5 * time.Second):
+		t.Fatal("callback request timed out")
+		return 0, nil
+	}
+}
+
+func TestDefaultHttpServerIgnoresStateMismatch(t *testing.T) {
+	redirectUrl, ch := startCallbackServer(t, "state")
+
+	// Requests with another state or without any are rejected, but don't end the login
+	for _, query := range []url.Values{
+		{"state": {"forged"}, "code": {"attacker"}},
+		{"state": {"forged"}, "error": {"access_denied"}},
+		{},
+	} {
+		status, result := callback(t, redirectUrl, query, ch)
+		if status != http.StatusBadRequest {
+			t.Errorf("status = %d for %v, want 400", status, query)
+		}
+		if result != nil {
+			t.Errorf("result = %+v for %v, want none", result, query)
+		}
+	}
+
+	// The real callback completes the login
+	status, result := callback(t, redirectUrl, url.Values{"state": {"state"}, "code": {"code"}}, ch)
+	if status != http.StatusOK || result == nil || result.Code != "code" || result.Err != nil {
+		t.Fatalf("status = %d, result = %+v, want 200 with the code", status, result)
+	}
+}
+
+func TestDefaultHttpServerReportsAuthorizationError(t *testing.T) {
+	redirectUrl, ch := startCallbackServer(t, "state")
+
+	status, result := callback(t, redirectUrl, url.Values{"state": {"state"}, "error": {"access_denied"}}, ch)
+	if status != http.StatusBadRequest || result == nil {
+		t.Fatalf("status = %d, result = %+v, want 400 with the error", status, result)
+	}
+
+	var e *Error
+	if !errors.As(result.Err, &e) || e.Type != AuthErrorType || e.Reason != "access_denied" {
+		t.Errorf("result error = %v, want access_denied of AuthErrorType", result.Err)
+	}
+}
@@ -0,0 +1,102 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httputil"
+	"regexp"
+	"time"
+)
+
+const (
+	// value logged in place of the secrets
+	redacted = "REDACTED"
+)
+
+var (
+	// headers which carry credentials
+	sensitiveHeaders = []string{"Authorization", "Cookie", "Set-Cookie"}
+	// credential headers in a dump
+	sensitiveHeaderPattern = regexp.MustCompile(`(?im)^((?:Authorization|Cookie|Set-Cookie):[ \t]*)[^\r\n]*`)
+	// tokens, secrets and codes in JSON bodies, form values and query strings of a dump
+	sensitiveValuePattern = regexp.MustCompile(`(?m)((?:^|[?&"\s{,])(?:access_token|refresh_token|client_secret|code_verifier|code)"?\s*[:=]\s*"?)[^"&\s]+`)
+)
+
+// logRequest logs the outcome of a request, including all its attempts.
+// Secrets are never logged: the query is left out of the url and the credential headers are redacted.
+func (hc *HttpClient) logRequest(ctx context.Context, req *http.Request, res *http.Response, err error, latency time.Duration) {
+	u := *req.URL
+	u.RawQuery = ""
+
+	// Log the headers of the last attempt, which include the ones set by the client
+	header := req.Header
+	if res != nil && res.Request != nil {
+		header = res.Request.Header
+	}
+
+	attrs := []slog.Attr{
+		slog.String("method", req.Method),
+		slog.String("url", u.String()),
+		slog.Duration("latency", latency),
+		slog.Group("headers", redactHeaders(header)...),
+	}
+
+	// Log the failures to send the request as errors
+	if err != nil {
+		attempts := 1
+		var e *Error
+		if errors.As(err, &e) {
+			attempts = e.Attempts
+		}
+		attrs = append(attrs, slog.Int("attempts", attempts), slog.Any("error", err))
+		hc.logger.LogAttrs(ctx, slog.LevelError, "spotify request failed", attrs...)
+		return
+	}
+
+	attrs = append(attrs, slog.Int("status", res.StatusCode), slog.Int("attempts", Attempts(res)))
+	hc.logger.LogAttrs(ctx, slog.LevelInfo, "spotify request", attrs...)
+}
+
+// dumpRequest logs the whole request along with its body at the debug level, with the secrets scrubbed.
+func (hc *HttpClient) dumpRequest(ctx context.Context, req *http.Request) {
+	dump, err := httputil.DumpRequestOut(req, true)
+	if err != nil {
+		hc.logger.DebugContext(ctx, "failed to dump spotify request", "error", err)
+		return
+	}
+	hc.logger.DebugContext(ctx, "spotify request dump", "dump", scrub(dump))
+}
+
+// dumpResponse logs the whole response along with its body at the debug level, with the secrets scrubbed.
+func (hc *HttpClient) dumpResponse(ctx context.Context, res *http.Response) {
+	dump, err := httputil.DumpResponse(res, true)
+	if err != nil {
+		hc.logger.DebugContext(ctx, "failed to dump spotify response", "error", err)
+		return
+	}
+	hc.logger.DebugContext(ctx, "spotify response dump", "dump", scrub(dump))
+}
+
+// redactHeaders returns the headers as log attributes, with the values of the credential headers redacted.
+func redactHeaders(header http.Header) []any {
+	attrs := make([]any, 0, len(header))
+	for key, values := range header {
+		value := any(values)
+		for _, sensitive := range sensitiveHeaders {
+			if http.CanonicalHeaderKey(key) == sensitive {
+				value = redacted
+			}
+		}
+		attrs = append(attrs, slog.Any(key, value))
+	}
+	return attrs
+}
+
+// scrub replaces the credential headers, tokens, secrets and codes in a dump.
+func scrub(dump []byte) string {
+	dump = sensitiveHeaderPattern.ReplaceAll(dump, []byte("${1}"+redacted))
+	dump = sensitiveValuePattern.ReplaceAll(dump, []byte("${1}"+redacted))
+	return string(dump)
+}
@@ -0,0 +1,32 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+)
+
+const (
+	// number of random bytes of a code verifier, which makes a 43 characters long verifier
+	codeVerifierLength = 32
+)
+
+// GenerateCodeVerifier generates a random code verifier for the Authorization Code with PKCE flow.
+// The verifier is 43 characters long and only contains letters, digits, "-" and "_", as required by RFC 7636.
+func GenerateCodeVerifier() (string, error) {
+	// Create a byte slice with the verifier length
+	bytes := make([]byte, codeVerifierLength)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+
+	// Convert the byte slice to an unpadded base64url-encoded string
+	return base64.RawURLEncoding.EncodeToString(bytes), nil
+}
+
+// CodeChallengeS256 returns the S256 code challenge for the given code verifier,
+// which is the unpadded base64url-encoded SHA256 hash of the verifier.
+func CodeChallengeS256(codeVerifier string) string {
+	hash := sha256.Sum256([]byte(codeVerifier))
+	return base64.RawURLEncoding.EncodeToString(hash[:])
+}
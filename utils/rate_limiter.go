@@ -0,0 +1,145 @@
+package utils
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// window used if the given one isn't positive, Spotify computes its rate limit over a rolling 30 seconds window
+	defaultRateLimitWindow = 30 * time.Second
+	// a 429 never slows the limiter down below this fraction of the configured rate
+	minRateFraction = 1.0 / 16
+	// fraction of the configured rate recovered by every response which isn't a 429
+	rateRecoveryFraction = 1.0 / 20
+)
+
+// RateLimiter paces the requests with a token bucket, so a burst of requests doesn't end up in a storm of 429 responses.
+// It lets the configured number of requests through per window, at most burst at once after being idle.
+//
+// It's adaptive: a 429 response halves the rate, down to 1/16 of the configured one, and holds every request back
+// until its Retry-After delay has passed. Every other response recovers 1/20 of the configured rate.
+//
+// It's safe for concurrent use, so a single RateLimiter can be shared by several clients, see HttpClient.SetRateLimiter.
+type RateLimiter struct {
+	// Configured and current rate, in requests per second
+	baseRate float64
+	rate     float64
+	burst    float64
+	// Tokens available at last, negative when the requests waiting for one are in debt
+	tokens float64
+	last   time.Time
+	// No request is let through before then, after a 429 response
+	pausedUntil time.Time
+	// Source of the current time, time.Now by default, and the wait until a later time
+	now   func() time.Time
+	sleep func(ctx context.Context, d time.Duration) error
+	mu    sync.Mutex
+}
+
+// NewRateLimiter creates a new RateLimiter which lets requests through per window, at most burst at once.
+// A window which isn't positive means 30 seconds, and requests and burst below 1 mean 1.
+func NewRateLimiter(requests int, window time.Duration, burst int) *RateLimiter {
+	if window <= 0 {
+		window = defaultRateLimitWindow
+	}
+	rate := float64(max(requests, 1)) / window.Seconds()
+
+	return &RateLimiter{
+		baseRate: rate,
+		rate:     rate,
+		burst:    float64(max(burst, 1)),
+		tokens:   float64(max(burst, 1)),
+		last:     time.Now(),
+		now:      time.Now,
+		sleep:    sleep,
+	}
+}
+
+// SetClock replaces the source of the current time, e.g. with a fake clock for testing. A nil clock means time.Now.
+// The bucket starts full again at the current time of the clock. The waits still take real time, so the clock should keep pace with it.
+func (rl *RateLimiter) SetClock(now func() time.Time) {
+	if now == nil {
+		now = time.Now
+	}
+
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	rl.now = now
+	rl.tokens, rl.last = rl.burst, now()
+}
+
+// Wait blocks until the next request may be sent, or until the context is done, whichever happens first.
+func (rl *RateLimiter) Wait(ctx context.Context) error {
+	// Reserve a token, going into debt if there is none left, and wait until the debt is paid off
+	rl.mu.Lock()
+	now := rl.now()
+	rl.refill(now)
+	rl.tokens--
+	wait := rl.last.Sub(now)
+	if rl.tokens < 0 {
+		wait += time.Duration(-rl.tokens / rl.rate * float64(time.Second))
+	}
+	rl.mu.Unlock()
+
+	if wait > 0 {
+		if err := rl.sleep(ctx, wait); err != nil {
+			// Give the token back for the others
+			rl.mu.Lock()
+			rl.tokens++
+			rl.mu.Unlock()
+			return err
+		}
+	}
+
+	// Hold the request back while the limiter is paused, it may have been paused during the wait
+	for {
+		rl.mu.Lock()
+		wait := rl.pausedUntil.Sub(rl.now())
+		rl.mu.Unlock()
+
+		if wait <= 0 {
+			return nil
+		}
+		if err := rl.sleep(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
+
+// observe adapts the rate to the given response: a 429 slows the limiter down, any other response lets it recover.
+func (rl *RateLimiter) observe(res *http.Response) {
+	if res == nil {
+		return
+	}
+
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	now := rl.now()
+	rl.refill(now)
+
+	if res.StatusCode != http.StatusTooManyRequests {
+		rl.rate = min(rl.rate+rl.baseRate*rateRecoveryFraction, rl.baseRate)
+		return
+	}
+
+	// Slow down, and pause until the delay requested by Spotify has passed, starting again with an empty bucket
+	rl.rate = max(rl.rate/2, rl.baseRate*minRateFraction)
+	if retryAfter, ok := parseRetryAfter(res.Header.Get("Retry-After")); ok && now.Add(retryAfter).After(rl.pausedUntil) {
+		rl.pausedUntil = now.Add(retryAfter)
+		rl.tokens = min(rl.tokens, 0)
+		rl.last = rl.pausedUntil
+	}
+}
+
+// refill adds the tokens earned since the last refill, up to the burst.
+func (rl *RateLimiter) refill(now time.Time) {
+	if now.After(rl.last) {
+		rl.tokens = min(rl.tokens+now.Sub(rl.last).Seconds()*rl.rate, rl.burst)
+		rl.last = now
+	}
+}
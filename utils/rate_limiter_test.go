@@ -0,0 +1,141 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+)
+
+// newTestRateLimiter creates a RateLimiter on the fake clock, whose waits advance the clock instead of sleeping.
+// The waits are recorded in the returned slice, and fail with the error of the context if it's done.
+func newTestRateLimiter(clock *fakeClock, requests int, window time.Duration, burst int) (*RateLimiter, *[]time.Duration) {
+	rl := NewRateLimiter(requests, window, burst)
+	rl.SetClock(clock.Now)
+
+	var waits []time.Duration
+	rl.sleep = func(ctx context.Context, d time.Duration) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		waits = append(waits, d)
+		clock.Advance(d)
+		return nil
+	}
+
+	return rl, &waits
+}
+
+func TestRateLimiterBurstThenPaces(t *testing.T) {
+	// 20 requests per second, i.e. one every 50ms, and 3 at once
+	clock := newFakeClock()
+	rl, waits := newTestRateLimiter(clock, 20, time.Second, 3)
+
+	for i := range 3 {
+		if err := rl.Wait(context.Background()); err != nil {
+			t.Fatalf("Wait() #%d error = %v", i+1, err)
+		}
+	}
+	if len(*waits) != 0 {
+		t.Fatalf("the burst waited %v, want no wait", *waits)
+	}
+
+	// The bucket is empty, the next requests wait for a token each
+	for range 2 {
+		if err := rl.Wait(context.Background()); err != nil {
+			t.Fatalf("Wait() error = %v", err)
+		}
+	}
+	if want := []time.Duration{50 * time.Millisecond, 50 * time.Millisecond}; !slices.Equal(*waits, want) {
+		t.Errorf("the requests after the burst waited %v, want %v", *waits, want)
+	}
+
+	// After being idle, the bucket is full again, but not beyond the burst
+	clock.Advance(time.Minute)
+	*waits = nil
+	for range 4 {
+		rl.Wait(context.Background())
+	}
+	if want := []time.Duration{50 * time.Millisecond}; !slices.Equal(*waits, want) {
+		t.Errorf("the requests after being idle waited %v, want %v", *waits, want)
+	}
+}
+
+func TestRateLimiterWaitCancelled(t *testing.T) {
+	// A token every 10 seconds, so the second request has to wait
+	clock := newFakeClock()
+	rl, waits := newTestRateLimiter(clock, 3, 30*time.Second, 1)
+	if err := rl.Wait(context.Background()); err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := rl.Wait(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Wait() error = %v, want context.Canceled", err)
+	}
+
+	// The reserved token is given back, so the next request doesn't pay for the cancelled one
+	clock.Advance(10 * time.Second)
+	if err := rl.Wait(context.Background()); err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+	if len(*waits) != 0 {
+		t.Errorf("the request after the cancelled one waited %v, want no wait", *waits)
+	}
+}
+
+func TestRateLimiterAdaptsToResponses(t *testing.T) {
+	rl := NewRateLimiter(100, time.Second, 10)
+	tooManyRequests := &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{}}
+	ok := &http.Response{StatusCode: http.StatusOK, Header: http.Header{}}
+
+	// Every 429 halves the rate, down to 1/16 of the configured one
+	rl.observe(tooManyRequests)
+	if rl.rate != 50 {
+		t.Errorf("rate = %v after a 429, want 50", rl.rate)
+	}
+	for range 10 {
+		rl.observe(tooManyRequests)
+	}
+	if want := 100 * minRateFraction; rl.rate != want {
+		t.Errorf("rate = %v after many 429s, want %v", rl.rate, want)
+	}
+
+	// Every other response recovers a fraction of the configured rate, up to the configured one
+	rl.observe(ok)
+	if want := 100*minRateFraction + 100*rateRecoveryFraction; rl.rate != want {
+		t.Errorf("rate = %v after a 200, want %v", rl.rate, want)
+	}
+	for range 50 {
+		rl.observe(ok)
+	}
+	if rl.rate != 100 {
+		t.Errorf("rate = %v after many 200s, want 100", rl.rate)
+	}
+}
+
+func TestRateLimiterPausesOnRetryAfter(t *testing.T) {
+	clock := newFakeClock()
+	rl, waits := newTestRateLimiter(clock, 1000, time.Second, 10)
+	res := &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{}}
+	res.Header.Set("Retry-After", "1")
+	rl.observe(res)
+
+	// The request is held back until the Retry-After delay has passed, and then waits for a token of the halved rate
+	if err := rl.Wait(context.Background()); err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+	if want := []time.Duration{time.Second + 2*time.Millisecond}; !slices.Equal(*waits, want) {
+		t.Errorf("the request waited %v during the pause, want %v", *waits, want)
+	}
+
+	// The pause is over
+	*waits = nil
+	clock.Advance(time.Second)
+	if err := rl.Wait(context.Background()); err != nil || len(*waits) != 0 {
+		t.Errorf("Wait() after the pause = %v after waiting %v, want no wait", err, *waits)
+	}
+}
@@ -0,0 +1,44 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/alicse3/gospotify/consts"
+)
+
+// DecodeResponse handles the response of a Spotify API call and always closes its body.
+// All the 2xx statuses (200 OK, 201 Created, 202 Accepted, 204 No Content etc.) are treated as success,
+// and the JSON body is decoded into v only if there is one, so v is left untouched for an empty body.
+// v may be nil to discard the body. Any other status is returned as an *Error of the given type, see ParseSpotifyError.
+func DecodeResponse(res *http.Response, errorType ErrorType, v any) error {
+	defer res.Body.Close()
+
+	// Handle Spotify API error
+	if !IsSuccess(res) {
+		return ParseSpotifyError(res, errorType)
+	}
+
+	// Read the response body, even if it's discarded, so the connection can be reused
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		return NewError(http.StatusInternalServerError, consts.MsgFailedToReadResponseBody, err)
+	}
+	if v == nil || len(bytes.TrimSpace(data)) == 0 {
+		return nil
+	}
+
+	// Unmarshal the response data into v
+	if err := json.Unmarshal(data, v); err != nil {
+		return NewError(http.StatusInternalServerError, consts.MsgFailedToUnmarshalResponseData, err)
+	}
+
+	return nil
+}
+
+// IsSuccess reports whether the response has a 2xx status.
+func IsSuccess(res *http.Response) bool {
+	return res.StatusCode >= 200 && res.StatusCode < 300
+}
@@ -0,0 +1,157 @@
+package utils
+
+import (
+	"context"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// default number of attempts for a request, including the first one
+	defaultRetryMaxAttempts = 3
+	// default delay before the first retry of a 5xx or network error
+	defaultRetryBaseDelay = 500 * time.Millisecond
+	// default upper bound of a single wait between attempts
+	defaultRetryMaxDelay = 30 * time.Second
+)
+
+// RetryPolicy defines when and how failed requests are retried.
+//
+// A 429 response is retried after the delay given by its Retry-After header.
+// 502, 503 and 504 responses and network errors are retried using exponential backoff with full jitter.
+// By default only idempotent methods (GET, PUT, DELETE, HEAD, OPTIONS) are retried.
+type RetryPolicy struct {
+	// Maximum number of attempts including the first one. Values below 2 disable retrying.
+	MaxAttempts int
+	// Delay before the first backoff retry. It is doubled on every following attempt.
+	BaseDelay time.Duration
+	// Upper bound of a single wait. A Retry-After above this value is not waited for and the response is returned as is.
+	MaxDelay time.Duration
+	// Retry non-idempotent methods (POST) as well.
+	RetryNonIdempotent bool
+}
+
+// DefaultRetryPolicy returns the RetryPolicy used by new HttpClient instances.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: defaultRetryMaxAttempts,
+		BaseDelay:   defaultRetryBaseDelay,
+		MaxDelay:    defaultRetryMaxDelay,
+	}
+}
+
+// NoRetryPolicy returns a RetryPolicy which sends every request exactly once.
+func NoRetryPolicy() RetryPolicy {
+	return RetryPolicy{MaxAttempts: 1}
+}
+
+// canRetry reports whether the request with the given method may be retried at all.
+func (rp RetryPolicy) canRetry(method string) bool {
+	if rp.MaxAttempts < 2 {
+		return false
+	}
+
+	switch method {
+	case http.MethodGet, http.MethodPut, http.MethodDelete, http.MethodHead, http.MethodOptions:
+		return true
+	default:
+		return rp.RetryNonIdempotent
+	}
+}
+
+// delay returns how long to wait before the next attempt and whether a retry should happen at all.
+// attempt is the number of the attempt which just failed, starting at 1.
+func (rp RetryPolicy) delay(attempt int, res *http.Response, err error) (time.Duration, bool) {
+	if attempt >= rp.MaxAttempts {
+		return 0, false
+	}
+
+	// Network errors and gateway errors are retried with backoff
+	if err != nil {
+		return rp.backoff(attempt), true
+	}
+
+	switch res.StatusCode {
+	case http.StatusTooManyRequests:
+		wait, ok := parseRetryAfter(res.Header.Get("Retry-After"))
+		if !ok {
+			return rp.backoff(attempt), true
+		}
+		if rp.MaxDelay > 0 && wait > rp.MaxDelay {
+			return 0, false
+		}
+		return wait, true
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return rp.backoff(attempt), true
+	default:
+		return 0, false
+	}
+}
+
+// backoff computes an exponential backoff delay with full jitter for the given attempt.
+func (rp RetryPolicy) backoff(attempt int) time.Duration {
+	ceiling := rp.BaseDelay << (attempt - 1)
+	if ceiling <= 0 || (rp.MaxDelay > 0 && ceiling > rp.MaxDelay) {
+		ceiling = rp.MaxDelay
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+
+	return rand.N(ceiling + 1)
+}
+
+// parseRetryAfter parses the Retry-After header, which is either a number of seconds or an HTTP date.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0), true
+	}
+
+	return 0, false
+}
+
+// sleep waits for the given duration or until the context is done, whichever happens first.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// attemptsKey is the context key under which the attempt number is stored on each sent request.
+type attemptsKey struct{}
+
+// Attempts returns the number of attempts it took to get the given response.
+func Attempts(res *http.Response) int {
+	if res == nil || res.Request == nil {
+		return 1
+	}
+
+	if attempts, ok := res.Request.Context().Value(attemptsKey{}).(int); ok {
+		return attempts
+	}
+
+	return 1
+}
+
+// discard drains and closes the response body, so the underlying connection can be reused.
+func discard(res *http.Response) {
+	io.Copy(io.Discard, res.Body)
+	res.Body.Close()
+}
@@ -0,0 +1,157 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicyCanRetry(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy RetryPolicy
+		method string
+		want   bool
+	}{
+		{"get", DefaultRetryPolicy(), http.MethodGet, true},
+		{"put", DefaultRetryPolicy(), http.MethodPut, true},
+		{"delete", DefaultRetryPolicy(), http.MethodDelete, true},
+		{"post", DefaultRetryPolicy(), http.MethodPost, false},
+		{"post retrying non-idempotent", RetryPolicy{MaxAttempts: 3, RetryNonIdempotent: true}, http.MethodPost, true},
+		{"no retry", NoRetryPolicy(), http.MethodGet, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.canRetry(tt.method); got != tt.want {
+				t.Errorf("canRetry(%s) = %v, want %v", tt.method, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryPolicyDelay(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3, BaseDelay: 100 * time.Millisecond, MaxDelay: 10 * time.Second}
+	response := func(status int, retryAfter string) *http.Response {
+		res := &http.Response{StatusCode: status, Header: http.Header{}}
+		if retryAfter != "" {
+			res.Header.Set("Retry-After", retryAfter)
+		}
+		return res
+	}
+
+	tests := []struct {
+		name      string
+		attempt   int
+		res       *http.Response
+		err       error
+		wantRetry bool
+		// Exact delay if maxWait is 0, otherwise the delay is up to maxWait
+		wantWait time.Duration
+		maxWait  time.Duration
+	}{
+		{name: "retry after", attempt: 1, res: response(http.StatusTooManyRequests, "2"), wantRetry: true, wantWait: 2 * time.Second},
+		{name: "retry after over max delay", attempt: 1, res: response(http.StatusTooManyRequests, "60"), wantRetry: false},
+		{name: "429 without retry after", attempt: 1, res: response(http.StatusTooManyRequests, ""), wantRetry: true, maxWait: 100 * time.Millisecond},
+		{name: "service unavailable", attempt: 2, res: response(http.StatusServiceUnavailable, ""), wantRetry: true, maxWait: 200 * time.Millisecond},
+		{name: "network error", attempt: 1, err: errors.New("connection reset"), wantRetry: true, maxWait: 100 * time.Millisecond},
+		{name: "not found", attempt: 1, res: response(http.StatusNotFound, ""), wantRetry: false},
+		{name: "internal server error", attempt: 1, res: response(http.StatusInternalServerError, ""), wantRetry: false},
+		{name: "last attempt", attempt: 3, res: response(http.StatusServiceUnavailable, ""), wantRetry: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wait, retry := policy.delay(tt.attempt, tt.res, tt.err)
+			if retry != tt.wantRetry {
+				t.Fatalf("delay() retry = %v, want %v", retry, tt.wantRetry)
+			}
+			if !retry {
+				return
+			}
+			if tt.maxWait == 0 && wait != tt.wantWait {
+				t.Errorf("delay() wait = %v, want %v", wait, tt.wantWait)
+			}
+			if tt.maxWait > 0 && (wait < 0 || wait > tt.maxWait) {
+				t.Errorf("delay() wait = %v, want between 0 and %v", wait, tt.maxWait)
+			}
+		})
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	if wait, ok := parseRetryAfter("3"); !ok || wait != 3*time.Second {
+		t.Errorf("parseRetryAfter(3) = %v, %v, want 3s", wait, ok)
+	}
+	if _, ok := parseRetryAfter(""); ok {
+		t.Error("parseRetryAfter() of an empty value is ok, want not ok")
+	}
+	if _, ok := parseRetryAfter("soon"); ok {
+		t.Error("parseRetryAfter(soon) is ok, want not ok")
+	}
+
+	// An HTTP date in the past means no wait
+	if wait, ok := parseRetryAfter(time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat)); !ok || wait != 0 {
+		t.Errorf("parseRetryAfter() of a past date = %v, %v, want 0", wait, ok)
+	}
+}
+
+// newFlakyServer returns a server which responds with 503 to the given number of requests, and with 200 afterwards.
+func newFlakyServer(t *testing.T, failures int32) (*httptest.Server, *atomic.Int32) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) <= failures {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &requests
+}
+
+func TestHttpClientRetriesFailedRequests(t *testing.T) {
+	server, requests := newFlakyServer(t, 2)
+	hc := NewHttpClient(server.URL)
+	hc.SetRetryPolicy(RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond})
+
+	res, err := hc.Get(context.Background(), "/", nil)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		t.Errorf("Get() status = %d, want 200", res.StatusCode)
+	}
+	if got := requests.Load(); got != 3 {
+		t.Errorf("%d requests, want 3", got)
+	}
+	if got := Attempts(res); got != 3 {
+		t.Errorf("Attempts() = %d, want 3", got)
+	}
+}
+
+func TestHttpClientDoesNotRetryPost(t *testing.T) {
+	server, requests := newFlakyServer(t, 2)
+	hc := NewHttpClient(server.URL)
+	hc.SetRetryPolicy(RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond})
+
+	res, err := hc.Post(context.Background(), "/", nil, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("Post() error = %v", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("Post() status = %d, want 503", res.StatusCode)
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("%d requests, want 1", got)
+	}
+}
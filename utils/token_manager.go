@@ -0,0 +1,312 @@
+package utils
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/alicse3/gospotify/consts"
+	"github.com/alicse3/gospotify/models"
+)
+
+const (
+	// DefaultTokenRefreshMargin is how long before its expiry the access token is refreshed by default,
+	// so the requests which are in flight at the boundary don't fail with a 401.
+	DefaultTokenRefreshMargin = time.Minute
+)
+
+// TokenManager holds the AuthToken of a client and refreshes it on demand.
+// The access token is refreshed a margin before its ExpiryTime, and the concurrent requests needing a refresh
+// share a single call to the token endpoint. It's safe for concurrent use.
+type TokenManager struct {
+	// Current token, replaced as a whole on every refresh and never modified in place
+	authToken *models.AuthToken
+	// Hash identifying the user of the token, kept across the refreshes even if the refresh token rotates
+	identity string
+	// For refreshing the tokens, they're never refreshed if it's nil
+	refresher TokenRefresher
+	// For persisting the refreshed tokens
+	tokenStore TokenStore
+	// How long before its expiry the access token is refreshed
+	margin time.Duration
+	// Source of the current time, time.Now by default
+	now func() time.Time
+	// Called when the refresh token is rejected, see SetOnReauthRequired
+	onReauthRequired func(err error)
+	// Refresh in flight, if any
+	refreshing *tokenRefresh
+	mu         sync.Mutex
+}
+
+// tokenRefresh is a refresh in flight, whose outcome is available once done is closed.
+type tokenRefresh struct {
+	done chan struct{}
+	// Context of the caller which started the refresh
+	ctx context.Context
+	err error
+}
+
+// NewTokenManager creates a new TokenManager for the given token, which refreshes it with the given refresher.
+// If refresher is nil, the token is never refreshed and is sent as is once it has expired.
+// The refresh margin is DefaultTokenRefreshMargin, or the one of the refresher if it implements the RefreshMarginer interface.
+func NewTokenManager(authToken *models.AuthToken, refresher TokenRefresher) *TokenManager {
+	// Use the margin of the refresher, if it needs another one
+	margin := DefaultTokenRefreshMargin
+	if marginer, ok := refresher.(RefreshMarginer); ok {
+		margin = max(marginer.RefreshMargin(), 0)
+	}
+
+	return &TokenManager{
+		authToken: authToken,
+		identity:  tokenIdentity(authToken),
+		refresher: refresher,
+		margin:    margin,
+		now:       time.Now,
+	}
+}
+
+// SetTokenStore sets the store which every refreshed token is saved to.
+func (tm *TokenManager) SetTokenStore(tokenStore TokenStore) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	tm.tokenStore = tokenStore
+}
+
+// SetOnReauthRequired sets the function called when refreshing the token fails because the refresh token has been revoked
+// or has expired, i.e. the error matches ErrReauthRequired. The user has to log in again then, e.g. a long-running daemon
+// may alert about it or run the login flow and replace the token with SetToken. The function is called once per failed refresh,
+// after the requests waiting for the refresh have been given the error.
+func (tm *TokenManager) SetOnReauthRequired(onReauthRequired func(err error)) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	tm.onReauthRequired = onReauthRequired
+}
+
+// SetRefreshMargin sets how long before its expiry the access token is refreshed, DefaultTokenRefreshMargin by default.
+// A negative margin means 0, i.e. the token is only refreshed once it has expired.
+func (tm *TokenManager) SetRefreshMargin(margin time.Duration) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	tm.margin = max(margin, 0)
+}
+
+// SetClock replaces the source of the current time, e.g. with a fake clock for testing. A nil clock means time.Now.
+func (tm *TokenManager) SetClock(now func() time.Time) {
+	if now == nil {
+		now = time.Now
+	}
+
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	tm.now = now
+}
+
+// Token returns a copy of the current token, or nil if there is none. It never refreshes the token.
+func (tm *TokenManager) Token() *models.AuthToken {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	if tm.authToken == nil {
+		return nil
+	}
+
+	authToken := *tm.authToken
+	return &authToken
+}
+
+// userIdentity returns the hash identifying the user of the token, which stays the same across the refreshes.
+// It changes only when the token is replaced with SetToken, e.g. by logging in again, possibly as another user.
+func (tm *TokenManager) userIdentity() string {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	return tm.identity
+}
+
+// SetToken replaces the current token, e.g. with the one obtained by logging in again, and persists it.
+func (tm *TokenManager) SetToken(authToken *models.AuthToken) error {
+	if authToken == nil {
+		return NewError(http.StatusInternalServerError, consts.MsgAuthTokenNotInitialised, nil)
+	}
+	replaced := *authToken
+
+	tm.mu.Lock()
+	tm.authToken = &replaced
+	tm.identity = tokenIdentity(&replaced)
+	tokenStore := tm.tokenStore
+	tm.mu.Unlock()
+
+	// Persist the new token
+	if tokenStore != nil {
+		if err := tokenStore.Save(&replaced); err != nil {
+			return NewError(http.StatusInternalServerError, consts.MsgFailedToSaveToken, err)
+		}
+	}
+
+	return nil
+}
+
+// AccessToken returns the current access token, refreshing it first if it expires within the refresh margin.
+// The given context is used for the call to the token endpoint, so cancelling it aborts the refresh as well.
+func (tm *TokenManager) AccessToken(ctx context.Context) (string, error) {
+	for {
+		tm.mu.Lock()
+		if tm.authToken == nil {
+			tm.mu.Unlock()
+			return "", NewError(http.StatusInternalServerError, consts.MsgAuthTokenNotInitialised, nil)
+		}
+
+		// Use the current token unless it's about to expire and can be refreshed.
+		// Without a refresher, the expired token is sent as is and the Spotify API rejects it.
+		if tm.refresher == nil || tm.now().Add(tm.margin).Before(tm.authToken.ExpiryTime) {
+			accessToken := tm.authToken.AccessToken
+			tm.mu.Unlock()
+			return accessToken, nil
+		}
+
+		refresh := tm.startRefresh(ctx)
+		tm.mu.Unlock()
+
+		err := refresh.wait(ctx)
+		if err == nil {
+			// Use the refreshed token even if it expires within the margin, rather than refreshing it again
+			return tm.Token().AccessToken, nil
+		}
+
+		// Try again with our own context if the caller which started the refresh gave up on it
+		if refresh.ctx.Err() == nil || ctx.Err() != nil {
+			return "", err
+		}
+	}
+}
+
+// Refresh refreshes the access token regardless of its expiry, e.g. after it has been revoked.
+// If a refresh is already in flight, it waits for that one instead of starting another.
+func (tm *TokenManager) Refresh(ctx context.Context) error {
+	tm.mu.Lock()
+	if tm.refresher == nil {
+		tm.mu.Unlock()
+		return NewError(http.StatusInternalServerError, consts.MsgTokenRefreshNotSupported, nil)
+	}
+	if tm.authToken == nil {
+		tm.mu.Unlock()
+		return NewError(http.StatusInternalServerError, consts.MsgAuthTokenNotInitialised, nil)
+	}
+	refresh := tm.startRefresh(ctx)
+	tm.mu.Unlock()
+
+	return refresh.wait(ctx)
+}
+
+// canRefresh reports whether the token can be refreshed.
+func (tm *TokenManager) canRefresh() bool {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	return tm.refresher != nil && tm.authToken != nil
+}
+
+// refreshRejected refreshes the access token after the Spotify API has rejected it,
+// unless it has been replaced meanwhile, e.g. by a concurrent request which was rejected as well.
+func (tm *TokenManager) refreshRejected(ctx context.Context, accessToken string) error {
+	tm.mu.Lock()
+	if tm.authToken != nil && tm.authToken.AccessToken != accessToken {
+		tm.mu.Unlock()
+		return nil
+	}
+	tm.mu.Unlock()
+
+	return tm.Refresh(ctx)
+}
+
+// startRefresh returns the refresh in flight, or starts a new one with the given context. tm.mu must be held.
+func (tm *TokenManager) startRefresh(ctx context.Context) *tokenRefresh {
+	if tm.refreshing != nil {
+		return tm.refreshing
+	}
+
+	refresh := &tokenRefresh{done: make(chan struct{}), ctx: ctx}
+	tm.refreshing = refresh
+	go tm.refresh(refresh, tm.authToken)
+
+	return refresh
+}
+
+// refresh gets a new token from the token endpoint, replaces the current one with it and persists it.
+func (tm *TokenManager) refresh(refresh *tokenRefresh, current *models.AuthToken) {
+	authToken, err := tm.refresher.RefreshToken(refresh.ctx, current)
+	if err != nil {
+		tm.mu.Lock()
+		tm.refreshing = nil
+		onReauthRequired := tm.onReauthRequired
+		tm.mu.Unlock()
+
+		refresh.err = NewError(http.StatusInternalServerError, consts.MsgFailedToRefreshTokens, err)
+		close(refresh.done)
+
+		// Let the app know the user has to log in again
+		if onReauthRequired != nil && errors.Is(err, ErrReauthRequired) {
+			onReauthRequired(refresh.err)
+		}
+		return
+	}
+	defer close(refresh.done)
+
+	// Spotify may rotate the refresh token, the current one is kept if the response has none
+	refreshed := *authToken
+	if refreshed.RefreshToken == "" {
+		refreshed.RefreshToken = current.RefreshToken
+	}
+
+	tm.mu.Lock()
+	refreshed.ExpiryTime = tm.now().Add(time.Duration(refreshed.ExpiresIn) * time.Second)
+	tm.refreshing = nil
+	// Keep the token set with SetToken meanwhile, which is newer
+	if tm.authToken != current {
+		tm.mu.Unlock()
+		return
+	}
+	tm.authToken = &refreshed
+	tokenStore := tm.tokenStore
+	tm.mu.Unlock()
+
+	// Persist the refreshed token, along with the rotated refresh token
+	if tokenStore != nil {
+		if err := tokenStore.Save(&refreshed); err != nil {
+			refresh.err = NewError(http.StatusInternalServerError, consts.MsgFailedToSaveToken, err)
+		}
+	}
+}
+
+// wait blocks until the refresh is done, or until the context is done, whichever happens first.
+func (tr *tokenRefresh) wait(ctx context.Context) error {
+	select {
+	case <-tr.done:
+		return tr.err
+	case <-ctx.Done():
+		return NewError(http.StatusInternalServerError, consts.MsgFailedToRefreshTokens, ctx.Err())
+	}
+}
+
+// tokenIdentity returns the hash of the refresh token, or of the access token if there is none, for identifying the user.
+func tokenIdentity(authToken *models.AuthToken) string {
+	if authToken == nil {
+		return ""
+	}
+
+	identity := authToken.RefreshToken
+	if identity == "" {
+		identity = authToken.AccessToken
+	}
+
+	sum := sha256.Sum256([]byte(identity))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,237 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicse3/gospotify/models"
+)
+
+// fakeClock is a clock which only moves when it's advanced.
+type fakeClock struct {
+	now time.Time
+	mu  sync.Mutex
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (fc *fakeClock) Now() time.Time {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+
+	return fc.now
+}
+
+func (fc *fakeClock) Advance(d time.Duration) {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+
+	fc.now = fc.now.Add(d)
+}
+
+// fakeRefresher hands out the access tokens access-1, access-2 and so on, without a refresh token.
+type fakeRefresher struct {
+	calls atomic.Int32
+	// Receives a value whenever a refresh starts, if not nil
+	started chan struct{}
+	// Blocks the refreshes until it's closed, if not nil
+	release chan struct{}
+	// Returned instead of a token, if not nil
+	err error
+}
+
+func (fr *fakeRefresher) RefreshToken(ctx context.Context, _ *models.AuthToken) (*models.AuthToken, error) {
+	n := fr.calls.Add(1)
+	if fr.started != nil {
+		fr.started <- struct{}{}
+	}
+
+	if fr.release != nil {
+		select {
+		case <-fr.release:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	if fr.err != nil {
+		return nil, fr.err
+	}
+
+	return &models.AuthToken{AccessToken: fmt.Sprintf("access-%d", n), ExpiresIn: 3600}, nil
+}
+
+func newTestTokenManager(clock *fakeClock, expiresIn time.Duration, refresher TokenRefresher) *TokenManager {
+	authToken := &models.AuthToken{AccessToken: "access-0", RefreshToken: "refresh", ExpiryTime: clock.Now().Add(expiresIn)}
+	tm := NewTokenManager(authToken, refresher)
+	tm.SetClock(clock.Now)
+
+	return tm
+}
+
+func TestTokenManagerRefreshesWithinMargin(t *testing.T) {
+	clock := newFakeClock()
+	refresher := &fakeRefresher{}
+	tm := newTestTokenManager(clock, 2*time.Minute, refresher)
+	tm.SetRefreshMargin(time.Minute)
+
+	// Outside the margin, the current token is used
+	accessToken, err := tm.AccessToken(context.Background())
+	if err != nil {
+		t.Fatalf("AccessToken() error = %v", err)
+	}
+	if accessToken != "access-0" || refresher.calls.Load() != 0 {
+		t.Fatalf("AccessToken() = %q after %d refreshes, want access-0 without a refresh", accessToken, refresher.calls.Load())
+	}
+
+	// Inside the margin, the token is refreshed before it expires
+	clock.Advance(61 * time.Second)
+	accessToken, err = tm.AccessToken(context.Background())
+	if err != nil {
+		t.Fatalf("AccessToken() error = %v", err)
+	}
+	if accessToken != "access-1" || refresher.calls.Load() != 1 {
+		t.Fatalf("AccessToken() = %q after %d refreshes, want access-1 after 1", accessToken, refresher.calls.Load())
+	}
+
+	// The refresh token is kept when the response has none, and the expiry follows the fake clock
+	authToken := tm.Token()
+	if authToken.RefreshToken != "refresh" {
+		t.Errorf("RefreshToken = %q, want refresh", authToken.RefreshToken)
+	}
+	if want := clock.Now().Add(time.Hour); !authToken.ExpiryTime.Equal(want) {
+		t.Errorf("ExpiryTime = %v, want %v", authToken.ExpiryTime, want)
+	}
+
+	// The refreshed token is used until it gets within the margin again
+	clock.Advance(58 * time.Minute)
+	if accessToken, _ := tm.AccessToken(context.Background()); accessToken != "access-1" {
+		t.Errorf("AccessToken() = %q, want access-1", accessToken)
+	}
+}
+
+func TestTokenManagerSharesConcurrentRefresh(t *testing.T) {
+	clock := newFakeClock()
+	refresher := &fakeRefresher{started: make(chan struct{}, 10), release: make(chan struct{})}
+	tm := newTestTokenManager(clock, -time.Minute, refresher)
+
+	const callers = 50
+	var wg sync.WaitGroup
+	results := make([]string, callers)
+	errs := make([]error, callers)
+	for i := range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], errs[i] = tm.AccessToken(context.Background())
+		}()
+	}
+
+	// Let the refresh complete once it has started
+	<-refresher.started
+	close(refresher.release)
+	wg.Wait()
+
+	if calls := refresher.calls.Load(); calls != 1 {
+		t.Fatalf("%d refreshes, want 1", calls)
+	}
+	for i := range callers {
+		if errs[i] != nil || results[i] != "access-1" {
+			t.Errorf("caller %d: AccessToken() = %q, %v, want access-1", i, results[i], errs[i])
+		}
+	}
+}
+
+func TestTokenManagerHandsOverCancelledRefresh(t *testing.T) {
+	clock := newFakeClock()
+	refresher := &fakeRefresher{started: make(chan struct{}, 10), release: make(chan struct{})}
+	tm := newTestTokenManager(clock, -time.Minute, refresher)
+
+	// The first caller starts the refresh with its own context
+	leaderCtx, cancel := context.WithCancel(context.Background())
+	leaderErr := make(chan error, 1)
+	go func() {
+		_, err := tm.AccessToken(leaderCtx)
+		leaderErr <- err
+	}()
+	<-refresher.started
+
+	// The next caller waits for the same refresh
+	type result struct {
+		accessToken string
+		err         error
+	}
+	follower := make(chan result, 1)
+	go func() {
+		accessToken, err := tm.AccessToken(context.Background())
+		follower <- result{accessToken, err}
+	}()
+
+	// Giving up on the first refresh fails the first caller only
+	cancel()
+	if err := <-leaderErr; !errors.Is(err, context.Canceled) {
+		t.Fatalf("first caller: AccessToken() error = %v, want context.Canceled", err)
+	}
+
+	// The next caller starts a refresh of its own, which completes
+	<-refresher.started
+	close(refresher.release)
+	got := <-follower
+	if got.err != nil || got.accessToken != "access-2" {
+		t.Fatalf("next caller: AccessToken() = %q, %v, want access-2", got.accessToken, got.err)
+	}
+	if calls := refresher.calls.Load(); calls != 2 {
+		t.Errorf("%d refreshes, want 2", calls)
+	}
+}
+
+func TestTokenManagerReauthRequired(t *testing.T) {
+	clock := newFakeClock()
+	rejected := &Error{Type: AuthErrorType, Status: http.StatusBadRequest, Reason: ReasonInvalidGrant}
+	tm := newTestTokenManager(clock, -time.Minute, &fakeRefresher{err: rejected})
+
+	// The function is called after the callers have been given the error
+	notified := make(chan error, 1)
+	tm.SetOnReauthRequired(func(err error) { notified <- err })
+
+	_, err := tm.AccessToken(context.Background())
+	if !errors.Is(err, ErrReauthRequired) {
+		t.Fatalf("AccessToken() error = %v, want ErrReauthRequired", err)
+	}
+	if err := <-notified; !errors.Is(err, ErrReauthRequired) {
+		t.Errorf("OnReauthRequired got %v, want ErrReauthRequired", err)
+	}
+}
+
+// marginRefresher is a fakeRefresher asking for its own refresh margin.
+type marginRefresher struct {
+	fakeRefresher
+	margin time.Duration
+}
+
+func (mr *marginRefresher) RefreshMargin() time.Duration {
+	return mr.margin
+}
+
+func TestTokenManagerUsesMarginOfRefresher(t *testing.T) {
+	clock := newFakeClock()
+	refresher := &marginRefresher{margin: 5 * time.Second}
+	tm := newTestTokenManager(clock, 10*time.Second, refresher)
+
+	// Within the default margin but outside the one of the refresher, the current token is used
+	if accessToken, _ := tm.AccessToken(context.Background()); accessToken != "access-0" {
+		t.Fatalf("AccessToken() = %q, want access-0", accessToken)
+	}
+
+	clock.Advance(6 * time.Second)
+	if accessToken, _ := tm.AccessToken(context.Background()); accessToken != "access-1" {
+		t.Errorf("AccessToken() = %q within the margin of the refresher, want access-1", accessToken)
+	}
+}
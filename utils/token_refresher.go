@@ -0,0 +1,160 @@
+package utils
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/alicse3/gospotify/consts"
+	"github.com/alicse3/gospotify/models"
+)
+
+// TokenRefresher interface defines the methods for refreshing an expired AuthToken.
+// Check ClientSecretRefresher, PKCERefresher and ClientCredentialsRefresher structs for implementation details.
+type TokenRefresher interface {
+	RefreshToken(ctx context.Context, authToken *models.AuthToken) (*models.AuthToken, error)
+}
+
+// RefreshMarginer interface is implemented by the TokenRefreshers which need another refresh margin than DefaultTokenRefreshMargin,
+// e.g. because they hand back the current token until shortly before it expires. NewTokenManager starts with their margin.
+type RefreshMarginer interface {
+	RefreshMargin() time.Duration
+}
+
+// ClientSecretRefresher is a struct that implements TokenRefresher interface for the tokens obtained with the Authorization Code flow.
+type ClientSecretRefresher struct {
+	ClientId     string
+	ClientSecret string
+	// Client for the Accounts API, a default one is used if it's nil
+	HttpClient *HttpClient
+}
+
+// RefreshToken refreshes the access token using the refresh token, authenticating with the client id and client secret.
+func (csr *ClientSecretRefresher) RefreshToken(ctx context.Context, authToken *models.AuthToken) (*models.AuthToken, error) {
+	// To make sure the dependencies are not empty before refreshing the tokens
+	if csr.ClientId == "" {
+		return nil, NewError(http.StatusInternalServerError, consts.MsgEmptyClientId, nil)
+	}
+	if csr.ClientSecret == "" {
+		return nil, NewError(http.StatusInternalServerError, consts.MsgEmptyClientSecret, nil)
+	}
+	if authToken == nil {
+		return nil, NewError(http.StatusInternalServerError, consts.MsgAuthTokenNotInitialised, nil)
+	}
+
+	// Generating base64 endoded(client id and client secret) string for authorization.
+	// For details, visit: https://developer.spotify.com/documentation/web-api/tutorials/refreshing-tokens
+	base64Encoded := base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%s:%s", csr.ClientId, csr.ClientSecret)))
+
+	// Set the required headers
+	headers := map[string]string{
+		"Content-Type":  "application/x-www-form-urlencoded",
+		"Authorization": "Basic " + base64Encoded, // As per the Spotify document, this is only required for the Authorization Code
+	}
+
+	// Set the form values for the token refresh request
+	formValues := map[string]string{
+		"grant_type":    "refresh_token",
+		"refresh_token": authToken.RefreshToken,
+	}
+
+	return RequestToken(ctx, csr.HttpClient, headers, formValues)
+}
+
+// PKCERefresher is a struct that implements TokenRefresher interface for the tokens obtained with the Authorization Code with PKCE flow.
+// No client secret is involved, the client id is sent along with the refresh token instead.
+type PKCERefresher struct {
+	ClientId string
+	// Client for the Accounts API, a default one is used if it's nil
+	HttpClient *HttpClient
+}
+
+// RefreshToken refreshes the access token using the refresh token and the client id.
+func (pr *PKCERefresher) RefreshToken(ctx context.Context, authToken *models.AuthToken) (*models.AuthToken, error) {
+	// To make sure the dependencies are not empty before refreshing the tokens
+	if pr.ClientId == "" {
+		return nil, NewError(http.StatusInternalServerError, consts.MsgEmptyClientId, nil)
+	}
+	if authToken == nil {
+		return nil, NewError(http.StatusInternalServerError, consts.MsgAuthTokenNotInitialised, nil)
+	}
+
+	// Set the required headers
+	headers := map[string]string{
+		"Content-Type": "application/x-www-form-urlencoded",
+	}
+
+	// Set the form values for the token refresh request.
+	// For details, visit: https://developer.spotify.com/documentation/web-api/tutorials/refreshing-tokens
+	formValues := map[string]string{
+		"grant_type":    "refresh_token",
+		"refresh_token": authToken.RefreshToken,
+		"client_id":     pr.ClientId,
+	}
+
+	return RequestToken(ctx, pr.HttpClient, headers, formValues)
+}
+
+// ClientCredentialsRefresher is a struct that implements TokenRefresher interface for the Client Credentials flow.
+// There is no refresh token in this flow, a new app token is requested with the client id and client secret instead.
+// The app tokens don't give access to any user information, only to the Spotify catalog.
+type ClientCredentialsRefresher struct {
+	ClientId     string
+	ClientSecret string
+	// Client for the Accounts API, a default one is used if it's nil
+	HttpClient *HttpClient
+}
+
+// RefreshToken requests a new app token using the client id and client secret. The given authToken is ignored and may be nil.
+func (ccr *ClientCredentialsRefresher) RefreshToken(ctx context.Context, _ *models.AuthToken) (*models.AuthToken, error) {
+	// To make sure the dependencies are not empty before requesting the token
+	if ccr.ClientId == "" {
+		return nil, NewError(http.StatusInternalServerError, consts.MsgEmptyClientId, nil)
+	}
+	if ccr.ClientSecret == "" {
+		return nil, NewError(http.StatusInternalServerError, consts.MsgEmptyClientSecret, nil)
+	}
+
+	// Generating base64 endoded(client id and client secret) string for authorization.
+	// For details, visit: https://developer.spotify.com/documentation/web-api/tutorials/client-credentials-flow
+	base64Encoded := base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%s:%s", ccr.ClientId, ccr.ClientSecret)))
+
+	// Set the required headers
+	headers := map[string]string{
+		"Content-Type":  "application/x-www-form-urlencoded",
+		"Authorization": "Basic " + base64Encoded,
+	}
+
+	// Set the form values for the token request
+	formValues := map[string]string{
+		"grant_type": "client_credentials",
+	}
+
+	return RequestToken(ctx, ccr.HttpClient, headers, formValues)
+}
+
+// RequestToken makes a POST request with the given headers and form values to the token endpoint of the Accounts API
+// and returns the AuthToken from the response. If httpClient is nil, a default client for the Accounts API is used.
+func RequestToken(ctx context.Context, httpClient *HttpClient, headers, formValues map[string]string) (*models.AuthToken, error) {
+	if httpClient == nil {
+		httpClient = NewHttpClient(consts.BaseUrlAccounts)
+	}
+
+	// Make a POST request to the token endpoint
+	res, err := httpClient.Post(ctx, consts.EndpointToken, headers, nil, formValues, nil)
+	if err != nil {
+		return nil, WrapError(http.StatusInternalServerError, consts.MsgPostCallFailed, err)
+	}
+
+	// Handle Spotify API error and decode the response data into AuthToken struct
+	var authToken models.AuthToken
+	if err := DecodeResponse(res, AuthErrorType, &authToken); err != nil {
+		return nil, err
+	}
+	authToken.SetExpiryTime()
+
+	// Return the AuthToken
+	return &authToken, nil
+}
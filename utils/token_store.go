@@ -0,0 +1,130 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/alicse3/gospotify/models"
+)
+
+// TokenStore interface defines the methods for persisting the AuthToken across the runs of an app.
+// Load returns nil without an error if no token has been saved yet.
+// Check FileTokenStore and MemoryTokenStore structs for implementation details.
+type TokenStore interface {
+	Load() (*models.AuthToken, error)
+	Save(authToken *models.AuthToken) error
+}
+
+// FileTokenStore is a struct that implements TokenStore interface by keeping the token in a JSON file.
+// The file is only readable and writable by the current user, and is replaced atomically on every save.
+type FileTokenStore struct {
+	path string
+}
+
+// NewFileTokenStore creates a new FileTokenStore which keeps the token in the file at the given path.
+// The directory of the file is created on the first save if it doesn't exist.
+func NewFileTokenStore(path string) *FileTokenStore {
+	return &FileTokenStore{path}
+}
+
+// Load reads the token from the file. It returns nil without an error if the file doesn't exist.
+func (fts *FileTokenStore) Load() (*models.AuthToken, error) {
+	// Read the file
+	data, err := os.ReadFile(fts.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal the file data into AuthToken struct
+	var authToken models.AuthToken
+	if err := json.Unmarshal(data, &authToken); err != nil {
+		return nil, err
+	}
+
+	return &authToken, nil
+}
+
+// Save writes the token to a temporary file next to the target file and then renames it,
+// so the file never holds a partially written token.
+func (fts *FileTokenStore) Save(authToken *models.AuthToken) error {
+	// Marshal the token to JSON
+	data, err := json.Marshal(authToken)
+	if err != nil {
+		return err
+	}
+
+	// Make sure the directory exists
+	dir := filepath.Dir(fts.path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+
+	// Write the token to a temporary file, which is created with 0600 permissions
+	tmp, err := os.CreateTemp(dir, filepath.Base(fts.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	// Replace the target file with the temporary file
+	return os.Rename(tmp.Name(), fts.path)
+}
+
+// MemoryTokenStore is a struct that implements TokenStore interface by keeping the token in memory.
+// It's safe for concurrent use, and useful for sharing a token between clients of the same process and for testing.
+type MemoryTokenStore struct {
+	authToken *models.AuthToken
+	mu        sync.Mutex
+}
+
+// NewMemoryTokenStore creates a new empty MemoryTokenStore.
+func NewMemoryTokenStore() *MemoryTokenStore {
+	return &MemoryTokenStore{}
+}
+
+// Load returns a copy of the saved token, or nil if no token has been saved yet.
+func (mts *MemoryTokenStore) Load() (*models.AuthToken, error) {
+	mts.mu.Lock()
+	defer mts.mu.Unlock()
+
+	if mts.authToken == nil {
+		return nil, nil
+	}
+
+	authToken := *mts.authToken
+	return &authToken, nil
+}
+
+// Save keeps a copy of the given token.
+func (mts *MemoryTokenStore) Save(authToken *models.AuthToken) error {
+	mts.mu.Lock()
+	defer mts.mu.Unlock()
+
+	if authToken == nil {
+		mts.authToken = nil
+		return nil
+	}
+
+	copied := *authToken
+	mts.authToken = &copied
+	return nil
+}